@@ -0,0 +1,775 @@
+// Package porkbuntest provides an in-memory fake of the Porkbun JSON API for tests and the mock mode of the provider.
+//
+// The server understands the DNS, DNSSEC, glue, URL forwarding, nameserver, domain listing, pricing
+// and SSL endpoints,
+// checks credentials like the real API and can simulate the 503 responses Porkbun sends
+// when an account is rate limited.
+package porkbuntest
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	APIKey    = "pk1_foobarbaz"
+	SecretKey = "sk1_foobarbaz"
+)
+
+// Record mirrors the record objects returned by /dns/retrieve. Name is the full name including the domain.
+type Record struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	TTL     string `json:"ttl"`
+	Prio    string `json:"prio"`
+	Notes   string `json:"notes"`
+}
+
+type URLForward struct {
+	ID          string `json:"id"`
+	Subdomain   string `json:"subdomain"`
+	Location    string `json:"location"`
+	Type        string `json:"type"`
+	IncludePath string `json:"includePath"`
+	Wildcard    string `json:"wildcard"`
+}
+
+// GlueHost is a nameserver host registered under a domain, Host is fully qualified
+type GlueHost struct {
+	Host string
+	IPv4 []string
+	IPv6 []string
+}
+
+type DNSSECRecord struct {
+	KeyTag     string `json:"keyTag"`
+	Alg        string `json:"alg"`
+	DigestType string `json:"digestType"`
+	Digest     string `json:"digest"`
+}
+
+type Domain struct {
+	Name        string
+	Records     []Record
+	Nameservers []string
+	URLForwards []URLForward
+	Glue        []GlueHost
+	DNSSEC      []DNSSECRecord
+	Labels      []string
+	ExpireDate  string
+}
+
+// Server is a running fake. All methods are safe to call while requests are in flight.
+type Server struct {
+	URL string
+
+	server *httptest.Server
+
+	mu        sync.Mutex
+	domains   map[string]*Domain
+	nextID    int
+	calls     map[string]int
+	limit     int
+	window    time.Duration
+	requests  []time.Time
+	anyDomain bool
+	stateFile string
+}
+
+// state is what Persist saves of a server
+type state struct {
+	NextID  int                `json:"next_id"`
+	Domains map[string]*Domain `json:"domains"`
+}
+
+type Option func(*Server)
+
+// WithDomain adds a domain owned by the account, records are given with their subdomain as Name
+func WithDomain(name string, records ...Record) Option {
+	return func(s *Server) {
+		d := s.addDomain(name)
+		for _, record := range records {
+			s.createRecord(d, record)
+		}
+	}
+}
+
+// WithNameservers sets the nameservers of domain, adding the domain if it wasn't yet
+func WithNameservers(domain string, nameservers ...string) Option {
+	return func(s *Server) {
+		d, ok := s.domains[strings.ToLower(domain)]
+		if !ok {
+			d = s.addDomain(domain)
+		}
+		d.Nameservers = append([]string{}, nameservers...)
+	}
+}
+
+// WithLabels adds labels with the given titles to domain, adding the domain if it wasn't yet
+func WithLabels(domain string, labels ...string) Option {
+	return func(s *Server) {
+		d, ok := s.domains[strings.ToLower(domain)]
+		if !ok {
+			d = s.addDomain(domain)
+		}
+		d.Labels = append(d.Labels, labels...)
+	}
+}
+
+// WithRateLimit makes the server answer with a 503 once more than limit requests arrive within window
+func WithRateLimit(limit int, window time.Duration) Option {
+	return func(s *Server) {
+		s.limit = limit
+		s.window = window
+	}
+}
+
+// WithAnyDomain makes every domain count as owned by the account, unknown domains are added with
+// default nameservers and no records when they are first used
+func WithAnyDomain() Option {
+	return func(s *Server) {
+		s.anyDomain = true
+	}
+}
+
+// NewServer starts the fake on a local port
+func NewServer(opts ...Option) *Server {
+	s := NewUnstartedServer(opts...)
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	return s
+}
+
+// NewUnstartedServer returns the fake without listening anywhere, serve it through its ServeHTTP method.
+// The endpoints are expected at the root, like "/dns/retrieve/foobar.dev".
+func NewUnstartedServer(opts ...Option) *Server {
+	s := &Server{
+		domains: map[string]*Domain{},
+		nextID:  100000000,
+		calls:   map[string]int{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Server) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// Persist loads the domains saved at path, if there are any, and saves them there after every request
+// from now on. It lets the fake outlive the process, like a provider that is restarted between plan and apply.
+func (s *Server) Persist(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("reading state: %w", err)
+	default:
+		var saved state
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return fmt.Errorf("decoding state %s: %w", path, err)
+		}
+		for name, d := range saved.Domains {
+			s.domains[name] = d
+		}
+		s.nextID = max(s.nextID, saved.NextID)
+	}
+
+	s.stateFile = path
+	return s.save()
+}
+
+func (s *Server) save() error {
+	if s.stateFile == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(state{NextID: s.nextID, Domains: s.domains}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.stateFile), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so an interrupted save doesn't leave half a state behind
+	tmp := s.stateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.stateFile)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.stateFile == "" {
+		s.handle(w, req)
+		return
+	}
+
+	// The response is held back until the change it reports has been saved
+	rec := httptest.NewRecorder()
+	s.handle(rec, req)
+
+	s.mu.Lock()
+	err := s.save()
+	s.mu.Unlock()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Saving state failed: %s", err))
+		return
+	}
+
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(rec.Body.Bytes())
+}
+
+// Records returns a copy of the records currently stored for domain
+func (s *Server) Records(domain string) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[strings.ToLower(domain)]
+	if !ok {
+		return nil
+	}
+	return append([]Record{}, d.Records...)
+}
+
+// Nameservers returns the nameservers currently set for domain
+func (s *Server) Nameservers(domain string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[strings.ToLower(domain)]
+	if !ok {
+		return nil
+	}
+	return append([]string{}, d.Nameservers...)
+}
+
+// URLForwards returns the URL forwards currently set for domain
+func (s *Server) URLForwards(domain string) []URLForward {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[strings.ToLower(domain)]
+	if !ok {
+		return nil
+	}
+	return append([]URLForward{}, d.URLForwards...)
+}
+
+// Glue returns the glue hosts currently registered for domain
+func (s *Server) Glue(domain string) []GlueHost {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[strings.ToLower(domain)]
+	if !ok {
+		return nil
+	}
+	return append([]GlueHost{}, d.Glue...)
+}
+
+// DNSSECRecords returns the DS records currently published for domain
+func (s *Server) DNSSECRecords(domain string) []DNSSECRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.domains[strings.ToLower(domain)]
+	if !ok {
+		return nil
+	}
+	return append([]DNSSECRecord{}, d.DNSSEC...)
+}
+
+// Calls returns how often an endpoint like "dns/retrieve" has been called, including rate limited calls
+func (s *Server) Calls(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[endpoint]
+}
+
+func (s *Server) addDomain(name string) *Domain {
+	name = strings.ToLower(name)
+	d := &Domain{
+		Name: name,
+		Nameservers: []string{
+			"curitiba.ns.porkbun.com",
+			"fortaleza.ns.porkbun.com",
+			"maceio.ns.porkbun.com",
+			"salvador.ns.porkbun.com",
+		},
+		ExpireDate: "2030-01-01 00:00:00",
+	}
+	s.domains[name] = d
+	return d
+}
+
+func (s *Server) createRecord(d *Domain, record Record) Record {
+	s.nextID++
+	record.ID = strconv.Itoa(s.nextID)
+	record.Name = fullName(record.Name, d.Name)
+	record.Type = strings.ToUpper(record.Type)
+	if record.TTL == "" {
+		record.TTL = "600"
+	}
+	if record.Prio == "" {
+		record.Prio = "0"
+	}
+	d.Records = append(d.Records, record)
+	return record
+}
+
+func fullName(subdomain string, domain string) string {
+	if subdomain == "" {
+		return domain
+	}
+	return strings.ToLower(subdomain) + "." + domain
+}
+
+type request struct {
+	APIKey       string   `json:"apikey"`
+	SecretAPIKey string   `json:"secretapikey"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Content      string   `json:"content"`
+	TTL          string   `json:"ttl"`
+	Prio         string   `json:"prio"`
+	Notes        string   `json:"notes"`
+	NS           []string `json:"ns"`
+	Subdomain    string   `json:"subdomain"`
+	Location     string   `json:"location"`
+	IncludePath  string   `json:"includePath"`
+	Wildcard     string   `json:"wildcard"`
+	IPs          []string `json:"ips"`
+	KeyTag       string   `json:"keyTag"`
+	Alg          string   `json:"alg"`
+	DigestType   string   `json:"digestType"`
+	Digest       string   `json:"digest"`
+}
+
+func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) < 2 && (len(parts) != 1 || parts[0] != "ping") {
+		writeError(w, http.StatusNotFound, "Invalid endpoint.")
+		return
+	}
+
+	endpoint := parts[0]
+	if len(parts) > 1 {
+		endpoint += "/" + parts[1]
+	}
+	s.calls[endpoint]++
+
+	if s.rateLimited() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, "Rate limit exceeded")
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "All API requests must be POST.")
+		return
+	}
+
+	// Pricing is public, the real API doesn't require keys for it
+	if endpoint == "pricing/get" {
+		writeSuccess(w, map[string]any{"pricing": map[string]any{
+			"com": map[string]any{"registration": "9.73", "renewal": "9.73", "transfer": "9.73", "coupons": []any{}},
+			"dev": map[string]any{"registration": "10.81", "renewal": "10.81", "transfer": "10.81"},
+			"xyz": map[string]any{"registration": "2.04", "renewal": "12.98", "transfer": "12.98", "coupons": map[string]any{
+				"registration": map[string]any{"code": "AWESOMENESS", "max_per_user": 1, "first_year_only": "yes", "type": "amount", "amount": 1},
+			}},
+		}})
+		return
+	}
+
+	var body request
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON.")
+		return
+	}
+	if body.APIKey != APIKey || body.SecretAPIKey != SecretKey {
+		writeError(w, http.StatusForbidden, "Invalid API key. (002)")
+		return
+	}
+
+	if endpoint == "ping" {
+		writeSuccess(w, map[string]any{"yourIp": "127.0.0.1"})
+		return
+	}
+
+	args := parts[2:]
+	switch endpoint {
+	case "domain/listAll":
+		s.listAll(w)
+		return
+	}
+
+	if len(args) == 0 {
+		writeError(w, http.StatusBadRequest, "Domain is required.")
+		return
+	}
+	d, ok := s.domains[strings.ToLower(args[0])]
+	if !ok && s.anyDomain {
+		d, ok = s.addDomain(args[0]), true
+	}
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid domain.")
+		return
+	}
+	args = args[1:]
+
+	switch endpoint {
+	case "dns/create":
+		if body.Type == "" || body.Content == "" {
+			writeError(w, http.StatusBadRequest, "Type and content are required.")
+			return
+		}
+		record := s.createRecord(d, Record{
+			Name:    body.Name,
+			Type:    body.Type,
+			Content: body.Content,
+			TTL:     body.TTL,
+			Prio:    body.Prio,
+			Notes:   body.Notes,
+		})
+		id, _ := strconv.Atoi(record.ID)
+		writeSuccess(w, map[string]any{"id": id})
+	case "dns/edit":
+		i := recordIndex(d, args)
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid record ID.")
+			return
+		}
+		record := &d.Records[i]
+		record.Name = fullName(body.Name, d.Name)
+		record.Type = strings.ToUpper(body.Type)
+		record.Content = body.Content
+		record.TTL = body.TTL
+		if record.TTL == "" {
+			record.TTL = "600"
+		}
+		record.Prio = body.Prio
+		if record.Prio == "" {
+			record.Prio = "0"
+		}
+		record.Notes = body.Notes
+		writeSuccess(w, nil)
+	case "dns/delete":
+		i := recordIndex(d, args)
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid record ID.")
+			return
+		}
+		d.Records = append(d.Records[:i], d.Records[i+1:]...)
+		writeSuccess(w, nil)
+	case "dns/retrieve":
+		records := d.Records
+		if len(args) > 0 {
+			records = nil
+			if i := recordIndex(d, args); i >= 0 {
+				records = []Record{d.Records[i]}
+			}
+		}
+		writeSuccess(w, map[string]any{"records": nonNil(records)})
+	case "dns/retrieveByNameType":
+		if len(args) == 0 {
+			writeError(w, http.StatusBadRequest, "Type is required.")
+			return
+		}
+		records := []Record{}
+		for _, i := range recordsByNameType(d, args) {
+			records = append(records, d.Records[i])
+		}
+		writeSuccess(w, map[string]any{"records": records})
+	case "dns/editByNameType":
+		matches := recordsByNameType(d, args)
+		if len(matches) == 0 {
+			writeError(w, http.StatusBadRequest, "No records found.")
+			return
+		}
+		for _, i := range matches {
+			record := &d.Records[i]
+			record.Content = body.Content
+			record.TTL = body.TTL
+			if record.TTL == "" {
+				record.TTL = "600"
+			}
+			record.Prio = body.Prio
+			if record.Prio == "" {
+				record.Prio = "0"
+			}
+			record.Notes = body.Notes
+		}
+		writeSuccess(w, nil)
+	case "dns/deleteByNameType":
+		matches := recordsByNameType(d, args)
+		for j := len(matches) - 1; j >= 0; j-- {
+			i := matches[j]
+			d.Records = append(d.Records[:i], d.Records[i+1:]...)
+		}
+		writeSuccess(w, nil)
+	case "dns/createDnssecRecord":
+		if body.KeyTag == "" || body.Alg == "" || body.DigestType == "" || body.Digest == "" {
+			writeError(w, http.StatusBadRequest, "keyTag, alg, digestType and digest are required.")
+			return
+		}
+		d.DNSSEC = append(d.DNSSEC, DNSSECRecord{KeyTag: body.KeyTag, Alg: body.Alg, DigestType: body.DigestType, Digest: body.Digest})
+		writeSuccess(w, nil)
+	case "dns/getDnssecRecords":
+		// Like the real API an empty set is a list, otherwise an object keyed by key tag
+		if len(d.DNSSEC) == 0 {
+			writeSuccess(w, map[string]any{"records": []string{}})
+			return
+		}
+		records := map[string]DNSSECRecord{}
+		for _, record := range d.DNSSEC {
+			records[record.KeyTag] = record
+		}
+		writeSuccess(w, map[string]any{"records": records})
+	case "dns/deleteDnssecRecord":
+		for i, record := range d.DNSSEC {
+			if len(args) > 0 && record.KeyTag == args[0] {
+				d.DNSSEC = append(d.DNSSEC[:i], d.DNSSEC[i+1:]...)
+				writeSuccess(w, nil)
+				return
+			}
+		}
+		writeError(w, http.StatusBadRequest, "Invalid key tag.")
+	case "domain/createGlue", "domain/updateGlue":
+		if len(args) == 0 || len(body.IPs) == 0 {
+			writeError(w, http.StatusBadRequest, "Glue host and IPs are required.")
+			return
+		}
+		host := GlueHost{Host: fullName(args[0], d.Name)}
+		for _, ip := range body.IPs {
+			if strings.Contains(ip, ":") {
+				host.IPv6 = append(host.IPv6, ip)
+			} else {
+				host.IPv4 = append(host.IPv4, ip)
+			}
+		}
+		i := glueIndex(d, host.Host)
+		switch {
+		case endpoint == "domain/createGlue" && i >= 0:
+			writeError(w, http.StatusBadRequest, "Glue host already exists.")
+			return
+		case endpoint == "domain/updateGlue" && i < 0:
+			writeError(w, http.StatusBadRequest, "Invalid glue host.")
+			return
+		case i >= 0:
+			d.Glue[i] = host
+		default:
+			d.Glue = append(d.Glue, host)
+		}
+		writeSuccess(w, nil)
+	case "domain/deleteGlue":
+		i := -1
+		if len(args) > 0 {
+			i = glueIndex(d, fullName(args[0], d.Name))
+		}
+		if i < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid glue host.")
+			return
+		}
+		d.Glue = append(d.Glue[:i], d.Glue[i+1:]...)
+		writeSuccess(w, nil)
+	case "domain/getGlue":
+		hosts := make([][]any, 0, len(d.Glue))
+		for _, host := range d.Glue {
+			hosts = append(hosts, []any{host.Host, map[string][]string{"v4": nonNil(host.IPv4), "v6": nonNil(host.IPv6)}})
+		}
+		writeSuccess(w, map[string]any{"hosts": hosts})
+	case "domain/getNs":
+		writeSuccess(w, map[string]any{"ns": d.Nameservers})
+	case "domain/updateNs":
+		if len(body.NS) == 0 {
+			writeError(w, http.StatusBadRequest, "Name servers are required.")
+			return
+		}
+		d.Nameservers = append([]string{}, body.NS...)
+		writeSuccess(w, nil)
+	case "domain/addUrlForward":
+		s.nextID++
+		d.URLForwards = append(d.URLForwards, URLForward{
+			ID:          strconv.Itoa(s.nextID),
+			Subdomain:   body.Subdomain,
+			Location:    body.Location,
+			Type:        body.Type,
+			IncludePath: body.IncludePath,
+			Wildcard:    body.Wildcard,
+		})
+		writeSuccess(w, nil)
+	case "domain/getUrlForwarding":
+		writeSuccess(w, map[string]any{"forwards": nonNil(d.URLForwards)})
+	case "domain/deleteUrlForward":
+		for i, forward := range d.URLForwards {
+			if len(args) > 0 && forward.ID == args[0] {
+				d.URLForwards = append(d.URLForwards[:i], d.URLForwards[i+1:]...)
+				writeSuccess(w, nil)
+				return
+			}
+		}
+		writeError(w, http.StatusBadRequest, "Invalid forward ID.")
+	case "ssl/retrieve":
+		// Like Porkbun's, the chain holds the certificate of the domain followed by the intermediate
+		writeSuccess(w, map[string]any{
+			"certificatechain": fakePEM("CERTIFICATE", "certificate of "+d.Name) + fakePEM("CERTIFICATE", "intermediate"),
+			"privatekey":       fakePEM("PRIVATE KEY", "private key of "+d.Name),
+			"publickey":        fakePEM("PUBLIC KEY", "public key of "+d.Name),
+		})
+	default:
+		writeError(w, http.StatusNotFound, "Invalid endpoint.")
+	}
+}
+
+func (s *Server) listAll(w http.ResponseWriter) {
+	names := make([]string, 0, len(s.domains))
+	for name := range s.domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	domains := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		d := s.domains[name]
+		labels := make([]map[string]string, 0, len(d.Labels))
+		for i, label := range d.Labels {
+			labels = append(labels, map[string]string{"id": strconv.Itoa(i + 1), "title": label})
+		}
+		domains = append(domains, map[string]any{
+			"domain":       d.Name,
+			"status":       "ACTIVE",
+			"tld":          d.Name[strings.LastIndex(d.Name, ".")+1:],
+			"createDate":   "2020-01-01 00:00:00",
+			"expireDate":   d.ExpireDate,
+			"securityLock": "1",
+			"whoisPrivacy": "1",
+			"autoRenew":    "1",
+			"notLocal":     0,
+			"labels":       labels,
+		})
+	}
+	writeSuccess(w, map[string]any{"domains": domains})
+}
+
+// rateLimited records the request and reports whether it exceeds the configured limit
+func (s *Server) rateLimited() bool {
+	if s.limit <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	recent := s.requests[:0]
+	for _, t := range s.requests {
+		if now.Sub(t) < s.window {
+			recent = append(recent, t)
+		}
+	}
+	s.requests = recent
+
+	if len(s.requests) >= s.limit {
+		return true
+	}
+	s.requests = append(s.requests, now)
+	return false
+}
+
+func recordIndex(d *Domain, args []string) int {
+	if len(args) == 0 {
+		return -1
+	}
+	for i, record := range d.Records {
+		if record.ID == args[0] {
+			return i
+		}
+	}
+	return -1
+}
+
+// recordsByNameType returns the indexes of the records matching the type and optional subdomain in args
+func recordsByNameType(d *Domain, args []string) []int {
+	if len(args) == 0 {
+		return nil
+	}
+	subdomain := ""
+	if len(args) > 1 {
+		subdomain = args[1]
+	}
+	name := fullName(subdomain, d.Name)
+
+	var matches []int
+	for i, record := range d.Records {
+		if record.Name == name && record.Type == strings.ToUpper(args[0]) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func glueIndex(d *Domain, host string) int {
+	for i, glue := range d.Glue {
+		if glue.Host == host {
+			return i
+		}
+	}
+	return -1
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func writeSuccess(w http.ResponseWriter, fields map[string]any) {
+	body := map[string]any{"status": "SUCCESS"}
+	for k, v := range fields {
+		body[k] = v
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status":  "ERROR",
+		"message": message,
+	})
+}
+
+// fakePEM encodes content as a PEM block of blockType, it isn't a real key or certificate
+func fakePEM(blockType string, content string) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: []byte(content)}))
+}
@@ -0,0 +1,166 @@
+package porkbuntest
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, s *Server) *porkbunapi.Client {
+	client := porkbunapi.New(APIKey, SecretKey)
+	var err error
+	client.BaseURL, err = url.Parse(s.URL)
+	require.NoError(t, err)
+	return client
+}
+
+func Test_RecordLifecycle(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	s := NewServer(WithDomain("foobar.dev", Record{Name: "", Type: "A", Content: "0.0.0.1"}))
+	defer s.Close()
+	client := newClient(t, s)
+
+	_, err := client.Ping(ctx)
+	r.NoError(err)
+
+	id, err := client.CreateRecord(ctx, "foobar.dev", porkbunapi.Record{Name: "www", Type: "cname", Content: "foobar.dev"})
+	r.NoError(err)
+
+	records, err := client.RetrieveRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Len(records, 2)
+	r.Equal("www.foobar.dev", records[1].Name)
+	r.Equal("CNAME", records[1].Type)
+	r.Equal("600", records[1].TTL)
+
+	r.NoError(client.EditRecord(ctx, "foobar.dev", id, porkbunapi.Record{Name: "www", Type: "CNAME", Content: "example.com", TTL: "3600"}))
+	r.Equal("example.com", s.Records("foobar.dev")[1].Content)
+	r.Equal("3600", s.Records("foobar.dev")[1].TTL)
+
+	r.NoError(client.DeleteRecord(ctx, "foobar.dev", id))
+	r.Len(s.Records("foobar.dev"), 1)
+
+	r.Error(client.DeleteRecord(ctx, "foobar.dev", id))
+	_, err = client.RetrieveRecords(ctx, "unknown.dev")
+	r.Error(err)
+
+	r.Equal(2, s.Calls("dns/retrieve"))
+}
+
+func Test_WithNameservers(t *testing.T) {
+	r := require.New(t)
+	s := NewServer(WithDomain("foobar.dev"), WithNameservers("FooBar.dev", "ns1.example.net"), WithNameservers("other.dev", "ns2.example.net"))
+	defer s.Close()
+
+	r.Equal([]string{"ns1.example.net"}, s.Nameservers("foobar.dev"))
+	r.Equal([]string{"ns2.example.net"}, s.Nameservers("other.dev"))
+
+	domains, err := newClient(t, s).ListDomains(context.Background())
+	r.NoError(err)
+	r.Len(domains, 2)
+}
+
+func Test_RejectsBadCredentials(t *testing.T) {
+	s := NewServer(WithDomain("foobar.dev"))
+	defer s.Close()
+
+	client := porkbunapi.New("pk1_wrong", "sk1_wrong")
+	client.BaseURL, _ = url.Parse(s.URL)
+
+	_, err := client.Ping(context.Background())
+	var apiErr *porkbunapi.Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, 403, apiErr.StatusCode)
+}
+
+func Test_RateLimit(t *testing.T) {
+	r := require.New(t)
+	s := NewServer(WithDomain("foobar.dev"), WithRateLimit(2, time.Hour))
+	defer s.Close()
+	client := newClient(t, s)
+
+	for range []int{1, 2} {
+		_, err := client.RetrieveRecords(context.Background(), "foobar.dev")
+		r.NoError(err)
+	}
+
+	_, err := client.RetrieveRecords(context.Background(), "foobar.dev")
+	r.True(porkbunapi.IsRateLimited(err))
+	r.Equal(3, s.Calls("dns/retrieve"))
+}
+
+func Test_GlueAndDNSSEC(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	s := NewServer(WithDomain("foobar.dev"))
+	defer s.Close()
+	client := newClient(t, s)
+
+	r.NoError(client.CreateGlueRecord(ctx, "foobar.dev", "ns1", []string{"192.0.2.1", "2001:db8::1"}))
+	r.Error(client.CreateGlueRecord(ctx, "foobar.dev", "ns1", []string{"192.0.2.2"}))
+	r.Equal([]GlueHost{{Host: "ns1.foobar.dev", IPv4: []string{"192.0.2.1"}, IPv6: []string{"2001:db8::1"}}}, s.Glue("foobar.dev"))
+	r.NoError(client.DeleteGlueRecord(ctx, "foobar.dev", "ns1"))
+	r.Empty(s.Glue("foobar.dev"))
+
+	r.NoError(client.CreateDNSSECRecord(ctx, "foobar.dev", porkbunapi.DNSSECRecord{KeyTag: "64087", Alg: "13", DigestType: "2", Digest: "abcd"}))
+	r.Equal([]DNSSECRecord{{KeyTag: "64087", Alg: "13", DigestType: "2", Digest: "abcd"}}, s.DNSSECRecords("foobar.dev"))
+	r.NoError(client.DeleteDNSSECRecord(ctx, "foobar.dev", "64087"))
+	r.Empty(s.DNSSECRecords("foobar.dev"))
+}
+
+func Test_AnyDomain(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	s := NewServer(WithAnyDomain())
+	defer s.Close()
+	client := newClient(t, s)
+
+	records, err := client.RetrieveRecords(ctx, "example.com")
+	r.NoError(err)
+	r.Empty(records)
+
+	_, err = client.CreateRecord(ctx, "Example.com", porkbunapi.Record{Name: "www", Type: "A", Content: "0.0.0.1"})
+	r.NoError(err)
+	r.Len(s.Records("example.com"), 1)
+}
+
+func Test_Persist(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", "porkbun.json")
+
+	first := NewUnstartedServer(WithAnyDomain())
+	r.NoError(first.Persist(path))
+	served := httptest.NewServer(first)
+	client := newClient(t, &Server{URL: served.URL})
+	id, err := client.CreateRecord(ctx, "example.com", porkbunapi.Record{Name: "www", Type: "A", Content: "0.0.0.1"})
+	r.NoError(err)
+	served.Close()
+
+	// A restarted fake picks up where the previous one stopped
+	second := NewServer(WithAnyDomain())
+	defer second.Close()
+	r.NoError(second.Persist(path))
+	client = newClient(t, second)
+
+	record, found, err := client.RetrieveRecord(ctx, "example.com", id)
+	r.NoError(err)
+	r.True(found)
+	r.Equal("0.0.0.1", record.Content)
+
+	next, err := client.CreateRecord(ctx, "example.com", porkbunapi.Record{Name: "mail", Type: "A", Content: "0.0.0.2"})
+	r.NoError(err)
+	r.NotEqual(id, next)
+
+	r.NoError(os.WriteFile(path, []byte("{"), 0o600))
+	r.ErrorContains(NewUnstartedServer().Persist(path), "decoding state")
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+// Every name the live acceptance tests create has a label with this prefix, so the sweeper can find
+// records a crashed run left behind
+const accNamespacePrefix = "tfacc-"
+
+// accRunID tells this run's records from those of runs going on at the same time against the same domain,
+// like the Terraform versions of the CI matrix
+var accRunID = accNamespacePrefix + randomHex(4)
+
+var accLabelPattern = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("porkbun_dns_record", &resource.Sweeper{
+		Name: "porkbun_dns_record",
+		// The region passed with -sweep is the shared test domain
+		F: func(domain string) error {
+			domain = strings.ToLower(domain)
+			return sweepAccRecords(context.Background(), accClient(), domain, func(name string) bool {
+				return hasAccLabel(name, domain, accNamespacePrefix)
+			})
+		},
+	})
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// accDomain returns the domain the live acceptance tests share, skipping the test when none is set
+func accDomain(t *testing.T) string {
+	domain := os.Getenv("PORKBUN_ACC_DOMAIN")
+	if os.Getenv("TF_ACC") == "" || domain == "" {
+		t.Skip("set TF_ACC and PORKBUN_ACC_DOMAIN to run acceptance tests against Porkbun")
+	}
+	return strings.ToLower(domain)
+}
+
+// accNamespace returns a subdomain of the shared test domain only t creates records under, named after the
+// test and the run, and removes whatever is left under it when the test ends
+func accNamespace(t *testing.T, domain string) string {
+	label := strings.Trim(accLabelPattern.ReplaceAllString(strings.ToLower(t.Name()), "-"), "-")
+	if len(label) > 40 {
+		label = strings.TrimRight(label[:40], "-")
+	}
+	namespace := label + "." + accRunID
+
+	t.Cleanup(func() {
+		err := sweepAccRecords(context.Background(), accClient(), domain, func(name string) bool {
+			return strings.HasSuffix(name, "."+namespace+"."+domain)
+		})
+		if err != nil {
+			t.Errorf("cleaning up %s: %v", namespace, err)
+		}
+	})
+	return namespace
+}
+
+// accClient is a client with the credentials the provider would use outside of tests
+func accClient() *porkbunapi.Client {
+	return porkbunapi.New(os.Getenv("PORKBUN_API_KEY"), os.Getenv("PORKBUN_SECRET_KEY"))
+}
+
+func accProviderFactories() map[string]func() (tfprotov6.ProviderServer, error) {
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"porkbun": providerserver.NewProtocol6WithError(New("test")()),
+	}
+}
+
+// hasAccLabel reports whether a record name of domain has a label starting with prefix
+func hasAccLabel(name string, domain string, prefix string) bool {
+	name = strings.ToLower(normalizeDnsValue(name))
+	if !strings.HasSuffix(name, "."+domain) {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."+domain), ".") {
+		if strings.HasPrefix(label, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sweepAccRecords deletes the records of domain whose names match, other records of the domain are
+// never touched
+func sweepAccRecords(ctx context.Context, client *porkbunapi.Client, domain string, match func(name string) bool) error {
+	records, err := client.RetrieveRecords(ctx, domain)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if !match(strings.ToLower(normalizeDnsValue(record.Name))) {
+			continue
+		}
+		if err := client.DeleteRecord(ctx, domain, record.ID); err != nil {
+			return fmt.Errorf("deleting %s record %s: %w", record.Type, record.Name, err)
+		}
+	}
+	return nil
+}
+
+// Test_AccRecordLifecycle runs the create, update and import steps of every record type against the shared
+// test domain at once, each type in its own namespace
+func Test_AccRecordLifecycle(t *testing.T) {
+	domain := accDomain(t)
+
+	tests := []struct {
+		recordType string
+		content    [2]string
+		prio       string
+	}{
+		{"A", [2]string{"192.0.2.1", "192.0.2.2"}, ""},
+		{"AAAA", [2]string{"2001:db8::1", "2001:db8::2"}, ""},
+		{"CNAME", [2]string{"one.example.com", "two.example.com"}, ""},
+		{"TXT", [2]string{"first", "second"}, ""},
+		{"MX", [2]string{"mx1.example.com", "mx2.example.com"}, "10"},
+	}
+	for _, test := range tests {
+		t.Run(test.recordType, func(t *testing.T) {
+			name := "record." + accNamespace(t, domain)
+
+			config := func(content string) string {
+				prio := ""
+				if test.prio != "" {
+					prio = fmt.Sprintf("prio = %q", test.prio)
+				}
+				return fmt.Sprintf(`
+          resource "porkbun_dns_record" "test" {
+            name    = %q
+            domain  = %q
+            type    = %q
+            content = %q
+            %s
+          }
+				`, name, domain, test.recordType, content, prio)
+			}
+
+			steps := []resource.TestStep{}
+			for _, content := range test.content {
+				steps = append(steps, resource.TestStep{
+					Config: config(content),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("porkbun_dns_record.test", "name", name),
+						resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", content),
+					),
+				})
+			}
+			steps = append(steps, resource.TestStep{
+				ResourceName:      "porkbun_dns_record.test",
+				ImportState:       true,
+				ImportStateIdFunc: importStateIdFunc("porkbun_dns_record.test"),
+				ImportStateVerify: true,
+			})
+
+			resource.ParallelTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: accProviderFactories(),
+				Steps:                    steps,
+			})
+		})
+	}
+}
+
+func Test_HasAccLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"record.test-acc-a.tfacc-1a2b3c4d.foobar.dev", true},
+		{"TFACC-1a2b3c4d.foobar.dev.", true},
+		{"www.foobar.dev", false},
+		{"tfacc.foobar.dev", false},
+		{"tfacc-1a2b3c4d.foobar.dev.example.com", false},
+		{"foobar.dev", false},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, hasAccLabel(test.name, "foobar.dev", accNamespacePrefix), test.name)
+	}
+}
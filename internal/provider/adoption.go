@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+)
+
+// adoptableRecords returns the records a new record at fqdn was likely meant to take over: the same name
+// and type with other content, like one created in the dashboard before the zone moved to Terraform. Records
+// carrying marker are already managed by Terraform and records with the same content are duplicates instead,
+// see recordCache.duplicates.
+func adoptableRecords(records []porkbunapi.Record, fqdn string, recordType string, content string, marker string) []porkbunapi.Record {
+	var adoptable []porkbunapi.Record
+	for _, record := range records {
+		if !strings.EqualFold(normalizeDnsValue(record.Name), normalizeDnsValue(fqdn)) || !strings.EqualFold(record.Type, recordType) {
+			continue
+		}
+		if strings.EqualFold(normalizeDnsValue(record.Content), normalizeDnsValue(content)) {
+			continue
+		}
+		if marker != "" && hasNotesMarker(record.Notes, marker) {
+			continue
+		}
+		adoptable = append(adoptable, record)
+	}
+	return adoptable
+}
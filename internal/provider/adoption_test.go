@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AdoptableRecords(t *testing.T) {
+	r := require.New(t)
+
+	records := []porkbunapi.Record{
+		{ID: "1", Name: "www.foobar.dev", Type: "CNAME", Content: "old.example.com"},
+		{ID: "2", Name: "www.foobar.dev", Type: "A", Content: "192.0.2.1"},
+		{ID: "3", Name: "api.foobar.dev", Type: "CNAME", Content: "old.example.com"},
+		{ID: "4", Name: "WWW.foobar.dev", Type: "cname", Content: "Other.Example.com."},
+		{ID: "5", Name: "www.foobar.dev", Type: "CNAME", Content: "lb.example.com", Notes: "managed-by:terraform"},
+	}
+
+	ids := func(records []porkbunapi.Record) []string {
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		return ids
+	}
+
+	r.Equal([]string{"1", "4", "5"}, ids(adoptableRecords(records, "www.foobar.dev", "CNAME", "new.example.com", "")))
+	// Records other resources manage are left out
+	r.Equal([]string{"1", "4"}, ids(adoptableRecords(records, "www.foobar.dev", "CNAME", "new.example.com", "managed-by:terraform")))
+	// and so are records with the planned content, which would be duplicates
+	r.Equal([]string{"1"}, ids(adoptableRecords(records, "www.foobar.dev", "CNAME", "other.example.com", "managed-by:terraform")))
+	r.Empty(adoptableRecords(records, "mail.foobar.dev", "CNAME", "new.example.com", ""))
+}
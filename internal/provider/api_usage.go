@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// apiUsage counts the requests a client made since it was built, so users can see how close a run gets to
+// Porkbun's rate limits. All methods are safe on a nil usage, which counts nothing.
+type apiUsage struct {
+	start     time.Time
+	requests  atomic.Int64
+	throttled atomic.Int64
+	failed    atomic.Int64
+}
+
+func newAPIUsage() *apiUsage {
+	return &apiUsage{start: time.Now()}
+}
+
+// apiUsageOf returns the usage counted by a client built by newHTTPClient
+func apiUsageOf(client *http.Client) *apiUsage {
+	logged, err := loggingTransportOf(client)
+	if err != nil {
+		return nil
+	}
+	return logged.usage
+}
+
+// record counts a request that got statusCode back, or failed with err without an answer
+func (u *apiUsage) record(statusCode int, err error) {
+	if u == nil {
+		return
+	}
+	u.requests.Add(1)
+	switch {
+	case err != nil:
+		u.failed.Add(1)
+	// The statuses porkbunapi reports as ErrRateLimited
+	case statusCode == http.StatusServiceUnavailable || statusCode == http.StatusTooManyRequests:
+		u.throttled.Add(1)
+	}
+}
+
+// requestsPerSecond is the average rate since the client was built
+func (u *apiUsage) requestsPerSecond() float64 {
+	if u == nil {
+		return 0
+	}
+	elapsed := time.Since(u.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(u.requests.Load()) / elapsed
+}
+
+// logFields adds the totals so far to fields, the last API log line of a run summarizes it
+func (u *apiUsage) logFields(fields map[string]any) map[string]any {
+	if u == nil {
+		return fields
+	}
+	fields["requests_total"] = u.requests.Load()
+	fields["throttled_total"] = u.throttled.Load()
+	fields["failed_total"] = u.failed.Load()
+	fields["requests_per_second"] = u.requestsPerSecond()
+	return fields
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_APIUsageCountsRequests(t *testing.T) {
+	r := require.New(t)
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"SUCCESS"}`))
+	}))
+
+	client := newHTTPClient(defaultMaxResponseBytes)
+	usage := apiUsageOf(client)
+	r.NotNil(usage)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(ts.URL)
+		r.NoError(err)
+		r.NoError(resp.Body.Close())
+	}
+	ts.Close()
+	_, err := client.Get(ts.URL)
+	r.Error(err)
+
+	r.Equal(int64(3), usage.requests.Load())
+	r.Equal(int64(1), usage.throttled.Load())
+	r.Equal(int64(1), usage.failed.Load())
+	r.Greater(usage.requestsPerSecond(), 0.0)
+
+	fields := usage.logFields(map[string]any{"status_code": 200})
+	r.Equal(int64(3), fields["requests_total"])
+	r.Equal(200, fields["status_code"])
+
+	// Clients set up some other way aren't counted
+	r.Nil(apiUsageOf(http.DefaultClient))
+	var none *apiUsage
+	none.record(200, nil)
+	r.Zero(none.requestsPerSecond())
+}
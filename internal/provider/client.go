@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+)
+
+// Ensure the API client satisfies the interface resources are written against
+var _ porkbunClient = &porkbunapi.Client{}
+
+// porkbunClient is the part of the Porkbun API the resources use. Configure hands them the API
+// client, unit tests substitute an in-memory fake so reconciliation logic can run without a server.
+type porkbunClient interface {
+	Ping(ctx context.Context) (string, error)
+	CreateRecord(ctx context.Context, domain string, record porkbunapi.Record) (string, error)
+	EditRecord(ctx context.Context, domain string, id string, record porkbunapi.Record) error
+	DeleteRecord(ctx context.Context, domain string, id string) error
+	RetrieveRecords(ctx context.Context, domain string) ([]porkbunapi.Record, error)
+	RetrieveSSLBundle(ctx context.Context, domain string) (porkbunapi.SSLBundle, error)
+	ListDomains(ctx context.Context) ([]porkbunapi.Domain, error)
+	GetNameservers(ctx context.Context, domain string) ([]string, error)
+	UpdateNameservers(ctx context.Context, domain string, nameservers []string) error
+	GetPricing(ctx context.Context) (map[string]porkbunapi.Pricing, error)
+	AddURLForward(ctx context.Context, domain string, forward porkbunapi.URLForward) error
+	GetURLForwards(ctx context.Context, domain string) ([]porkbunapi.URLForward, error)
+	DeleteURLForward(ctx context.Context, domain string, id string) error
+	CreateDNSSECRecord(ctx context.Context, domain string, record porkbunapi.DNSSECRecord) error
+	GetDNSSECRecords(ctx context.Context, domain string) ([]porkbunapi.DNSSECRecord, error)
+	DeleteDNSSECRecord(ctx context.Context, domain string, keyTag string) error
+	CreateGlueRecord(ctx context.Context, domain string, subdomain string, ips []string) error
+	UpdateGlueRecord(ctx context.Context, domain string, subdomain string, ips []string) error
+	DeleteGlueRecord(ctx context.Context, domain string, subdomain string) error
+	GetGlueRecords(ctx context.Context, domain string) ([]porkbunapi.GlueRecord, error)
+}
+
+// apiErrorDetail formats err for the detail of a diagnostic, adding the request Porkbun failed and what can
+// be done about the failures users are able to fix themselves
+func apiErrorDetail(err error) string {
+	detail := fmt.Sprintf("Error: %s", err)
+
+	// What Porkbun support and bug reports need to find the failed call
+	var apiErr *porkbunapi.Error
+	if errors.As(err, &apiErr) {
+		detail += fmt.Sprintf("\n\nEndpoint: %s\nHTTP status: %d", apiErr.Endpoint, apiErr.StatusCode)
+		if apiErr.Status != "" {
+			detail += fmt.Sprintf("\nPorkbun message: %s", apiErr.Message)
+		}
+		if apiErr.RequestID != "" {
+			detail += fmt.Sprintf("\nRequest ID: %s", apiErr.RequestID)
+		}
+	}
+
+	switch {
+	case errors.Is(err, porkbunapi.ErrAuth):
+		detail += "\n\nCheck api_key and secret_key, and that API access is enabled for the domain in the Porkbun dashboard."
+	case errors.Is(err, porkbunapi.ErrRateLimited):
+		detail += "\n\nPorkbun kept rate limiting the requests, try again later or raise max_retries."
+	}
+	return detail
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// contentFields splits content with a structure into named parts, so plans show the part that changed
+// instead of only the whole content: SRV records by weight, port and target, DKIM keys and DMARC policies by
+// tag and SPF policies by mechanism. Other records and content that doesn't parse have no fields.
+func contentFields(recordType string, name string, content string) map[string]string {
+	switch {
+	case strings.EqualFold(recordType, "SRV"):
+		return srvContentFields(content)
+	case isDKIMRecord(recordType, name), isDMARCRecord(recordType, name):
+		return tagContentFields(txtValue(content))
+	case strings.EqualFold(recordType, "TXT") && isSPF(txtValue(content)):
+		return spfContentFields(txtValue(content))
+	}
+	return nil
+}
+
+// srvContentFields splits SRV content, Porkbun keeps the priority in prio so the content is weight, port and target
+func srvContentFields(content string) map[string]string {
+	parts := strings.Fields(content)
+	if len(parts) != 3 {
+		return nil
+	}
+	return map[string]string{"weight": parts[0], "port": parts[1], "target": parts[2]}
+}
+
+// tagContentFields splits tag=value pairs separated by semicolons, as DKIM and DMARC records hold them
+func tagContentFields(content string) map[string]string {
+	fields := map[string]string{}
+	for _, tag := range strings.Split(content, ";") {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(tag, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil
+		}
+		fields[name] = strings.TrimSpace(value)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// spfContentFields groups the terms of an SPF policy by mechanism or modifier, like include or all. Terms
+// keep their qualifier and are joined with spaces in the order of the policy.
+func spfContentFields(content string) map[string]string {
+	fields := map[string]string{}
+	for _, term := range strings.Fields(content)[1:] {
+		mechanism := strings.TrimLeft(term, "+-~?")
+		if end := strings.IndexAny(mechanism, ":=/"); end >= 0 {
+			mechanism = mechanism[:end]
+		}
+		mechanism = strings.ToLower(mechanism)
+		if fields[mechanism] != "" {
+			fields[mechanism] += " "
+		}
+		fields[mechanism] += term
+	}
+	return fields
+}
+
+// contentFieldsValue is content_fields for data, unknown as long as the content, name or type is
+func contentFieldsValue(data porkbunDnsRecordResourceData) types.Map {
+	if data.Type.IsUnknown() || data.Name.IsUnknown() || data.Content.IsUnknown() {
+		return types.MapUnknown(types.StringType)
+	}
+	if data.Content.IsNull() {
+		return types.MapNull(types.StringType)
+	}
+
+	fields := contentFields(data.Type.ValueString(), data.Name.ValueString(), data.Content.ValueString())
+	if fields == nil {
+		return types.MapNull(types.StringType)
+	}
+	elements := make(map[string]attr.Value, len(fields))
+	for name, value := range fields {
+		elements[name] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContentFields(t *testing.T) {
+	tests := []struct {
+		recordType string
+		name       string
+		content    string
+		expected   map[string]string
+	}{
+		{"SRV", "_sip._tcp", "5 5060 sip.foobar.dev", map[string]string{"weight": "5", "port": "5060", "target": "sip.foobar.dev"}},
+		{"SRV", "_sip._tcp", "5060 sip.foobar.dev", nil},
+		{"TXT", "", "v=spf1 include:_spf.mail.dev ip4:192.0.2.0/24 include:mail.dev -all", map[string]string{
+			"include": "include:_spf.mail.dev include:mail.dev",
+			"ip4":     "ip4:192.0.2.0/24",
+			"all":     "-all",
+		}},
+		{"TXT", "", `"v=spf1 mx " "~all"`, map[string]string{"mx": "mx", "all": "~all"}},
+		{"TXT", "mail._domainkey", "v=DKIM1; k=ed25519; p=11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=", map[string]string{
+			"v": "DKIM1",
+			"k": "ed25519",
+			"p": "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=",
+		}},
+		{"TXT", "_dmarc", "v=DMARC1; p=reject; rua=mailto:dmarc@foobar.dev;", map[string]string{
+			"v":   "DMARC1",
+			"p":   "reject",
+			"rua": "mailto:dmarc@foobar.dev",
+		}},
+		{"TXT", "_dmarc", "not a policy", nil},
+		{"TXT", "", "google-site-verification=abc", nil},
+		{"A", "www", "192.0.2.1", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.recordType+" "+test.content, func(t *testing.T) {
+			require.Equal(t, test.expected, contentFields(test.recordType, test.name, test.content))
+		})
+	}
+}
+
+func Test_ContentFieldsValue(t *testing.T) {
+	r := require.New(t)
+
+	data := unitRecordData("1", "0.0.0.1")
+	r.True(contentFieldsValue(data).IsNull())
+
+	data.Type = types.StringValue("SRV")
+	data.Content = types.StringUnknown()
+	r.True(contentFieldsValue(data).IsUnknown())
+
+	// Write-only content is never shown
+	data.Content = types.StringNull()
+	r.True(contentFieldsValue(data).IsNull())
+
+	data.Content = types.StringValue("5 443 www.foobar.dev")
+	r.Equal(map[string]string{"weight": "5", "port": "443", "target": "www.foobar.dev"}, mapStrings(t, contentFieldsValue(data)))
+}
+
+func Test_ModifyPlanSetsContentFields(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+	srv := func(content string) porkbunDnsRecordResourceData {
+		data := unitRecordData("1", content)
+		data.Name = types.StringValue("_https._tcp")
+		data.Type = types.StringValue("SRV")
+		data.ContentFields = contentFieldsValue(data)
+		return data
+	}
+
+	planned := srv("5 8443 www.foobar.dev")
+	planned.ContentFields = types.MapUnknown(types.StringType)
+	plan := recordState(t, res, planned)
+	req := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  recordState(t, res, srv("5 443 www.foobar.dev")),
+	}
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	var fields types.Map
+	r.False(resp.Plan.GetAttribute(ctx, path.Root("content_fields"), &fields).HasError())
+	r.Equal(map[string]string{"weight": "5", "port": "8443", "target": "www.foobar.dev"}, mapStrings(t, fields))
+}
+
+func mapStrings(t *testing.T, value types.Map) map[string]string {
+	var result map[string]string
+	require.False(t, value.ElementsAs(context.Background(), &result, false).HasError())
+	return result
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Content read from a file or decoded from base64 can't be longer than this, a record that doesn't fit in
+// the common 4096 byte EDNS buffer would need DNS over TCP to be resolved
+const maxSourcedContentLength = 4096
+
+// Files are refused long before their content could be normalized into something that fits
+const maxContentFileSize = 64 << 10
+
+// contentFromFile reads the content of a record from the file at path, like generated DKIM or TLSA key
+// material
+func contentFromFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxContentFileSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxContentFileSize {
+		return "", fmt.Errorf("%s is larger than %d bytes", path, maxContentFileSize)
+	}
+	return normalizeSourcedContent(data)
+}
+
+// contentFromBase64 decodes the content of a record from standard base64, which may be wrapped over
+// several lines
+func contentFromBase64(value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+	if err != nil {
+		return "", fmt.Errorf("invalid base64: %w", err)
+	}
+	return normalizeSourcedContent(data)
+}
+
+// normalizeSourcedContent joins the lines of data into the single line Porkbun stores and drops the
+// whitespace around it, so a trailing newline or a key wrapped at 64 characters gives the same content
+func normalizeSourcedContent(data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", errors.New("content isn't valid UTF-8 text")
+	}
+	content := strings.TrimSpace(strings.NewReplacer("\r", "", "\n", "").Replace(string(data)))
+	if content == "" {
+		return "", errors.New("content is empty")
+	}
+	if len(content) > maxSourcedContentLength {
+		return "", fmt.Errorf("content is %d bytes long, at most %d are supported", len(content), maxSourcedContentLength)
+	}
+	return content, nil
+}
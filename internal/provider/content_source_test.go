@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContentFromFile(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+
+	key := filepath.Join(dir, "dkim.txt")
+	r.NoError(os.WriteFile(key, []byte("v=DKIM1; k=rsa;\r\n p=MIIBIjAN\nBgkqhkiG\n\n"), 0o600))
+	content, err := contentFromFile(key)
+	r.NoError(err)
+	r.Equal("v=DKIM1; k=rsa; p=MIIBIjANBgkqhkiG", content)
+
+	_, err = contentFromFile(filepath.Join(dir, "missing.txt"))
+	r.Error(err)
+
+	empty := filepath.Join(dir, "empty.txt")
+	r.NoError(os.WriteFile(empty, []byte(" \n"), 0o600))
+	_, err = contentFromFile(empty)
+	r.ErrorContains(err, "empty")
+
+	long := filepath.Join(dir, "long.txt")
+	r.NoError(os.WriteFile(long, []byte(strings.Repeat("a", maxSourcedContentLength+1)), 0o600))
+	_, err = contentFromFile(long)
+	r.ErrorContains(err, "at most 4096")
+
+	huge := filepath.Join(dir, "huge.bin")
+	r.NoError(os.WriteFile(huge, make([]byte, maxContentFileSize+1), 0o600))
+	_, err = contentFromFile(huge)
+	r.ErrorContains(err, "larger than")
+}
+
+func Test_ContentFromBase64(t *testing.T) {
+	r := require.New(t)
+
+	content, err := contentFromBase64("MyAxIDEgZmVkY2Jh\nOTg3NjU0MzIxMAo=")
+	r.NoError(err)
+	r.Equal("3 1 1 fedcba9876543210", content)
+
+	_, err = contentFromBase64("not base64!")
+	r.ErrorContains(err, "invalid base64")
+
+	_, err = contentFromBase64("/w==")
+	r.ErrorContains(err, "UTF-8")
+}
@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunAllRecordsDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunAllRecordsDataSource{}
+
+// How many domains porkbun_all_records retrieves at once unless configured, low enough for the rate limit
+const defaultAllRecordsConcurrency = 4
+
+func NewAllRecordsDataSource() datasource.DataSource {
+	return &porkbunAllRecordsDataSource{}
+}
+
+type porkbunAllRecordsDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunAllRecordsDataSourceData struct {
+	Type        types.String           `tfsdk:"type"`
+	Content     types.String           `tfsdk:"content"`
+	Concurrency types.Int64            `tfsdk:"concurrency"`
+	Records     []porkbunAllRecordData `tfsdk:"records"`
+}
+
+type porkbunAllRecordData struct {
+	Domain  types.String `tfsdk:"domain"`
+	Id      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Type    types.String `tfsdk:"type"`
+	Content types.String `tfsdk:"content"`
+	Ttl     types.String `tfsdk:"ttl"`
+	Prio    types.String `tfsdk:"prio"`
+	Notes   types.String `tfsdk:"notes"`
+}
+
+func (d *porkbunAllRecordsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_all_records"
+}
+
+func (d *porkbunAllRecordsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the records of every domain in the account, for account-wide audits like finding every record " +
+			"that still points at a decommissioned address. The zones are retrieved `concurrency` at a time and shared with the " +
+			"`porkbun_dns_record` resources refreshed in the same run.",
+
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list records of this type, like `A`",
+			},
+			"content": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Only list records with this content, like `192.0.2.1`. " +
+					"Compared without case and a trailing dot so hostnames match however they are written",
+			},
+			"concurrency": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: fmt.Sprintf("How many domains to retrieve at once, defaults to %d", defaultAllRecordsConcurrency),
+			},
+			"records": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The matching records, ordered by domain and record ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"domain": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The domain of the record",
+						},
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The Porkbun ID of the record",
+						},
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The subdomain of the record without the base domain",
+						},
+						"type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The type of the record",
+						},
+						"content": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The content of the record",
+						},
+						"ttl": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The ttl of the record in seconds",
+						},
+						"prio": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The priority of MX and SRV records, null for others",
+						},
+						"notes": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The notes of the record",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *porkbunAllRecordsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunAllRecordsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunAllRecordsDataSourceData
+	rt := d.provider.runtime()
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	concurrency := defaultAllRecordsConcurrency
+	if !data.Concurrency.IsNull() {
+		concurrency = int(data.Concurrency.ValueInt64())
+	}
+	if concurrency < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("concurrency"),
+			"Invalid concurrency",
+			fmt.Sprintf("Expected at least 1, got %d", concurrency),
+		)
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domains, err := retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Domain, error) {
+		return rt.client.ListDomains(ctx)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not list domains.",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	names := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		names = append(names, strings.ToLower(domain.Domain))
+	}
+	sort.Strings(names)
+
+	zones, errs := retrieveZones(ctx, rt, names, concurrency)
+	for i, err := range errs {
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf(`Could not retrieve records for %s.`, names[i]),
+				apiErrorDetail(err),
+			)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Records = []porkbunAllRecordData{}
+	for i, domain := range names {
+		for _, record := range sortedRecords(zones[i]) {
+			if !data.Type.IsNull() && !strings.EqualFold(data.Type.ValueString(), record.Type) {
+				continue
+			}
+			if !data.Content.IsNull() && !sameRecordContent(data.Content.ValueString(), record.Content) {
+				continue
+			}
+
+			prio := types.StringNull()
+			// The API reports a priority of 0 on every record, it only means something for these
+			if record.Type == "MX" || record.Type == "SRV" {
+				prio = optionalString(record.Prio)
+			}
+			data.Records = append(data.Records, porkbunAllRecordData{
+				Domain:  types.StringValue(domain),
+				Id:      types.StringValue(record.ID),
+				Name:    types.StringValue(relativeRecordName(record.Name, domain)),
+				Type:    types.StringValue(record.Type),
+				Content: types.StringValue(record.Content),
+				Ttl:     optionalString(record.TTL),
+				Prio:    prio,
+				Notes:   optionalString(record.Notes),
+			})
+		}
+	}
+	tflog.Debug(ctx, "Listed records of all domains", map[string]any{"domain_count": len(names), "matching_count": len(data.Records)})
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// retrieveZones retrieves the records of domains through the record cache, at most concurrency domains at a
+// time. The zones and errors are returned in the order of domains.
+func retrieveZones(ctx context.Context, rt *providerRuntime, domains []string, concurrency int) ([]map[string]porkbunapi.Record, []error) {
+	zones := make([]map[string]porkbunapi.Record, len(domains))
+	errs := make([]error, len(domains))
+	slots := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, domain := range domains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			ctx := tflog.SetField(ctx, "domain", domain)
+			zones[i], errs[i] = rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+				return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+					return rt.client.RetrieveRecords(ctx, domain)
+				})
+			})
+		}()
+	}
+	wg.Wait()
+
+	return zones, errs
+}
+
+// sameRecordContent compares content like a resolver would, hostnames are case insensitive and may be fully qualified
+func sameRecordContent(a string, b string) bool {
+	return strings.EqualFold(normalizeDnsValue(a), normalizeDnsValue(b))
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AllRecordsDataSource(t *testing.T) {
+	server := newTestServer(t,
+		porkbuntest.WithDomain("other.dev",
+			porkbuntest.Record{Name: "", Type: "MX", Content: "mail.other.dev", Prio: "10"},
+			porkbuntest.Record{Name: "old", Type: "A", Content: "192.0.2.1"},
+		),
+		porkbuntest.WithDomain("foobar.dev",
+			porkbuntest.Record{Name: "www", Type: "A", Content: "192.0.2.1", TTL: "3600"},
+			porkbuntest.Record{Name: "api", Type: "CNAME", Content: "Old.Other.dev."},
+		),
+	)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `data "porkbun_all_records" "all" { concurrency = 1 }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_all_records.all", "records.#", "4"),
+					// Ordered by domain
+					resource.TestCheckResourceAttr("data.porkbun_all_records.all", "records.0.domain", "foobar.dev"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.all", "records.0.name", "www"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.all", "records.0.ttl", "3600"),
+					resource.TestCheckNoResourceAttr("data.porkbun_all_records.all", "records.0.prio"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.all", "records.2.domain", "other.dev"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.all", "records.2.type", "MX"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.all", "records.2.prio", "10"),
+				),
+			},
+			{
+				Config: `data "porkbun_all_records" "decommissioned" { content = "192.0.2.1" }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_all_records.decommissioned", "records.#", "2"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.decommissioned", "records.0.domain", "foobar.dev"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.decommissioned", "records.1.domain", "other.dev"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.decommissioned", "records.1.name", "old"),
+				),
+			},
+			{
+				Config: `data "porkbun_all_records" "cnames" {
+                  type    = "cname"
+                  content = "old.other.dev"
+                }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_all_records.cnames", "records.#", "1"),
+					resource.TestCheckResourceAttr("data.porkbun_all_records.cnames", "records.0.name", "api"),
+				),
+			},
+		},
+	})
+}
+
+func Test_SameRecordContent(t *testing.T) {
+	r := require.New(t)
+
+	r.True(sameRecordContent("192.0.2.1", "192.0.2.1"))
+	r.True(sameRecordContent("Mail.Foobar.dev.", "mail.foobar.dev"))
+	r.False(sameRecordContent("192.0.2.1", "192.0.2.10"))
+}
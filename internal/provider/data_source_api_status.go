@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunApiStatusDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunApiStatusDataSource{}
+
+func NewApiStatusDataSource() datasource.DataSource {
+	return &porkbunApiStatusDataSource{}
+}
+
+type porkbunApiStatusDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunApiStatusDataSourceData struct {
+	Reachable     types.Bool   `tfsdk:"reachable"`
+	Authenticated types.Bool   `tfsdk:"authenticated"`
+	LatencyMs     types.Int64  `tfsdk:"latency_ms"`
+	YourIp        types.String `tfsdk:"your_ip"`
+	Error         types.String `tfsdk:"error"`
+}
+
+func (d *porkbunApiStatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_status"
+}
+
+func (d *porkbunApiStatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Pings the Porkbun API once with the provider's keys, for pre-flight checks in a `check` block before a large apply. " +
+			"Failures are reported in the attributes rather than as errors so assertions can look at them. " +
+			"Set `skip_credentials_validation` on the provider for the check to run when the API is down.",
+
+		Attributes: map[string]schema.Attribute{
+			"reachable": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the API answered, even if it rejected the call",
+			},
+			"authenticated": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the call succeeded with the provider's keys",
+			},
+			"latency_ms": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "How long the call took in milliseconds",
+			},
+			"your_ip": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The IP address Porkbun saw the call come from, empty when the call failed",
+			},
+			"error": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Why the call failed, empty when it succeeded",
+			},
+		},
+	}
+}
+
+func (d *porkbunApiStatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunApiStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	rt := d.provider.runtime()
+
+	// A single attempt without retries, so the result reflects the API as it is right now
+	start := time.Now()
+	ip, err := rt.client.Ping(ctx)
+	latency := time.Since(start)
+
+	var apiErr *porkbunapi.Error
+	data := porkbunApiStatusDataSourceData{
+		Reachable:     types.BoolValue(err == nil || errors.As(err, &apiErr)),
+		Authenticated: types.BoolValue(err == nil),
+		LatencyMs:     types.Int64Value(latency.Milliseconds()),
+		YourIp:        types.StringValue(ip),
+		Error:         types.StringValue(""),
+	}
+	if err != nil {
+		data.Error = types.StringValue(err.Error())
+	}
+	tflog.Debug(ctx, "Pinged API", map[string]any{"reachable": data.Reachable.ValueBool(), "latency_ms": latency.Milliseconds()})
+
+	diags := resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
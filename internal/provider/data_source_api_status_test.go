@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ApiStatusDataSource(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `data "porkbun_api_status" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_api_status.test", "reachable", "true"),
+					resource.TestCheckResourceAttr("data.porkbun_api_status.test", "authenticated", "true"),
+					resource.TestCheckResourceAttr("data.porkbun_api_status.test", "your_ip", "127.0.0.1"),
+					resource.TestCheckResourceAttr("data.porkbun_api_status.test", "error", ""),
+					resource.TestCheckResourceAttrSet("data.porkbun_api_status.test", "latency_ms"),
+				),
+			},
+		},
+	})
+}
+
+// failingPingClient answers Ping with err
+type failingPingClient struct {
+	*fakeClient
+	err error
+}
+
+func (c failingPingClient) Ping(ctx context.Context) (string, error) {
+	return "", c.err
+}
+
+func Test_ApiStatusDataSourceFailures(t *testing.T) {
+	tests := map[string]struct {
+		err           error
+		reachable     bool
+		authenticated bool
+	}{
+		"rejected": {err: apiError("Invalid API key. (002)"), reachable: true},
+		"down":     {err: errors.New(`Post "https://api.porkbun.com/api/json/v3/ping": dial tcp: i/o timeout`)},
+		"limited":  {err: &porkbunapi.Error{StatusCode: 503, Message: "Rate limit exceeded"}, reachable: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := require.New(t)
+			ctx := context.Background()
+
+			p := &porkbunProvider{}
+			p.setRuntime(&providerRuntime{client: failingPingClient{newFakeClient(), test.err}, records: newRecordCache()})
+			d := &porkbunApiStatusDataSource{provider: p}
+
+			var schemaResp datasource.SchemaResponse
+			d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+			resp := datasource.ReadResponse{State: tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			}}
+			d.Read(ctx, datasource.ReadRequest{}, &resp)
+			r.Empty(resp.Diagnostics)
+
+			var data porkbunApiStatusDataSourceData
+			r.False(resp.State.Get(ctx, &data).HasError())
+			r.Equal(test.reachable, data.Reachable.ValueBool())
+			r.False(data.Authenticated.ValueBool())
+			r.Equal(test.err.Error(), data.Error.ValueString())
+			r.Equal("", data.YourIp.ValueString())
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunApiUsageDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunApiUsageDataSource{}
+
+func NewApiUsageDataSource() datasource.DataSource {
+	return &porkbunApiUsageDataSource{}
+}
+
+type porkbunApiUsageDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunApiUsageDataSourceData struct {
+	Requests          types.Int64   `tfsdk:"requests"`
+	Throttled         types.Int64   `tfsdk:"throttled"`
+	Failed            types.Int64   `tfsdk:"failed"`
+	ElapsedSeconds    types.Int64   `tfsdk:"elapsed_seconds"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+}
+
+func (d *porkbunApiUsageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_api_usage"
+}
+
+func (d *porkbunApiUsageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports the Porkbun API requests the provider made so far in the current Terraform command, to size " +
+			"`-parallelism` and `max_retries` for the rate limits of an account. Data sources are read before resources are changed, " +
+			"add `depends_on` on the resources to count their requests. Every API request is also logged with the same totals " +
+			"under `TF_LOG_PROVIDER_PORKBUN_API=debug`.",
+
+		Attributes: map[string]schema.Attribute{
+			"requests": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "How many requests were sent",
+			},
+			"throttled": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "How many requests Porkbun rejected for exceeding the rate limit",
+			},
+			"failed": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "How many requests got no answer, like timeouts",
+			},
+			"elapsed_seconds": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Seconds since the provider was configured",
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The average request rate since the provider was configured",
+			},
+		},
+	}
+}
+
+func (d *porkbunApiUsageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunApiUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	usage := d.provider.runtime().usage
+
+	data := porkbunApiUsageDataSourceData{
+		Requests:          types.Int64Value(0),
+		Throttled:         types.Int64Value(0),
+		Failed:            types.Int64Value(0),
+		ElapsedSeconds:    types.Int64Value(0),
+		RequestsPerSecond: types.Float64Value(0),
+	}
+	if usage != nil {
+		data.Requests = types.Int64Value(usage.requests.Load())
+		data.Throttled = types.Int64Value(usage.throttled.Load())
+		data.Failed = types.Int64Value(usage.failed.Load())
+		data.ElapsedSeconds = types.Int64Value(int64(time.Since(usage.start).Seconds()))
+		// Rounded so the value doesn't look more precise than it is
+		data.RequestsPerSecond = types.Float64Value(math.Round(usage.requestsPerSecond()*100) / 100)
+	}
+	tflog.Debug(ctx, "Read API usage", usage.logFields(map[string]any{}))
+
+	diags := resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func Test_ApiUsageDataSource(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `data "porkbun_api_usage" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Configuring the provider pinged the API to validate the credentials
+					resource.TestCheckResourceAttr("data.porkbun_api_usage.test", "requests", "1"),
+					resource.TestCheckResourceAttr("data.porkbun_api_usage.test", "throttled", "0"),
+					resource.TestCheckResourceAttr("data.porkbun_api_usage.test", "failed", "0"),
+					resource.TestCheckResourceAttrSet("data.porkbun_api_usage.test", "requests_per_second"),
+				),
+			},
+		},
+	})
+}
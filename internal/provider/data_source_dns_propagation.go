@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunDnsPropagationDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &porkbunDnsPropagationDataSource{}
+
+func NewDnsPropagationDataSource() datasource.DataSource {
+	return &porkbunDnsPropagationDataSource{lookup: lookupRecord}
+}
+
+// porkbunDnsPropagationDataSource only talks to public DNS, so it needs no provider configuration
+type porkbunDnsPropagationDataSource struct {
+	lookup dnsLookupFunc
+}
+
+type porkbunDnsPropagationDataSourceData struct {
+	Name       types.String                      `tfsdk:"name"`
+	Type       types.String                      `tfsdk:"type"`
+	Expected   types.String                      `tfsdk:"expected"`
+	Resolvers  types.List                        `tfsdk:"resolvers"`
+	Propagated types.Bool                        `tfsdk:"propagated"`
+	Results    []porkbunDnsPropagationResultData `tfsdk:"results"`
+}
+
+type porkbunDnsPropagationResultData struct {
+	Resolver types.String   `tfsdk:"resolver"`
+	Values   []types.String `tfsdk:"values"`
+	Error    types.String   `tfsdk:"error"`
+	Matches  types.Bool     `tfsdk:"matches"`
+}
+
+func (d *porkbunDnsPropagationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_propagation"
+}
+
+func (d *porkbunDnsPropagationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks a record up on public resolvers and reports whether it has propagated. Meant for `check` blocks, " +
+			"which turn a record that isn't visible yet into a warning instead of failing the apply.",
+
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The fully qualified name to look up, e.g. `www.example.com`",
+			},
+			"type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The record type, one of " + strings.Join(lookupRecordTypes, ", "),
+			},
+			"expected": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The value that has to be visible, in the format of the record's `content`. " +
+					"When unset any answer counts as propagated",
+			},
+			"resolvers": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Resolvers to ask, as IP addresses or `host:port`. Defaults to " +
+					"`" + strings.Join(defaultPropagationResolvers, "`, `") + "`",
+			},
+			"propagated": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether every resolver answered with the expected value",
+			},
+			"results": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The answer of each resolver",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resolver": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The resolver that was asked",
+						},
+						"values": schema.ListAttribute{
+							Computed:            true,
+							ElementType:         types.StringType,
+							MarkdownDescription: "The values it returned",
+						},
+						"error": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Why the lookup failed, empty when it succeeded",
+						},
+						"matches": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the expected value was among the values",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *porkbunDnsPropagationDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data porkbunDnsPropagationDataSourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Type.IsUnknown() && !slices.Contains(lookupRecordTypes, strings.ToUpper(data.Type.ValueString())) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Unsupported record type",
+			fmt.Sprintf("%s records can't be looked up, expected one of %s", data.Type.ValueString(), strings.Join(lookupRecordTypes, ", ")),
+		)
+	}
+
+	for i, resolver := range data.Resolvers.Elements() {
+		resolver, ok := resolver.(types.String)
+		if !ok || resolver.IsUnknown() {
+			continue
+		}
+		if _, err := resolverAddress(resolver.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("resolvers").AtListIndex(i),
+				"Invalid resolver",
+				err.Error(),
+			)
+		}
+	}
+}
+
+func (d *porkbunDnsPropagationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data porkbunDnsPropagationDataSourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resolvers := defaultPropagationResolvers
+	if !data.Resolvers.IsNull() {
+		resolvers = nil
+		resp.Diagnostics.Append(data.Resolvers.ElementsAs(ctx, &resolvers, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	name := data.Name.ValueString()
+	recordType := strings.ToUpper(data.Type.ValueString())
+	ctx = tflog.SetField(ctx, "name", name)
+	ctx = tflog.SetField(ctx, "type", recordType)
+
+	results := checkPropagation(ctx, d.lookup, resolvers, name, recordType, data.Expected.ValueString())
+
+	data.Propagated = types.BoolValue(len(results) > 0)
+	data.Results = make([]porkbunDnsPropagationResultData, 0, len(results))
+	for _, result := range results {
+		tflog.Debug(ctx, "Looked up record", map[string]any{"resolver": result.Resolver, "values": result.Values, "error": result.Error})
+
+		values := make([]types.String, 0, len(result.Values))
+		for _, value := range result.Values {
+			values = append(values, types.StringValue(value))
+		}
+		data.Results = append(data.Results, porkbunDnsPropagationResultData{
+			Resolver: types.StringValue(result.Resolver),
+			Values:   values,
+			Error:    types.StringValue(result.Error),
+			Matches:  types.BoolValue(result.Matches),
+		})
+		if !result.Matches {
+			data.Propagated = types.BoolValue(false)
+		}
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func Test_DnsPropagationDataSource(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+	resolver := newTestResolver(t, map[string][]dnsmessage.Resource{
+		"www.foobar.dev.": {
+			testResource("www.foobar.dev.", &dnsmessage.AResource{A: [4]byte{0, 0, 0, 1}}),
+		},
+	})
+
+	config := func(expected string) string {
+		return fmt.Sprintf(`
+          data "porkbun_dns_propagation" "test" {
+            name      = "www.foobar.dev"
+            type      = "a"
+            expected  = %q
+            resolvers = [%q]
+          }
+        `, expected, resolver)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: config("0.0.0.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_dns_propagation.test", "propagated", "true"),
+					resource.TestCheckResourceAttr("data.porkbun_dns_propagation.test", "results.0.resolver", resolver),
+					resource.TestCheckResourceAttr("data.porkbun_dns_propagation.test", "results.0.values.0", "0.0.0.1"),
+					resource.TestCheckResourceAttr("data.porkbun_dns_propagation.test", "results.0.error", ""),
+				),
+			},
+			{
+				Config: config("0.0.0.2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_dns_propagation.test", "propagated", "false"),
+					resource.TestCheckResourceAttr("data.porkbun_dns_propagation.test", "results.0.matches", "false"),
+				),
+			},
+		},
+	})
+}
+
+func Test_DnsPropagationDataSourceValidation(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          data "porkbun_dns_propagation" "test" {
+            name = "foobar.dev"
+            type = "CAA"
+          }
+        `,
+				ExpectError: regexp.MustCompile("Unsupported record type"),
+			},
+			{
+				Config: `
+          data "porkbun_dns_propagation" "test" {
+            name      = "foobar.dev"
+            type      = "A"
+            resolvers = ["one.one.one.one"]
+          }
+        `,
+				ExpectError: regexp.MustCompile("Invalid resolver"),
+			},
+		},
+	})
+}
@@ -0,0 +1,218 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunDomainDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunDomainDataSource{}
+
+func NewDomainDataSource() datasource.DataSource {
+	return &porkbunDomainDataSource{}
+}
+
+type porkbunDomainDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunDomainDataSourceData struct {
+	Domain       types.String `tfsdk:"domain"`
+	Tld          types.String `tfsdk:"tld"`
+	Status       types.String `tfsdk:"status"`
+	CreateDate   types.String `tfsdk:"create_date"`
+	ExpireDate   types.String `tfsdk:"expire_date"`
+	AutoRenew    types.Bool   `tfsdk:"auto_renew"`
+	SecurityLock types.Bool   `tfsdk:"security_lock"`
+	WhoisPrivacy types.Bool   `tfsdk:"whois_privacy"`
+	Labels       []string     `tfsdk:"labels"`
+
+	RenewalPrice      types.String `tfsdk:"renewal_price"`
+	RegistrationPrice types.String `tfsdk:"registration_price"`
+	TransferPrice     types.String `tfsdk:"transfer_price"`
+}
+
+func (d *porkbunDomainDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domain"
+}
+
+func (d *porkbunDomainDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up a domain of the account with what renewing it currently costs, " +
+			"so budgets of upcoming renewals can be built from Terraform outputs. Prices are Porkbun's current prices for the TLD in USD.",
+
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain to look up, like `example.com`",
+			},
+			"tld": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The TLD of the domain, like `com`",
+			},
+			"status": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The status of the registration, like `ACTIVE`",
+			},
+			"create_date": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "When the domain was registered, like `2020-01-01 00:00:00`",
+			},
+			"expire_date": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "When the registration expires and the domain is up for renewal, like `2030-01-01 00:00:00`",
+			},
+			"auto_renew": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether Porkbun renews the domain automatically before it expires",
+			},
+			"security_lock": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether the domain is locked against transfers",
+			},
+			"whois_privacy": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether WHOIS privacy is enabled",
+			},
+			"labels": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The titles of the labels the domain carries in the Porkbun dashboard",
+			},
+			"renewal_price": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Price of a renewal of the domain in USD, like `10.81`. Null when Porkbun doesn't list pricing for the TLD",
+			},
+			"registration_price": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Price of the first year of a registration under the TLD in USD",
+			},
+			"transfer_price": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Price of a transfer under the TLD in USD",
+			},
+		},
+	}
+}
+
+func (d *porkbunDomainDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunDomainDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunDomainDataSourceData
+	rt := d.provider.runtime()
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	name := strings.ToLower(normalizeDnsValue(data.Domain.ValueString()))
+	ctx = tflog.SetField(ctx, "domain", name)
+
+	domains, err := retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Domain, error) {
+		return rt.client.ListDomains(ctx)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not list domains.",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	var domain porkbunapi.Domain
+	var found bool
+	for _, candidate := range domains {
+		if strings.EqualFold(candidate.Domain, name) {
+			domain, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("domain"),
+			"Domain not found",
+			fmt.Sprintf("%s isn't a domain of the account", name),
+		)
+		return
+	}
+
+	pricing, err := retry(ctx, rt.retries, func(ctx context.Context) (map[string]porkbunapi.Pricing, error) {
+		return rt.client.GetPricing(ctx)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not retrieve pricing.",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	data = domainData(data, domain)
+	if price, ok := pricing[strings.ToLower(data.Tld.ValueString())]; ok {
+		data.RenewalPrice = types.StringValue(price.Renewal)
+		data.RegistrationPrice = types.StringValue(price.Registration)
+		data.TransferPrice = types.StringValue(price.Transfer)
+	} else {
+		resp.Diagnostics.AddWarning(
+			"No pricing for the TLD",
+			fmt.Sprintf("Porkbun doesn't list prices for .%s, the prices of %s are left empty", data.Tld.ValueString(), name),
+		)
+	}
+	tflog.Debug(ctx, "Retrieved domain", map[string]any{"expire_date": domain.ExpireDate, "renewal_price": data.RenewalPrice.ValueString()})
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// domainData is data with the attributes of domain set and the prices left null, the configured domain is kept as given
+func domainData(data porkbunDomainDataSourceData, domain porkbunapi.Domain) porkbunDomainDataSourceData {
+	tld := domain.TLD
+	if tld == "" {
+		// Without one from the API the last label is right for all but a few TLDs like co.uk
+		tld = domain.Domain[strings.LastIndex(domain.Domain, ".")+1:]
+	}
+
+	data.Tld = types.StringValue(strings.ToLower(tld))
+	data.Status = types.StringValue(domain.Status)
+	data.CreateDate = types.StringValue(domain.CreateDate)
+	data.ExpireDate = types.StringValue(domain.ExpireDate)
+	data.AutoRenew = types.BoolValue(domain.AutoRenew)
+	data.SecurityLock = types.BoolValue(domain.SecurityLock)
+	data.WhoisPrivacy = types.BoolValue(domain.WhoisPrivacy)
+	data.Labels = make([]string, 0, len(domain.Labels))
+	for _, label := range domain.Labels {
+		data.Labels = append(data.Labels, label.Title)
+	}
+	data.RenewalPrice = types.StringNull()
+	data.RegistrationPrice = types.StringNull()
+	data.TransferPrice = types.StringNull()
+	return data
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DomainDataSource(t *testing.T) {
+	server := newTestServer(t,
+		porkbuntest.WithLabels("foobar.xyz", "prod"),
+		porkbuntest.WithDomain("foobar.dev"),
+		porkbuntest.WithDomain("foobar.example"),
+	)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          data "porkbun_domain" "xyz" {
+            domain = "FooBar.xyz"
+          }
+
+          data "porkbun_domain" "dev" {
+            domain = "foobar.dev"
+          }
+        `,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_domain.xyz", "tld", "xyz"),
+					resource.TestCheckResourceAttr("data.porkbun_domain.xyz", "expire_date", "2030-01-01 00:00:00"),
+					resource.TestCheckResourceAttr("data.porkbun_domain.xyz", "auto_renew", "true"),
+					resource.TestCheckResourceAttr("data.porkbun_domain.xyz", "labels.#", "1"),
+					resource.TestCheckResourceAttr("data.porkbun_domain.xyz", "labels.0", "prod"),
+					// The renewal, not the discounted first year
+					resource.TestCheckResourceAttr("data.porkbun_domain.xyz", "renewal_price", "12.98"),
+					resource.TestCheckResourceAttr("data.porkbun_domain.xyz", "registration_price", "2.04"),
+					resource.TestCheckResourceAttr("data.porkbun_domain.dev", "renewal_price", "10.81"),
+					resource.TestCheckResourceAttr("data.porkbun_domain.dev", "labels.#", "0"),
+				),
+			},
+			{
+				Config: `
+          data "porkbun_domain" "unpriced" {
+            domain = "foobar.example"
+          }
+        `,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_domain.unpriced", "tld", "example"),
+					resource.TestCheckNoResourceAttr("data.porkbun_domain.unpriced", "renewal_price"),
+				),
+			},
+			{
+				Config: `
+          data "porkbun_domain" "missing" {
+            domain = "missing.dev"
+          }
+        `,
+				ExpectError: regexp.MustCompile(`missing.dev isn't a domain of the account`),
+			},
+		},
+	})
+}
+
+func Test_DomainDataDerivesTld(t *testing.T) {
+	r := require.New(t)
+
+	data := domainData(porkbunDomainDataSourceData{Domain: types.StringValue("foobar.dev")}, porkbunapi.Domain{Domain: "foobar.dev"})
+	r.Equal("dev", data.Tld.ValueString())
+	r.Equal("foobar.dev", data.Domain.ValueString())
+	r.True(data.RenewalPrice.IsNull())
+
+	data = domainData(data, porkbunapi.Domain{Domain: "foobar.co.uk", TLD: "co.uk"})
+	r.Equal("co.uk", data.Tld.ValueString())
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunDomainsByNameserverDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunDomainsByNameserverDataSource{}
+
+func NewDomainsByNameserverDataSource() datasource.DataSource {
+	return &porkbunDomainsByNameserverDataSource{}
+}
+
+type porkbunDomainsByNameserverDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunDomainsByNameserverDataSourceData struct {
+	Nameserver types.String        `tfsdk:"nameserver"`
+	Domains    map[string][]string `tfsdk:"domains"`
+	Groups     map[string][]string `tfsdk:"groups"`
+}
+
+func (d *porkbunDomainsByNameserverDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_domains_by_nameserver"
+}
+
+func (d *porkbunDomainsByNameserverDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the domains of the account with the nameservers currently set at the registry, so migrations like moving " +
+			"every domain still on Porkbun's nameservers elsewhere can be driven by `for_each`. " +
+			"The nameservers are retrieved one domain at a time, large accounts may want to raise `max_retries` for the rate limit.",
+
+		Attributes: map[string]schema.Attribute{
+			"nameserver": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Only list domains with a nameserver that is this host or under it, " +
+					"like `ns.porkbun.com` for Porkbun's own nameservers",
+			},
+			"domains": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.ListType{ElemType: types.StringType},
+				MarkdownDescription: "The nameservers of each domain, sorted and keyed by domain",
+			},
+			"groups": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+				MarkdownDescription: "The domains sharing the same nameservers, keyed by the sorted nameservers joined with commas, " +
+					"like `curitiba.ns.porkbun.com,fortaleza.ns.porkbun.com`",
+			},
+		},
+	}
+}
+
+func (d *porkbunDomainsByNameserverDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunDomainsByNameserverDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunDomainsByNameserverDataSourceData
+	rt := d.provider.runtime()
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domains, err := retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Domain, error) {
+		return rt.client.ListDomains(ctx)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not list domains.",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	filter := normalizeNameserver(data.Nameserver.ValueString())
+	data.Domains = map[string][]string{}
+	data.Groups = map[string][]string{}
+	for _, domain := range domains {
+		name := strings.ToLower(domain.Domain)
+		nameservers, err := retry(ctx, rt.retries, func(ctx context.Context) ([]string, error) {
+			return rt.client.GetNameservers(ctx, name)
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Could not retrieve the nameservers of %s.", name),
+				apiErrorDetail(err),
+			)
+			return
+		}
+
+		nameservers = sortedNameservers(nameservers)
+		if filter != "" && !nameserversUnder(nameservers, filter) {
+			continue
+		}
+		data.Domains[name] = nameservers
+		key := strings.Join(nameservers, ",")
+		data.Groups[key] = append(data.Groups[key], name)
+	}
+	for _, group := range data.Groups {
+		sort.Strings(group)
+	}
+	tflog.Debug(ctx, "Grouped domains by nameserver", map[string]any{"domain_count": len(domains), "matching_count": len(data.Domains)})
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// normalizeNameserver lowercases a nameserver and drops the trailing dot of a fully qualified name
+func normalizeNameserver(nameserver string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(nameserver), "."))
+}
+
+// sortedNameservers returns nameservers normalized and sorted, so domains with the same set group together
+func sortedNameservers(nameservers []string) []string {
+	sorted := make([]string, 0, len(nameservers))
+	for _, nameserver := range nameservers {
+		if nameserver = normalizeNameserver(nameserver); nameserver != "" {
+			sorted = append(sorted, nameserver)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// nameserversUnder reports whether any of nameservers is host or a name under it
+func nameserversUnder(nameservers []string, host string) bool {
+	for _, nameserver := range nameservers {
+		if nameserver == host || strings.HasSuffix(nameserver, "."+host) {
+			return true
+		}
+	}
+	return false
+}
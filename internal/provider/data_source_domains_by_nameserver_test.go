@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DomainsByNameserverDataSource(t *testing.T) {
+	server := newTestServer(t,
+		porkbuntest.WithDomain("foobar.dev"),
+		porkbuntest.WithDomain("other.dev"),
+		porkbuntest.WithNameservers("moved.dev", "Kim.NS.Cloudflare.com.", "bob.ns.cloudflare.com"),
+	)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `data "porkbun_domains_by_nameserver" "all" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_domains_by_nameserver.all", "domains.%", "3"),
+					resource.TestCheckResourceAttr("data.porkbun_domains_by_nameserver.all", "domains.moved.dev.#", "2"),
+					resource.TestCheckResourceAttr("data.porkbun_domains_by_nameserver.all", "domains.moved.dev.0", "bob.ns.cloudflare.com"),
+					resource.TestCheckResourceAttr("data.porkbun_domains_by_nameserver.all", "domains.moved.dev.1", "kim.ns.cloudflare.com"),
+					resource.TestCheckResourceAttr("data.porkbun_domains_by_nameserver.all", "groups.%", "2"),
+					resource.TestCheckResourceAttr("data.porkbun_domains_by_nameserver.all",
+						"groups.curitiba.ns.porkbun.com,fortaleza.ns.porkbun.com,maceio.ns.porkbun.com,salvador.ns.porkbun.com.#", "2"),
+				),
+			},
+			{
+				Config: `data "porkbun_domains_by_nameserver" "porkbun" { nameserver = "ns.porkbun.com." }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_domains_by_nameserver.porkbun", "domains.%", "2"),
+					resource.TestCheckResourceAttrSet("data.porkbun_domains_by_nameserver.porkbun", "domains.foobar.dev.0"),
+					resource.TestCheckNoResourceAttr("data.porkbun_domains_by_nameserver.porkbun", "domains.moved.dev.0"),
+				),
+			},
+		},
+	})
+}
+
+func Test_NameserversUnder(t *testing.T) {
+	r := require.New(t)
+
+	nameservers := sortedNameservers([]string{"Salvador.NS.Porkbun.com.", " curitiba.ns.porkbun.com", ""})
+	r.Equal([]string{"curitiba.ns.porkbun.com", "salvador.ns.porkbun.com"}, nameservers)
+
+	r.True(nameserversUnder(nameservers, "ns.porkbun.com"))
+	r.True(nameserversUnder(nameservers, "salvador.ns.porkbun.com"))
+	r.False(nameserversUnder(nameservers, "porkbun.co"))
+	r.False(nameserversUnder(nameservers, "bun.com"))
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunPricingDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunPricingDataSource{}
+
+func NewPricingDataSource() datasource.DataSource {
+	return &porkbunPricingDataSource{}
+}
+
+type porkbunPricingDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunPricingDataSourceData struct {
+	Tld          types.String        `tfsdk:"tld"`
+	Registration types.String        `tfsdk:"registration"`
+	Renewal      types.String        `tfsdk:"renewal"`
+	Transfer     types.String        `tfsdk:"transfer"`
+	SpecialType  types.String        `tfsdk:"special_type"`
+	OnSale       types.Bool          `tfsdk:"on_sale"`
+	Coupons      []porkbunCouponData `tfsdk:"coupons"`
+}
+
+type porkbunCouponData struct {
+	AppliesTo     types.String  `tfsdk:"applies_to"`
+	Code          types.String  `tfsdk:"code"`
+	Type          types.String  `tfsdk:"type"`
+	Amount        types.Float64 `tfsdk:"amount"`
+	FirstYearOnly types.Bool    `tfsdk:"first_year_only"`
+	MaxPerUser    types.Int64   `tfsdk:"max_per_user"`
+}
+
+func (d *porkbunPricingDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pricing"
+}
+
+func (d *porkbunPricingDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up what registering, renewing and transferring a domain under a TLD costs at Porkbun, " +
+			"including running promotions so automation can tell when a TLD is on sale.",
+
+		Attributes: map[string]schema.Attribute{
+			"tld": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The TLD to look up, like `dev`. A leading dot is ignored.",
+			},
+			"registration": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Price of the first year of a registration in USD, like `9.73`",
+			},
+			"renewal": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Price of a renewal in USD",
+			},
+			"transfer": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Price of a transfer in USD",
+			},
+			"special_type": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Set for TLDs with their own pricing rules, like `handshake`",
+			},
+			"on_sale": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Whether registering costs less than usual, because a registration coupon is running or the first year is cheaper than a renewal",
+			},
+			"coupons": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Promotion codes Porkbun advertises for the TLD",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"applies_to": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "What the coupon discounts, like `registration`",
+						},
+						"code": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The code to enter at checkout",
+						},
+						"type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "`amount` for a discount in USD or `percent`",
+						},
+						"amount": schema.Float64Attribute{
+							Computed:            true,
+							MarkdownDescription: "Size of the discount",
+						},
+						"first_year_only": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether only the first year is discounted",
+						},
+						"max_per_user": schema.Int64Attribute{
+							Computed:            true,
+							MarkdownDescription: "How often an account can use the coupon",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *porkbunPricingDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunPricingDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunPricingDataSourceData
+	rt := d.provider.runtime()
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tld := strings.ToLower(strings.TrimPrefix(data.Tld.ValueString(), "."))
+	ctx = tflog.SetField(ctx, "tld", tld)
+	pricing, err := retry(ctx, rt.retries, func(ctx context.Context) (map[string]porkbunapi.Pricing, error) {
+		return rt.client.GetPricing(ctx)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not retrieve pricing.",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	price, ok := pricing[tld]
+	if !ok {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("tld"),
+			"TLD not offered",
+			fmt.Sprintf("Porkbun doesn't offer domains under .%s", tld),
+		)
+		return
+	}
+
+	data.Registration = types.StringValue(price.Registration)
+	data.Renewal = types.StringValue(price.Renewal)
+	data.Transfer = types.StringValue(price.Transfer)
+	data.SpecialType = optionalString(price.SpecialType)
+	data.OnSale = types.BoolValue(price.OnSale())
+	data.Coupons = couponData(price.Coupons)
+	tflog.Debug(ctx, "Retrieved pricing", map[string]any{"on_sale": price.OnSale(), "coupon_count": len(price.Coupons)})
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// couponData lists the coupons ordered by what they apply to so the result is stable
+func couponData(coupons porkbunapi.Coupons) []porkbunCouponData {
+	appliesTo := make([]string, 0, len(coupons))
+	for key := range coupons {
+		appliesTo = append(appliesTo, key)
+	}
+	sort.Strings(appliesTo)
+
+	data := make([]porkbunCouponData, 0, len(coupons))
+	for _, key := range appliesTo {
+		coupon := coupons[key]
+		data = append(data, porkbunCouponData{
+			AppliesTo:     types.StringValue(key),
+			Code:          types.StringValue(coupon.Code),
+			Type:          types.StringValue(coupon.Type),
+			Amount:        types.Float64Value(coupon.Amount),
+			FirstYearOnly: types.BoolValue(strings.EqualFold(coupon.FirstYearOnly, "yes")),
+			MaxPerUser:    types.Int64Value(int64(coupon.MaxPerUser)),
+		})
+	}
+	return data
+}
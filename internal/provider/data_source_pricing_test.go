@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PricingDataSource(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          data "porkbun_pricing" "xyz" {
+            tld = ".XYZ"
+          }
+
+          data "porkbun_pricing" "dev" {
+            tld = "dev"
+          }
+        `,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_pricing.xyz", "registration", "2.04"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.xyz", "renewal", "12.98"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.xyz", "on_sale", "true"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.xyz", "coupons.#", "1"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.xyz", "coupons.0.applies_to", "registration"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.xyz", "coupons.0.code", "AWESOMENESS"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.xyz", "coupons.0.first_year_only", "true"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.dev", "registration", "10.81"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.dev", "on_sale", "false"),
+					resource.TestCheckResourceAttr("data.porkbun_pricing.dev", "coupons.#", "0"),
+					resource.TestCheckNoResourceAttr("data.porkbun_pricing.dev", "special_type"),
+				),
+			},
+			{
+				Config: `
+          data "porkbun_pricing" "test" {
+            tld = "invalid"
+          }
+        `,
+				ExpectError: regexp.MustCompile("TLD not offered"),
+			},
+		},
+	})
+}
+
+func Test_CouponData(t *testing.T) {
+	r := require.New(t)
+
+	data := couponData(porkbunapi.Coupons{
+		"transfer":     {Code: "MOVE", Type: "percent", Amount: 10, FirstYearOnly: "no"},
+		"registration": {Code: "NEW", Type: "amount", Amount: 1, FirstYearOnly: "yes", MaxPerUser: 1},
+	})
+	r.Len(data, 2)
+	r.Equal("registration", data[0].AppliesTo.ValueString())
+	r.True(data[0].FirstYearOnly.ValueBool())
+	r.Equal(int64(1), data[0].MaxPerUser.ValueInt64())
+	r.Equal("transfer", data[1].AppliesTo.ValueString())
+	r.False(data[1].FirstYearOnly.ValueBool())
+
+	r.Empty(couponData(nil))
+}
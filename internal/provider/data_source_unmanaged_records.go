@@ -0,0 +1,199 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunUnmanagedRecordsDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunUnmanagedRecordsDataSource{}
+
+// Porkbun serves the apex NS records itself, nobody manages those with a resource
+var defaultAuditIgnoreTypes = []string{"NS"}
+
+func NewUnmanagedRecordsDataSource() datasource.DataSource {
+	return &porkbunUnmanagedRecordsDataSource{}
+}
+
+type porkbunUnmanagedRecordsDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunUnmanagedRecordsDataSourceData struct {
+	Domain      types.String                 `tfsdk:"domain"`
+	ManagedIds  []types.String               `tfsdk:"managed_ids"`
+	IgnoreTypes types.List                   `tfsdk:"ignore_types"`
+	Warn        types.Bool                   `tfsdk:"warn"`
+	Records     []porkbunUnmanagedRecordData `tfsdk:"records"`
+}
+
+type porkbunUnmanagedRecordData struct {
+	Id      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Type    types.String `tfsdk:"type"`
+	Content types.String `tfsdk:"content"`
+}
+
+func (d *porkbunUnmanagedRecordsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_unmanaged_records"
+}
+
+func (d *porkbunUnmanagedRecordsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Audits a domain for records no resource manages. Pass the IDs of the `porkbun_dns_record` resources " +
+			"of the workspace and every other record of the zone is listed and, unless `warn` is false, reported as a warning on each plan and refresh.",
+
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain to audit",
+			},
+			"managed_ids": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "IDs of the records managed by this workspace, e.g. `[for r in porkbun_dns_record.all : r.id]`",
+			},
+			"ignore_types": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Record types left out of the audit, defaults to `" + strings.Join(defaultAuditIgnoreTypes, "`, `") +
+					"` since Porkbun creates the apex NS records itself",
+			},
+			"warn": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether unmanaged records are reported as warnings, defaults to true",
+			},
+			"records": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The records of the domain that aren't in `managed_ids`",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The Porkbun ID of the record, use it to import the record",
+						},
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The subdomain of the record without the base domain",
+						},
+						"type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The type of the record",
+						},
+						"content": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The content of the record",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *porkbunUnmanagedRecordsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunUnmanagedRecordsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunUnmanagedRecordsDataSourceData
+	rt := d.provider.runtime()
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ignoreTypes := defaultAuditIgnoreTypes
+	if !data.IgnoreTypes.IsNull() {
+		ignoreTypes = nil
+		resp.Diagnostics.Append(data.IgnoreTypes.ElementsAs(ctx, &ignoreTypes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	managed := map[string]bool{}
+	for _, id := range data.ManagedIds {
+		managed[id.ValueString()] = true
+	}
+
+	domain := data.Domain.ValueString()
+	ctx = tflog.SetField(ctx, "domain", domain)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+	// Resources refreshed in the same run have filled the cache already, so the audit is usually free
+	records, err := rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf(`Could not retrieve records for %s.`, domain),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	unmanaged := unmanagedRecords(records, managed, ignoreTypes)
+	tflog.Debug(ctx, "Audited records", map[string]any{"record_count": len(records), "unmanaged_count": len(unmanaged)})
+
+	data.Records = make([]porkbunUnmanagedRecordData, 0, len(unmanaged))
+	var summary []string
+	for _, record := range unmanaged {
+		name := relativeRecordName(record.Name, domain)
+		data.Records = append(data.Records, porkbunUnmanagedRecordData{
+			Id:      types.StringValue(record.ID),
+			Name:    types.StringValue(name),
+			Type:    types.StringValue(record.Type),
+			Content: types.StringValue(record.Content),
+		})
+		summary = append(summary, fmt.Sprintf("%s %s %s (ID %s)", record.Name, record.Type, record.Content, record.ID))
+	}
+
+	if len(unmanaged) > 0 && (data.Warn.IsNull() || data.Warn.ValueBool()) {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("%d unmanaged records in %s", len(unmanaged), domain),
+			"These records exist at Porkbun but no resource in this workspace manages them:\n\n"+strings.Join(summary, "\n")+
+				"\n\nImport them with a porkbun_dns_record resource or delete them if they are no longer needed.",
+		)
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// unmanagedRecords returns the records whose ID isn't in managed, skipping ignoreTypes, sorted like sortedRecords
+func unmanagedRecords(records map[string]porkbunapi.Record, managed map[string]bool, ignoreTypes []string) []porkbunapi.Record {
+	var unmanaged []porkbunapi.Record
+	for _, record := range sortedRecords(records) {
+		if managed[record.ID] || slices.ContainsFunc(ignoreTypes, func(t string) bool { return strings.EqualFold(t, record.Type) }) {
+			continue
+		}
+		unmanaged = append(unmanaged, record)
+	}
+	return unmanaged
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UnmanagedRecordsDataSource(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev",
+		porkbuntest.Record{Name: "foobar.dev", Type: "NS", Content: "curi.ns.porkbun.com"},
+		porkbuntest.Record{Name: "old.foobar.dev", Type: "A", Content: "192.0.2.1"},
+	))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_dns_record" "www" {
+            name    = "www"
+            domain  = "foobar.dev"
+            content = "0.0.0.1"
+            type    = "A"
+          }
+
+          data "porkbun_unmanaged_records" "audit" {
+            domain      = "foobar.dev"
+            managed_ids = [porkbun_dns_record.www.id]
+          }
+        `,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_unmanaged_records.audit", "records.#", "1"),
+					resource.TestCheckResourceAttr("data.porkbun_unmanaged_records.audit", "records.0.name", "old"),
+					resource.TestCheckResourceAttr("data.porkbun_unmanaged_records.audit", "records.0.type", "A"),
+					resource.TestCheckResourceAttr("data.porkbun_unmanaged_records.audit", "records.0.content", "192.0.2.1"),
+				),
+			},
+		},
+	})
+}
+
+func Test_UnmanagedRecords(t *testing.T) {
+	r := require.New(t)
+
+	records := indexRecords([]porkbunapi.Record{
+		{ID: "10", Name: "foobar.dev", Type: "NS", Content: "curi.ns.porkbun.com"},
+		{ID: "9", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"},
+		{ID: "11", Name: "foobar.dev", Type: "MX", Content: "mx.foobar.dev"},
+		{ID: "2", Name: "old.foobar.dev", Type: "A", Content: "192.0.2.1"},
+	})
+
+	unmanaged := unmanagedRecords(records, map[string]bool{"9": true}, defaultAuditIgnoreTypes)
+	r.Equal([]porkbunapi.Record{records["2"], records["11"]}, unmanaged)
+
+	unmanaged = unmanagedRecords(records, map[string]bool{"9": true}, []string{"mx", "a"})
+	r.Equal([]porkbunapi.Record{records["10"]}, unmanaged)
+
+	r.Empty(unmanagedRecords(records, map[string]bool{"2": true, "9": true, "10": true, "11": true}, nil))
+}
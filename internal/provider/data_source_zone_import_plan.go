@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &porkbunZoneImportPlanDataSource{}
+var _ datasource.DataSourceWithConfigure = &porkbunZoneImportPlanDataSource{}
+
+func NewZoneImportPlanDataSource() datasource.DataSource {
+	return &porkbunZoneImportPlanDataSource{}
+}
+
+type porkbunZoneImportPlanDataSource struct {
+	provider *porkbunProvider
+}
+
+type porkbunZoneImportPlanDataSourceData struct {
+	Domain      types.String              `tfsdk:"domain"`
+	IgnoreTypes types.List                `tfsdk:"ignore_types"`
+	Records     []porkbunZoneImportRecord `tfsdk:"records"`
+	Hcl         types.String              `tfsdk:"hcl"`
+}
+
+type porkbunZoneImportRecord struct {
+	Id       types.String `tfsdk:"id"`
+	Address  types.String `tfsdk:"address"`
+	ImportId types.String `tfsdk:"import_id"`
+	Hcl      types.String `tfsdk:"hcl"`
+}
+
+func (d *porkbunZoneImportPlanDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_zone_import_plan"
+}
+
+func (d *porkbunZoneImportPlanDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Suggests an `import` block and `porkbun_dns_record` resource for every record of a domain, so an existing zone " +
+			"can be brought under Terraform without writing them by hand. Write `hcl` to a file with `terraform console` or an output " +
+			"and review the resource names before applying.",
+
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain whose records to import",
+			},
+			"ignore_types": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Record types left out, defaults to `" + strings.Join(defaultAuditIgnoreTypes, "`, `") +
+					"` since Porkbun creates the apex NS records itself",
+			},
+			"records": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "What is suggested for each record, ordered by record ID",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The Porkbun ID of the record",
+						},
+						"address": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The suggested resource address, like `porkbun_dns_record.www_cname`",
+						},
+						"import_id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The ID to import the record with, `domain/id`",
+						},
+						"hcl": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "The import block and resource for the record",
+						},
+					},
+				},
+			},
+			"hcl": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The import blocks and resources of all records, ready to be written to a `.tf` file",
+			},
+		},
+	}
+}
+
+func (d *porkbunZoneImportPlanDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	d.provider = provider
+}
+
+func (d *porkbunZoneImportPlanDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunZoneImportPlanDataSourceData
+	rt := d.provider.runtime()
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ignoreTypes := defaultAuditIgnoreTypes
+	if !data.IgnoreTypes.IsNull() {
+		ignoreTypes = nil
+		resp.Diagnostics.Append(data.IgnoreTypes.ElementsAs(ctx, &ignoreTypes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	domain := strings.ToLower(data.Domain.ValueString())
+	ctx = tflog.SetField(ctx, "domain", domain)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+	records, err := rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf(`Could not retrieve records for %s.`, domain),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// No record is managed yet, so everything but the ignored types is suggested
+	imports := zoneImports(domain, unmanagedRecords(records, nil, ignoreTypes), rt.managedNotesMarker)
+	tflog.Debug(ctx, "Planned zone import", map[string]any{"record_count": len(records), "import_count": len(imports)})
+
+	data.Records = make([]porkbunZoneImportRecord, 0, len(imports))
+	hcl := make([]string, 0, len(imports))
+	for _, suggestion := range imports {
+		data.Records = append(data.Records, porkbunZoneImportRecord{
+			Id:       types.StringValue(suggestion.ID),
+			Address:  types.StringValue(suggestion.Address),
+			ImportId: types.StringValue(suggestion.ImportID),
+			Hcl:      types.StringValue(suggestion.HCL),
+		})
+		hcl = append(hcl, suggestion.HCL)
+	}
+	data.Hcl = types.StringValue(strings.Join(hcl, "\n"))
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
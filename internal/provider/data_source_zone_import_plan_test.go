@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func Test_ZoneImportPlanDataSource(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev",
+		porkbuntest.Record{Name: "", Type: "NS", Content: "curitiba.ns.porkbun.com"},
+		porkbuntest.Record{Name: "www", Type: "CNAME", Content: "foobar.dev"},
+	))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `data "porkbun_zone_import_plan" "test" { domain = "foobar.dev" }`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.porkbun_zone_import_plan.test", "records.#", "1"),
+					resource.TestCheckResourceAttr("data.porkbun_zone_import_plan.test", "records.0.address", "porkbun_dns_record.www_cname"),
+					resource.TestCheckResourceAttrPair("data.porkbun_zone_import_plan.test", "records.0.hcl", "data.porkbun_zone_import_plan.test", "hcl"),
+					resource.TestMatchResourceAttr("data.porkbun_zone_import_plan.test", "records.0.import_id", regexp.MustCompile(`^foobar\.dev/\d+$`)),
+				),
+			},
+			{
+				Config: `data "porkbun_zone_import_plan" "test" {
+  domain       = "foobar.dev"
+  ignore_types = []
+}`,
+				Check: resource.TestCheckResourceAttr("data.porkbun_zone_import_plan.test", "records.#", "2"),
+			},
+		},
+	})
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// delegationLookupFunc returns the nameservers the parent zone of domain delegates it to
+type delegationLookupFunc func(ctx context.Context, domain string) ([]string, error)
+
+// lookupDelegation asks the nameservers of the parent zone of domain, run by the registry of its TLD, which
+// nameservers it is delegated to. Resolvers answer from caches that hold the old delegation for up to two
+// days, while the parent serves what the registry has as soon as it published it.
+func lookupDelegation(ctx context.Context, domain string) ([]string, error) {
+	_, parent, ok := strings.Cut(normalizeDnsValue(domain), ".")
+	if !ok {
+		return nil, fmt.Errorf("%s has no parent zone", domain)
+	}
+
+	parentNameservers, err := lookupRecord(ctx, defaultPropagationResolvers[0], parent, "NS")
+	if err != nil {
+		return nil, fmt.Errorf("looking up the nameservers of %s: %w", parent, err)
+	}
+	if len(parentNameservers) == 0 {
+		return nil, fmt.Errorf("%s has no nameservers", parent)
+	}
+
+	var errs []error
+	for _, nameserver := range parentNameservers {
+		delegated, err := queryDelegation(ctx, net.JoinHostPort(nameserver, "53"), domain)
+		if err == nil {
+			return delegated, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", nameserver, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// queryDelegation asks the nameserver at address for the NS records of domain without recursion. A parent
+// zone answers with a referral, the delegation is in the authority section of the response.
+func queryDelegation(ctx context.Context, address string, domain string) ([]string, error) {
+	name, err := dnsmessage.NewName(normalizeDnsValue(domain) + ".")
+	if err != nil {
+		return nil, err
+	}
+
+	id := uint16(rand.N(1 << 16))
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(1232, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, err
+	}
+	query, err := (&dnsmessage.Message{
+		Header:      dnsmessage.Header{ID: id},
+		Questions:   []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeNS, Class: dnsmessage.ClassINET}},
+		Additionals: []dnsmessage.Resource{{Header: opt, Body: &dnsmessage.OPTResource{}}},
+	}).Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultLookupTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 1232)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		var response dnsmessage.Message
+		if err := response.Unpack(buf[:n]); err != nil || response.Header.ID != id || !response.Header.Response {
+			// Not the answer to this query
+			continue
+		}
+		if response.Header.RCode == dnsmessage.RCodeNameError {
+			return []string{}, nil
+		}
+		if response.Header.RCode != dnsmessage.RCodeSuccess {
+			return nil, fmt.Errorf("the query for the nameservers of %s failed with %s", domain, response.Header.RCode)
+		}
+
+		nameservers := []string{}
+		for _, rr := range append(response.Answers, response.Authorities...) {
+			ns, ok := rr.Body.(*dnsmessage.NSResource)
+			if ok && strings.EqualFold(rr.Header.Name.String(), name.String()) {
+				nameservers = append(nameservers, strings.ToLower(normalizeDnsValue(ns.NS.String())))
+			}
+		}
+		sort.Strings(nameservers)
+		return nameservers, nil
+	}
+}
+
+// waitForDelegation checks the delegation of domain every interval until the parent zone serves
+// nameservers or timeout passes
+func waitForDelegation(ctx context.Context, lookup delegationLookupFunc, domain string, nameservers []string, interval time.Duration, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		delegated, err := lookup(ctx, domain)
+		if err == nil && sameNameservers(delegated, nameservers) {
+			return nil
+		}
+		if err != nil {
+			tflog.Debug(ctx, "Unable to look up the delegation", map[string]any{"error": err.Error()})
+		} else {
+			tflog.Debug(ctx, "Waiting for the registry to delegate to the nameservers", map[string]any{"delegated": delegated})
+		}
+
+		select {
+		case <-ctx.Done():
+			switch {
+			case err != nil:
+				return fmt.Errorf("the delegation of %s couldn't be checked within %s: %w", domain, timeout, err)
+			case len(delegated) == 0:
+				return fmt.Errorf("the registry didn't delegate %s to any nameservers within %s", domain, timeout)
+			default:
+				return fmt.Errorf("the registry still delegates %s to %s after %s", domain, strings.Join(delegated, ", "), timeout)
+			}
+		case <-time.After(interval):
+		}
+	}
+}
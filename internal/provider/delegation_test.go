@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func Test_QueryDelegation(t *testing.T) {
+	r := require.New(t)
+
+	parent := newTestResolver(t, map[string][]dnsmessage.Resource{
+		"foobar.dev.": {
+			testResource("foobar.dev.", &dnsmessage.NSResource{NS: dnsmessage.MustNewName("Kim.NS.Cloudflare.com.")}),
+			testResource("foobar.dev.", &dnsmessage.NSResource{NS: dnsmessage.MustNewName("bob.ns.cloudflare.com.")}),
+		},
+	})
+
+	delegated, err := queryDelegation(context.Background(), parent, "foobar.dev")
+	r.NoError(err)
+	r.Equal([]string{"bob.ns.cloudflare.com", "kim.ns.cloudflare.com"}, delegated)
+
+	// Domains the registry doesn't delegate at all have no nameservers
+	delegated, err = queryDelegation(context.Background(), parent, "missing.dev")
+	r.NoError(err)
+	r.Empty(delegated)
+}
+
+func Test_WaitForDelegation(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cloudflare := []string{"kim.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+
+	// The registry publishes the new nameservers on the third check
+	checks := 0
+	lookup := func(ctx context.Context, domain string) ([]string, error) {
+		checks++
+		switch checks {
+		case 1:
+			return nil, errors.New("i/o timeout")
+		case 2:
+			return porkbunDefaultNameservers, nil
+		}
+		return []string{"bob.ns.cloudflare.com", "kim.ns.cloudflare.com"}, nil
+	}
+	r.NoError(waitForDelegation(ctx, lookup, "foobar.dev", cloudflare, time.Millisecond, time.Minute))
+	r.Equal(3, checks)
+
+	stale := func(ctx context.Context, domain string) ([]string, error) {
+		return porkbunDefaultNameservers, nil
+	}
+	r.ErrorContains(waitForDelegation(ctx, stale, "foobar.dev", cloudflare, time.Millisecond, 10*time.Millisecond), "still delegates foobar.dev to curitiba.ns.porkbun.com")
+}
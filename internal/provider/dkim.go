@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+)
+
+// isDKIMRecord reports whether a record publishes a DKIM key, a TXT record named selector._domainkey
+func isDKIMRecord(recordType string, name string) bool {
+	if !strings.EqualFold(recordType, "TXT") {
+		return false
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i, label := range labels {
+		if label == "_domainkey" {
+			return i > 0
+		}
+	}
+	return false
+}
+
+// validateDKIM checks that content is a DKIM key record as described in RFC 6376 section 3.6.1: tags
+// separated by semicolons, an optional v=DKIM1 first and a p tag with the base64 public key. An empty p
+// marks a revoked key.
+func validateDKIM(content string) error {
+	tags := map[string]string{}
+	for i, tag := range strings.Split(txtValue(content), ";") {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(tag, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return fmt.Errorf("%q isn't a tag=value pair", strings.TrimSpace(tag))
+		}
+		if _, ok := tags[name]; ok {
+			return fmt.Errorf("the %s tag is given more than once", name)
+		}
+		tags[name] = strings.TrimSpace(value)
+
+		if name == "v" && (i != 0 || tags[name] != "DKIM1") {
+			return fmt.Errorf("v must be the first tag and be DKIM1")
+		}
+	}
+
+	key, ok := tags["p"]
+	if !ok {
+		return fmt.Errorf("the p tag with the public key is missing")
+	}
+	// Whitespace is allowed anywhere in the key
+	key = strings.Join(strings.Fields(key), "")
+	if key == "" {
+		return nil
+	}
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("the p tag isn't base64")
+	}
+
+	switch algorithm := tags["k"]; algorithm {
+	case "", "rsa":
+		public, err := x509.ParsePKIXPublicKey(der)
+		if _, ok := public.(*rsa.PublicKey); err != nil || !ok {
+			return fmt.Errorf("the p tag isn't an RSA public key")
+		}
+	case "ed25519":
+		if len(der) != ed25519.PublicKeySize {
+			return fmt.Errorf("the p tag isn't an Ed25519 public key, expected %d bytes but got %d", ed25519.PublicKeySize, len(der))
+		}
+	default:
+		return fmt.Errorf("unknown key type %q, expected rsa or ed25519", algorithm)
+	}
+	return nil
+}
+
+// dkimSelectorConflict finds a TXT record other than the planned one at the DKIM selector fqdn, another
+// key published there would be replaced or shadowed without anyone noticing
+func dkimSelectorConflict(records []porkbunapi.Record, fqdn string, content string) (porkbunapi.Record, bool) {
+	planned := strings.Join(strings.Fields(txtValue(content)), "")
+	for _, record := range records {
+		if !strings.EqualFold(record.Type, "TXT") || !strings.EqualFold(normalizeDnsValue(record.Name), normalizeDnsValue(fqdn)) {
+			continue
+		}
+		if strings.Join(strings.Fields(txtValue(record.Content)), "") != planned {
+			return record, true
+		}
+	}
+	return porkbunapi.Record{}, false
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func testDKIMKeys(t *testing.T) (string, string) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(der), base64.StdEncoding.EncodeToString(edKey)
+}
+
+func Test_IsDKIMRecord(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isDKIMRecord("TXT", "mail._domainkey"))
+	r.True(isDKIMRecord("txt", "s1._DomainKey.eu"))
+	r.False(isDKIMRecord("CNAME", "mail._domainkey"))
+	r.False(isDKIMRecord("TXT", "_domainkey"))
+	r.False(isDKIMRecord("TXT", "mail"))
+}
+
+func Test_ValidateDKIM(t *testing.T) {
+	rsaKey, edKey := testDKIMKeys(t)
+
+	valid := []string{
+		"v=DKIM1; k=rsa; p=" + rsaKey,
+		"p=" + rsaKey,
+		"v=DKIM1; k=ed25519; p=" + edKey,
+		`"v=DKIM1; k=rsa; " "p=` + rsaKey[:100] + `" "` + rsaKey[100:] + `"`,
+		"v=DKIM1; p=" + rsaKey[:100] + " " + rsaKey[100:] + ";",
+		// Revoked
+		"v=DKIM1; p=",
+	}
+	for _, content := range valid {
+		require.NoError(t, validateDKIM(content), content)
+	}
+
+	invalid := map[string]string{
+		"v=DKIM1; k=rsa":                  "p tag with the public key is missing",
+		"k=rsa; v=DKIM1; p=" + rsaKey:     "v must be the first tag",
+		"v=DKIM2; p=" + rsaKey:            "v must be the first tag",
+		"v=DKIM1; p=not%base64":           "isn't base64",
+		"v=DKIM1; p=" + edKey:             "isn't an RSA public key",
+		"v=DKIM1; k=ed25519; p=" + rsaKey: "isn't an Ed25519 public key",
+		"v=DKIM1; k=dsa; p=" + rsaKey:     `unknown key type "dsa"`,
+		"v=DKIM1; p=" + rsaKey + "; p=":   "more than once",
+		"v=DKIM1; " + rsaKey:              "isn't a tag=value pair",
+	}
+	for content, expected := range invalid {
+		require.ErrorContains(t, validateDKIM(content), expected, content)
+	}
+}
+
+func Test_DKIMSelectorConflict(t *testing.T) {
+	r := require.New(t)
+	records := []porkbunapi.Record{
+		{ID: "1", Name: "mail._domainkey.foobar.dev", Type: "CNAME", Content: "mail.dkim.example."},
+		{ID: "2", Name: "s1._domainkey.foobar.dev", Type: "TXT", Content: `"v=DKIM1; p=abc" "def"`},
+	}
+
+	existing, ok := dkimSelectorConflict(records, "s1._domainkey.foobar.dev", "v=DKIM1; p=other")
+	r.True(ok)
+	r.Equal("2", existing.ID)
+
+	// The same key split differently isn't a conflict
+	_, ok = dkimSelectorConflict(records, "S1._domainkey.foobar.dev.", "v=DKIM1; p=abcdef")
+	r.False(ok)
+	_, ok = dkimSelectorConflict(records, "mail._domainkey.foobar.dev", "v=DKIM1; p=other")
+	r.False(ok)
+	_, ok = dkimSelectorConflict(records, "s2._domainkey.foobar.dev", "v=DKIM1; p=other")
+	r.False(ok)
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// isDMARCRecord reports whether a record holds a DMARC policy, a TXT record named _dmarc for the domain
+// or one of its subdomains
+func isDMARCRecord(recordType string, name string) bool {
+	label, _, _ := strings.Cut(name, ".")
+	return strings.EqualFold(recordType, "TXT") && strings.EqualFold(label, "_dmarc")
+}
+
+// dmarcWarnings lints a DMARC policy for configurations that are valid but weak. Content that isn't a
+// DMARC1 policy is left alone.
+func dmarcWarnings(content string) []string {
+	tags := map[string]string{}
+	for _, tag := range strings.Split(content, ";") {
+		key, value, _ := strings.Cut(tag, "=")
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	if !strings.EqualFold(tags["v"], "DMARC1") {
+		return nil
+	}
+
+	var warnings []string
+	policy, hasPolicy := tags["p"]
+	_, hasReports := tags["rua"]
+	switch {
+	case !hasPolicy:
+		warnings = append(warnings, "The policy has no p tag, receivers will ignore it.")
+	case strings.EqualFold(policy, "none") && !hasReports:
+		warnings = append(warnings, "p=none without rua doesn't protect the domain and sends no aggregate reports, "+
+			"so there is nothing to learn from before tightening the policy.")
+	}
+
+	if pct, ok := tags["pct"]; ok {
+		if value, err := strconv.Atoi(pct); err == nil && value < 100 {
+			warnings = append(warnings, fmt.Sprintf("pct=%d applies the policy to only part of the mail failing DMARC.", value))
+		}
+	}
+
+	_, hasDKIMAlignment := tags["adkim"]
+	_, hasSPFAlignment := tags["aspf"]
+	if !hasDKIMAlignment && !hasSPFAlignment {
+		warnings = append(warnings, "Neither adkim nor aspf is set, so both default to relaxed alignment. "+
+			"Set them to s for strict alignment or to r to make relaxed alignment explicit.")
+	}
+
+	return warnings
+}
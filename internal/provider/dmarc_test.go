@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsDMARCRecord(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isDMARCRecord("TXT", "_dmarc"))
+	r.True(isDMARCRecord("txt", "_DMARC.mail"))
+	r.False(isDMARCRecord("CNAME", "_dmarc"))
+	r.False(isDMARCRecord("TXT", "mail._dmarc"))
+	r.False(isDMARCRecord("TXT", ""))
+}
+
+func Test_DMARCWarnings(t *testing.T) {
+	tests := map[string][]string{
+		"v=DMARC1; p=reject; adkim=s; aspf=s":                    nil,
+		"v=DMARC1; p=none; rua=mailto:dmarc@foobar.dev; adkim=r": nil,
+		"v=spf1 -all":                                                   nil,
+		"v=DMARC1; p=none; adkim=s":                                     {"p=none without rua"},
+		"v=DMARC1; p=quarantine; pct=25; aspf=r":                        {"pct=25"},
+		"v=DMARC1; p=reject":                                            {"Neither adkim nor aspf"},
+		"v=DMARC1; rua=mailto:dmarc@foobar.dev; adkim=s":                {"no p tag"},
+		"V=dmarc1;p=none;pct=50":                                        {"p=none without rua", "pct=50", "Neither adkim nor aspf"},
+		"v=DMARC1; p=reject; pct=100; adkim=s; rua=mailto:d@foobar.dev": nil,
+	}
+	for content, expected := range tests {
+		t.Run(content, func(t *testing.T) {
+			warnings := dmarcWarnings(content)
+			require.Len(t, warnings, len(expected))
+			for i, warning := range warnings {
+				require.Contains(t, warning, expected[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Public resolvers queried when a configuration doesn't name its own
+var defaultPropagationResolvers = []string{"1.1.1.1", "8.8.8.8", "9.9.9.9"}
+
+// Record types that can be looked up, the standard library resolver doesn't support the others
+var lookupRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "SRV", "TXT"}
+
+// Porkbun's authoritative nameservers, asking them directly sees a change as soon as it's live without
+// waiting for cached answers to expire
+var porkbunNameservers = nameserverAddresses(porkbunDefaultNameservers)
+
+const defaultLookupTimeout = 5 * time.Second
+
+// How often resolvers are checked while waiting for propagation, unless propagation_interval is set
+const defaultPropagationInterval = 10 * time.Second
+
+// dnsLookupFunc returns the values resolver has for name and recordType, formatted like Porkbun record content
+type dnsLookupFunc func(ctx context.Context, resolver string, name string, recordType string) ([]string, error)
+
+type propagationResult struct {
+	Resolver string
+	Values   []string
+	Error    string
+	Matches  bool
+}
+
+// checkPropagation queries every resolver concurrently. A resolver matches when it has expected among its
+// values, or any value at all when expected is empty.
+func checkPropagation(ctx context.Context, lookup dnsLookupFunc, resolvers []string, name string, recordType string, expected string) []propagationResult {
+	results := make([]propagationResult, len(resolvers))
+
+	var wg sync.WaitGroup
+	for i, resolver := range resolvers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result := propagationResult{Resolver: resolver}
+			values, err := lookup(ctx, resolver, name, recordType)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Values = values
+			result.Matches = valuesMatch(values, expected)
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// waitForPropagation checks the resolvers every interval until all of them have expected or timeout passes
+func waitForPropagation(ctx context.Context, lookup dnsLookupFunc, resolvers []string, name string, recordType string, expected string, interval time.Duration, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		var pending []string
+		for _, result := range checkPropagation(ctx, lookup, resolvers, name, recordType, expected) {
+			if !result.Matches {
+				pending = append(pending, result.Resolver)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		tflog.Debug(ctx, "Waiting for record to propagate", map[string]any{"pending_resolvers": pending})
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s %s didn't show %q on %s within %s", name, recordType, expected, strings.Join(pending, ", "), timeout)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// expectedLookupValue turns record content into the form lookupRecord returns it in
+func expectedLookupValue(recordType string, content string) string {
+	if strings.EqualFold(recordType, "TXT") && strings.HasPrefix(content, `"`) {
+		if joined, err := joinTXT(content); err == nil {
+			return joined
+		}
+	}
+	return normalizeDnsValue(content)
+}
+
+// recordFQDN is the name a record with the relative name is served at
+func recordFQDN(name string, domain string) string {
+	if name == "" {
+		return domain
+	}
+	return name + "." + domain
+}
+
+// liveDnsWarnings looks a record that is about to be created up on resolver and describes anything
+// suggesting it won't resolve as planned once it exists. Failed lookups are only logged.
+func liveDnsWarnings(ctx context.Context, lookup dnsLookupFunc, resolver string, domain string, name string, recordType string, expected string) []string {
+	var warnings []string
+
+	nameservers, err := lookup(ctx, resolver, domain, "NS")
+	if err != nil {
+		tflog.Debug(ctx, "Unable to look up nameservers", map[string]any{"error": err.Error()})
+	} else if len(nameservers) > 0 && !delegatedToPorkbun(nameservers) {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s is delegated to %s, not to Porkbun. Records created at Porkbun won't resolve until the nameservers are changed, "+
+				"wait_for_delegation creates them only after that.",
+			domain, strings.Join(nameservers, ", "),
+		))
+	}
+
+	values, err := lookup(ctx, resolver, name, recordType)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to look up record", map[string]any{"error": err.Error()})
+	} else if len(values) > 0 && !valuesMatch(values, expected) {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s already resolves to %s as a %s record. The new record will be served alongside it or conflict with it.",
+			name, strings.Join(values, ", "), recordType,
+		))
+	}
+
+	return warnings
+}
+
+func delegatedToPorkbun(nameservers []string) bool {
+	for _, ns := range nameservers {
+		if strings.HasSuffix(strings.ToLower(normalizeDnsValue(ns)), ".porkbun.com") {
+			return true
+		}
+	}
+	return false
+}
+
+func valuesMatch(values []string, expected string) bool {
+	if expected == "" {
+		return len(values) > 0
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, normalizeDnsValue(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupRecord asks resolver directly, bypassing the system configuration and its caches
+func lookupRecord(ctx context.Context, resolver string, name string, recordType string) ([]string, error) {
+	address, err := resolverAddress(resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, address)
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultLookupTimeout)
+	defer cancel()
+
+	var values []string
+	switch strings.ToUpper(recordType) {
+	case "A", "AAAA":
+		network := "ip4"
+		if strings.EqualFold(recordType, "AAAA") {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, name)
+		if err != nil {
+			return notFoundIsEmpty(err)
+		}
+		for _, ip := range ips {
+			values = append(values, ip.String())
+		}
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return notFoundIsEmpty(err)
+		}
+		// Names without a CNAME come back unchanged
+		if !strings.EqualFold(normalizeDnsValue(cname), normalizeDnsValue(name)) {
+			values = append(values, cname)
+		}
+	case "MX":
+		mxs, err := r.LookupMX(ctx, name)
+		if err != nil {
+			return notFoundIsEmpty(err)
+		}
+		for _, mx := range mxs {
+			values = append(values, mx.Host)
+		}
+	case "NS":
+		nss, err := r.LookupNS(ctx, name)
+		if err != nil {
+			return notFoundIsEmpty(err)
+		}
+		for _, ns := range nss {
+			values = append(values, ns.Host)
+		}
+	case "SRV":
+		_, srvs, err := r.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return notFoundIsEmpty(err)
+		}
+		// Porkbun keeps the priority separately, the content is weight, port and target
+		for _, srv := range srvs {
+			values = append(values, fmt.Sprintf("%d %d %s", srv.Weight, srv.Port, srv.Target))
+		}
+	case "TXT":
+		txts, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return notFoundIsEmpty(err)
+		}
+		values = append(values, txts...)
+	default:
+		return nil, fmt.Errorf("looking up %s records is not supported, expected one of %s", recordType, strings.Join(lookupRecordTypes, ", "))
+	}
+
+	for i, value := range values {
+		values[i] = normalizeDnsValue(value)
+	}
+	sort.Strings(values)
+	return values, nil
+}
+
+// notFoundIsEmpty turns an answer without records into an empty result, a missing record isn't a failure to look it up
+func notFoundIsEmpty(err error) ([]string, error) {
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		return []string{}, nil
+	}
+	return nil, err
+}
+
+// nameserverAddresses adds the DNS port to nameserver host names so they can be queried as resolvers
+func nameserverAddresses(nameservers []string) []string {
+	addresses := make([]string, len(nameservers))
+	for i, nameserver := range nameservers {
+		addresses[i] = net.JoinHostPort(nameserver, "53")
+	}
+	return addresses
+}
+
+// resolverAddress adds the DNS port to resolvers given as a bare IP address
+func resolverAddress(resolver string) (string, error) {
+	if ip := net.ParseIP(resolver); ip != nil {
+		return net.JoinHostPort(resolver, "53"), nil
+	}
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		return "", fmt.Errorf("resolver %q is neither an IP address nor host:port", resolver)
+	}
+	return resolver, nil
+}
+
+// normalizeDnsValue drops the trailing dot resolvers put on names so they compare equal to record content
+func normalizeDnsValue(value string) string {
+	return strings.TrimSuffix(value, ".")
+}
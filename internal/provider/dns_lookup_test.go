@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// newTestResolver answers DNS queries over UDP from records, keyed by absolute name. CNAMEs are
+// returned for queries of any type, like a real server does. Unknown names get NXDOMAIN.
+func newTestResolver(t *testing.T, records map[string][]dnsmessage.Resource) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var p dnsmessage.Parser
+			header, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			question, err := p.Question()
+			if err != nil {
+				continue
+			}
+
+			resp := dnsmessage.Message{
+				Header: dnsmessage.Header{
+					ID:                 header.ID,
+					Response:           true,
+					Authoritative:      true,
+					RecursionDesired:   header.RecursionDesired,
+					RecursionAvailable: true,
+				},
+				Questions: []dnsmessage.Question{question},
+			}
+			known, ok := records[strings.ToLower(question.Name.String())]
+			if !ok {
+				resp.Header.RCode = dnsmessage.RCodeNameError
+			}
+			for _, rr := range known {
+				if rr.Header.Type == question.Type || rr.Header.Type == dnsmessage.TypeCNAME {
+					resp.Answers = append(resp.Answers, rr)
+				}
+			}
+
+			out, err := resp.Pack()
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(out, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func testResource(name string, body dnsmessage.ResourceBody) dnsmessage.Resource {
+	var recordType dnsmessage.Type
+	switch body.(type) {
+	case *dnsmessage.AResource:
+		recordType = dnsmessage.TypeA
+	case *dnsmessage.CNAMEResource:
+		recordType = dnsmessage.TypeCNAME
+	case *dnsmessage.MXResource:
+		recordType = dnsmessage.TypeMX
+	case *dnsmessage.TXTResource:
+		recordType = dnsmessage.TypeTXT
+	case *dnsmessage.NSResource:
+		recordType = dnsmessage.TypeNS
+	}
+
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{
+			Name:  dnsmessage.MustNewName(name),
+			Type:  recordType,
+			Class: dnsmessage.ClassINET,
+			TTL:   600,
+		},
+		Body: body,
+	}
+}
+
+func Test_LookupRecord(t *testing.T) {
+	resolver := newTestResolver(t, map[string][]dnsmessage.Resource{
+		"foobar.dev.": {
+			testResource("foobar.dev.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}),
+			testResource("foobar.dev.", &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mx.foobar.dev.")}),
+			testResource("foobar.dev.", &dnsmessage.TXTResource{TXT: []string{"v=spf1", " -all"}}),
+		},
+		"www.foobar.dev.": {
+			testResource("www.foobar.dev.", &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("foobar.dev.")}),
+		},
+	})
+
+	tests := []struct {
+		name       string
+		recordType string
+		expected   []string
+	}{
+		{"foobar.dev", "A", []string{"192.0.2.1"}},
+		{"foobar.dev", "mx", []string{"mx.foobar.dev"}},
+		{"foobar.dev", "TXT", []string{"v=spf1 -all"}},
+		{"www.foobar.dev", "CNAME", []string{"foobar.dev"}},
+		{"missing.foobar.dev", "A", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.recordType, func(t *testing.T) {
+			values, err := lookupRecord(context.Background(), resolver, tt.name, tt.recordType)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, values)
+		})
+	}
+
+	_, err := lookupRecord(context.Background(), resolver, "foobar.dev", "CAA")
+	require.ErrorContains(t, err, "not supported")
+}
+
+func Test_ResolverAddress(t *testing.T) {
+	r := require.New(t)
+
+	address, err := resolverAddress("1.1.1.1")
+	r.NoError(err)
+	r.Equal("1.1.1.1:53", address)
+
+	address, err = resolverAddress("2606:4700:4700::1111")
+	r.NoError(err)
+	r.Equal("[2606:4700:4700::1111]:53", address)
+
+	address, err = resolverAddress("127.0.0.1:5353")
+	r.NoError(err)
+	r.Equal("127.0.0.1:5353", address)
+
+	_, err = resolverAddress("one.one.one.one")
+	r.Error(err)
+}
+
+func Test_PorkbunNameserverResolvers(t *testing.T) {
+	r := require.New(t)
+
+	// wait_for_propagation asks the nameservers Porkbun delegates its zones to
+	r.Len(porkbunNameservers, len(porkbunDefaultNameservers))
+	for i, resolver := range porkbunNameservers {
+		host, port, err := net.SplitHostPort(resolver)
+		r.NoError(err)
+		r.Equal("53", port)
+		r.Equal(porkbunDefaultNameservers[i], host)
+		r.True(strings.HasSuffix(host, ".ns.porkbun.com"), host)
+	}
+	r.Contains(porkbunNameservers, "curitiba.ns.porkbun.com:53")
+}
+
+func Test_CheckPropagation(t *testing.T) {
+	r := require.New(t)
+
+	lookup := func(ctx context.Context, resolver string, name string, recordType string) ([]string, error) {
+		switch resolver {
+		case "fresh":
+			return []string{"0.0.0.1", "0.0.0.2"}, nil
+		case "stale":
+			return []string{"0.0.0.9"}, nil
+		default:
+			return nil, errors.New("i/o timeout")
+		}
+	}
+
+	results := checkPropagation(context.Background(), lookup, []string{"fresh", "stale", "down"}, "foobar.dev", "A", "0.0.0.2")
+	r.Equal([]propagationResult{
+		{Resolver: "fresh", Values: []string{"0.0.0.1", "0.0.0.2"}, Matches: true},
+		{Resolver: "stale", Values: []string{"0.0.0.9"}, Matches: false},
+		{Resolver: "down", Error: "i/o timeout", Matches: false},
+	}, results)
+
+	// Without an expected value any answer will do
+	results = checkPropagation(context.Background(), lookup, []string{"stale"}, "foobar.dev", "A", "")
+	r.True(results[0].Matches)
+
+	// Names compare without the trailing dot and case
+	r.True(valuesMatch([]string{"mx.foobar.dev"}, "MX.foobar.dev."))
+}
+
+func Test_ExpectedLookupValue(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("v=spf1 -all", expectedLookupValue("TXT", `"v=spf1 " "-all"`))
+	r.Equal("v=spf1 -all", expectedLookupValue("TXT", "v=spf1 -all"))
+	r.Equal("mx.foobar.dev", expectedLookupValue("MX", "mx.foobar.dev."))
+
+	r.Equal("foobar.dev", recordFQDN("", "foobar.dev"))
+	r.Equal("www.foobar.dev", recordFQDN("www", "foobar.dev"))
+}
+
+func Test_LiveDnsWarnings(t *testing.T) {
+	r := require.New(t)
+
+	answers := map[string][]string{
+		"foobar.dev NS":     {"ns1.elsewhere.net", "ns2.elsewhere.net"},
+		"www.foobar.dev A":  {"192.0.2.1"},
+		"porkbun.dev NS":    {"curi.ns.porkbun.com", "maceio.ns.porkbun.com"},
+		"www.porkbun.dev A": {"0.0.0.1"},
+	}
+	lookup := func(ctx context.Context, resolver string, name string, recordType string) ([]string, error) {
+		if name == "broken.dev" {
+			return nil, errors.New("i/o timeout")
+		}
+		return answers[name+" "+recordType], nil
+	}
+
+	warnings := liveDnsWarnings(context.Background(), lookup, "1.1.1.1", "foobar.dev", "www.foobar.dev", "A", "0.0.0.1")
+	r.Len(warnings, 2)
+	r.Contains(warnings[0], "delegated to ns1.elsewhere.net, ns2.elsewhere.net")
+	r.Contains(warnings[1], "already resolves to 192.0.2.1")
+
+	r.Empty(liveDnsWarnings(context.Background(), lookup, "1.1.1.1", "porkbun.dev", "www.porkbun.dev", "A", "0.0.0.1"))
+	r.Empty(liveDnsWarnings(context.Background(), lookup, "1.1.1.1", "porkbun.dev", "new.porkbun.dev", "A", "0.0.0.1"))
+	r.Empty(liveDnsWarnings(context.Background(), lookup, "1.1.1.1", "broken.dev", "www.porkbun.dev", "A", "0.0.0.1"))
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// reverseName returns the in-addr.arpa or ip6.arpa name for an IP address
+func reverseName(address string) (string, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", fmt.Errorf("%q is not an IP address", address)
+	}
+
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", v4[3], v4[2], v4[1], v4[0]), nil
+	}
+
+	const hexDigits = "0123456789abcdef"
+	nibbles := make([]string, 0, 32)
+	for i := len(ip) - 1; i >= 0; i-- {
+		nibbles = append(nibbles, string(hexDigits[ip[i]&0x0f]), string(hexDigits[ip[i]>>4]))
+	}
+	return strings.Join(nibbles, ".") + ".ip6.arpa", nil
+}
+
+// A single TXT character-string is limited to 255 bytes on the wire
+const maxTXTChunkBytes = 255
+
+// txtChunks splits value into quoted character-strings of at most 255 bytes each
+// without cutting a UTF-8 sequence in half.
+func txtChunks(value string) []string {
+	chunks := []string{}
+	for len(value) > 0 || len(chunks) == 0 {
+		end := len(value)
+		if end > maxTXTChunkBytes {
+			end = maxTXTChunkBytes
+			// Step back to the start of a rune, continuation bytes look like 10xxxxxx
+			for end > 0 && value[end]&0xc0 == 0x80 {
+				end--
+			}
+		}
+		chunks = append(chunks, quoteTXT(value[:end]))
+		value = value[end:]
+	}
+	return chunks
+}
+
+func quoteTXT(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return `"` + escaped + `"`
+}
+
+// joinTXT reverses txtChunks, concatenating the quoted or bare character-strings in content
+func joinTXT(content string) (string, error) {
+	var joined strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\\':
+			if i+1 >= len(content) {
+				return "", fmt.Errorf("content ends with a dangling escape")
+			}
+			i++
+			joined.WriteByte(content[i])
+		case c == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			// Whitespace between character-strings isn't part of the value
+		default:
+			joined.WriteByte(c)
+		}
+	}
+
+	if inQuotes {
+		return "", fmt.Errorf("content has an unterminated quoted string")
+	}
+	return joined.String(), nil
+}
+
+// txtValue is the value of TXT content, long values are often split into quoted character-strings
+func txtValue(content string) string {
+	if strings.HasPrefix(strings.TrimSpace(content), `"`) {
+		if joined, err := joinTXT(content); err == nil {
+			return joined
+		}
+	}
+	return content
+}
+
+// txtEquivalent reports whether two TXT contents hold the same value, however it is split into quoted
+// character-strings and whatever whitespace surrounds it
+func txtEquivalent(a string, b string) bool {
+	return strings.TrimSpace(txtValue(a)) == strings.TrimSpace(txtValue(b))
+}
+
+var srvServicePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,13}[a-z0-9])?$`)
+var srvProtocolPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// srvName builds the _service._proto owner name of an SRV record. service and protocol may be
+// given with or without their leading underscore, name is the record name below the domain
+// and may be empty for the apex.
+func srvName(service string, protocol string, name string) (string, error) {
+	service = strings.ToLower(strings.TrimPrefix(service, "_"))
+	protocol = strings.ToLower(strings.TrimPrefix(protocol, "_"))
+
+	// RFC 6335 service names are at most 15 characters, need a letter and can't contain "--"
+	if !srvServicePattern.MatchString(service) || strings.Contains(service, "--") || !strings.ContainsAny(service, "abcdefghijklmnopqrstuvwxyz") {
+		return "", fmt.Errorf("%q is not a valid service name", service)
+	}
+	if !srvProtocolPattern.MatchString(protocol) {
+		return "", fmt.Errorf("%q is not a valid protocol", protocol)
+	}
+
+	owner := "_" + service + "._" + protocol
+	name = strings.Trim(name, ".")
+	if name != "" {
+		owner += "." + name
+	}
+	return owner, nil
+}
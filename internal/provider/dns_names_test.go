@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReverseName(t *testing.T) {
+	r := require.New(t)
+
+	for address, expected := range map[string]string{
+		"192.0.2.10":         "10.2.0.192.in-addr.arpa",
+		"::ffff:192.0.2.1":   "1.2.0.192.in-addr.arpa",
+		"2001:db8::567:89ab": "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	} {
+		name, err := reverseName(address)
+		r.NoError(err)
+		r.Equal(expected, name, address)
+	}
+
+	_, err := reverseName("foobar.dev")
+	r.Error(err)
+}
+
+func Test_TxtChunks(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal([]string{`""`}, txtChunks(""))
+	r.Equal([]string{`"v=DKIM1; k=rsa; p=\"x\\y\""`}, txtChunks(`v=DKIM1; k=rsa; p="x\y"`))
+
+	long := strings.Repeat("a", 300)
+	chunks := txtChunks(long)
+	r.Equal([]string{`"` + strings.Repeat("a", 255) + `"`, `"` + strings.Repeat("a", 45) + `"`}, chunks)
+
+	// ü is two bytes, the second chunk must not start with half of it
+	multibyte := strings.Repeat("a", 254) + "ü"
+	chunks = txtChunks(multibyte)
+	r.Equal([]string{`"` + strings.Repeat("a", 254) + `"`, `"ü"`}, chunks)
+
+	for _, value := range []string{long, multibyte, `with "quotes" and \ slashes`} {
+		joined, err := joinTXT(strings.Join(txtChunks(value), " "))
+		r.NoError(err)
+		r.Equal(value, joined)
+	}
+}
+
+func Test_JoinTxt(t *testing.T) {
+	r := require.New(t)
+
+	joined, err := joinTXT(`"v=spf1 include:_spf.example.com" " ~all"`)
+	r.NoError(err)
+	r.Equal("v=spf1 include:_spf.example.com ~all", joined)
+
+	joined, err = joinTXT(`bare`)
+	r.NoError(err)
+	r.Equal("bare", joined)
+
+	_, err = joinTXT(`"unterminated`)
+	r.Error(err)
+}
+
+func Test_TxtEquivalent(t *testing.T) {
+	r := require.New(t)
+
+	r.True(txtEquivalent(`"v=spf1 include:_spf.example.com" " ~all"`, "v=spf1 include:_spf.example.com ~all"))
+	r.True(txtEquivalent(`"abc" "def"`, `"abcdef"`))
+	r.True(txtEquivalent(" token ", `"token"`))
+	r.False(txtEquivalent(`"abc " "def"`, "abcdef"))
+	r.False(txtEquivalent("v=spf1  -all", "v=spf1 -all"))
+	r.False(txtEquivalent(`"unterminated`, "unterminated"))
+}
+
+func Test_SrvName(t *testing.T) {
+	tests := []struct {
+		service  string
+		protocol string
+		name     string
+		expected string
+	}{
+		{service: "sip", protocol: "tcp", name: "", expected: "_sip._tcp"},
+		{service: "_xmpp-client", protocol: "_TCP", name: "chat", expected: "_xmpp-client._tcp.chat"},
+		{service: "minecraft", protocol: "udp", name: "play.eu.", expected: "_minecraft._udp.play.eu"},
+	}
+	for _, test := range tests {
+		name, err := srvName(test.service, test.protocol, test.name)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, name)
+	}
+
+	for _, service := range []string{"", "-sip", "sip-", "x--y", "1234", "averyveryverylongservice", "s_p"} {
+		_, err := srvName(service, "tcp", "")
+		require.Error(t, err, service)
+	}
+
+	_, err := srvName("sip", "t.cp", "")
+	require.Error(t, err)
+}
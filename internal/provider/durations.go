@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDuration reads a duration attribute given as a number of seconds like "600" or as a Go duration
+// like "10m" or "1h30m"
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither a number of seconds nor a duration like 1h", value)
+	}
+	return duration, nil
+}
+
+// parsePositiveDuration is parseDuration for timeouts and intervals, which have to be longer than zero
+func parsePositiveDuration(value string) (time.Duration, error) {
+	duration, err := parseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("%q isn't longer than zero", value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"600":   10 * time.Minute,
+		" 30 ":  30 * time.Second,
+		"0":     0,
+		"10m":   10 * time.Minute,
+		"1h30m": 90 * time.Minute,
+		"1.5s":  1500 * time.Millisecond,
+	}
+	for value, expected := range tests {
+		duration, err := parseDuration(value)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, duration, value)
+	}
+
+	for _, value := range []string{"", "soon", "1d", "10 minutes"} {
+		_, err := parseDuration(value)
+		require.ErrorContains(t, err, "neither a number of seconds nor a duration", value)
+	}
+}
+
+func Test_ParsePositiveDuration(t *testing.T) {
+	r := require.New(t)
+
+	duration, err := parsePositiveDuration("300")
+	r.NoError(err)
+	r.Equal(5*time.Minute, duration)
+
+	_, err = parsePositiveDuration("0s")
+	r.ErrorContains(err, "isn't longer than zero")
+	_, err = parsePositiveDuration("-10")
+	r.ErrorContains(err, "isn't longer than zero")
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ ephemeral.EphemeralResource = &porkbunSslBundleEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &porkbunSslBundleEphemeralResource{}
+
+func NewSslBundleEphemeralResource() ephemeral.EphemeralResource {
+	return &porkbunSslBundleEphemeralResource{}
+}
+
+type porkbunSslBundleEphemeralResource struct {
+	provider *porkbunProvider
+}
+
+type porkbunSslBundleEphemeralResourceData struct {
+	Domain           types.String `tfsdk:"domain"`
+	CertificateChain types.String `tfsdk:"certificate_chain"`
+	PrivateKey       types.String `tfsdk:"private_key"`
+	PublicKey        types.String `tfsdk:"public_key"`
+
+	Certificate          types.String `tfsdk:"certificate"`
+	IntermediateChain    types.String `tfsdk:"intermediate_chain"`
+	CombinedPem          types.String `tfsdk:"combined_pem"`
+	CertificateDer       types.String `tfsdk:"certificate_der"`
+	IntermediateChainDer types.List   `tfsdk:"intermediate_chain_der"`
+	PrivateKeyDer        types.String `tfsdk:"private_key_der"`
+}
+
+func (r *porkbunSslBundleEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssl_bundle"
+}
+
+func (r *porkbunSslBundleEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Retrieves the free SSL certificate bundle Porkbun issues for a domain. Requires Terraform 1.10 or later, " +
+			"the values are never written to plan or state so the private key can be passed to write-only arguments of other providers.",
+
+		Attributes: map[string]schema.Attribute{
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain to retrieve the bundle for",
+			},
+			"certificate_chain": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The PEM encoded certificate chain",
+			},
+			"private_key": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The PEM encoded private key",
+			},
+			"public_key": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The PEM encoded public key",
+			},
+			"certificate": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The PEM encoded certificate of the domain, the first one of `certificate_chain`",
+			},
+			"intermediate_chain": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The PEM encoded intermediate certificates following the certificate of the domain in `certificate_chain`, like the `certificate_chain` argument of `aws_acm_certificate` expects",
+			},
+			"combined_pem": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The certificate chain followed by the private key in a single PEM file, as HAProxy and some load balancers take them",
+			},
+			"certificate_der": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The certificate of the domain DER encoded, in base64",
+			},
+			"intermediate_chain_der": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The intermediate certificates DER encoded, in base64",
+			},
+			"private_key_der": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				MarkdownDescription: "The private key DER encoded, in base64",
+			},
+		},
+	}
+}
+
+func (r *porkbunSslBundleEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunSslBundleEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunSslBundleEphemeralResourceData
+	rt := r.provider.runtime()
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "domain", data.Domain.ValueString())
+	bundle, err := retry(ctx, rt.retries, func(ctx context.Context) (porkbunapi.SSLBundle, error) {
+		return rt.client.RetrieveSSLBundle(ctx, data.Domain.ValueString())
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve the SSL bundle for %s", data.Domain.ValueString()),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	data.CertificateChain = types.StringValue(bundle.CertificateChain)
+	data.PrivateKey = types.StringValue(bundle.PrivateKey)
+	data.PublicKey = types.StringValue(bundle.PublicKey)
+
+	certificates, key, err := splitSSLBundle(bundle)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not read the SSL bundle for %s", data.Domain.ValueString()),
+			fmt.Sprintf("Error: %s", err),
+		)
+		return
+	}
+
+	var intermediates strings.Builder
+	intermediatesDer := make([]string, 0, len(certificates)-1)
+	for _, certificate := range certificates[1:] {
+		intermediates.Write(pem.EncodeToMemory(certificate))
+		intermediatesDer = append(intermediatesDer, base64.StdEncoding.EncodeToString(certificate.Bytes))
+	}
+	data.Certificate = types.StringValue(string(pem.EncodeToMemory(certificates[0])))
+	data.IntermediateChain = types.StringValue(intermediates.String())
+	data.CombinedPem = types.StringValue(data.Certificate.ValueString() + intermediates.String() + string(pem.EncodeToMemory(key)))
+	data.CertificateDer = types.StringValue(base64.StdEncoding.EncodeToString(certificates[0].Bytes))
+	data.PrivateKeyDer = types.StringValue(base64.StdEncoding.EncodeToString(key.Bytes))
+	data.IntermediateChainDer, diags = types.ListValueFrom(ctx, types.StringType, intermediatesDer)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.Result.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// splitSSLBundle decodes the certificates of the chain, the one of the domain first, and the private key
+func splitSSLBundle(bundle porkbunapi.SSLBundle) ([]*pem.Block, *pem.Block, error) {
+	certificates, err := pemBlocks(bundle.CertificateChain, "CERTIFICATE")
+	if err != nil {
+		return nil, nil, fmt.Errorf("certificate chain: %w", err)
+	}
+	if len(certificates) == 0 {
+		return nil, nil, fmt.Errorf("the certificate chain holds no certificate")
+	}
+
+	keys, err := pemBlocks(bundle.PrivateKey, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("private key: %w", err)
+	}
+	if len(keys) != 1 {
+		return nil, nil, fmt.Errorf("expected a single private key, got %d PEM blocks", len(keys))
+	}
+	return certificates, keys[0], nil
+}
+
+// pemBlocks decodes every PEM block of pemData, which all have to be of blockType unless it is empty.
+// Anything but whitespace around the blocks is an error, so a truncated bundle isn't silently cut short.
+func pemBlocks(pemData string, blockType string) ([]*pem.Block, error) {
+	var blocks []*pem.Block
+	rest := []byte(pemData)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if blockType != "" && block.Type != blockType {
+			return nil, fmt.Errorf("expected %s PEM blocks, got %s", blockType, block.Type)
+		}
+		blocks = append(blocks, block)
+	}
+	if strings.TrimSpace(string(rest)) != "" {
+		return nil, fmt.Errorf("the PEM data has %d bytes that aren't a PEM block", len(strings.TrimSpace(string(rest))))
+	}
+	return blocks, nil
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// externalDNSEndpoint is a record as external-dns describes it, in its DNSEndpoint resources and the
+// endpoints it logs. A single endpoint holds every target of a name and type.
+type externalDNSEndpoint struct {
+	DNSName    string   `json:"dnsName"`
+	Targets    []string `json:"targets"`
+	RecordType string   `json:"recordType"`
+	RecordTTL  int64    `json:"recordTTL"`
+}
+
+type externalDNSEndpointSpec struct {
+	Spec struct {
+		Endpoints []externalDNSEndpoint `json:"endpoints"`
+	} `json:"spec"`
+}
+
+// externalDNSEndpoints reads a JSON list of endpoints, a DNSEndpoint resource or a list of DNSEndpoint
+// resources like `kubectl get dnsendpoints -o json` prints them
+func externalDNSEndpoints(data string) ([]externalDNSEndpoint, error) {
+	trimmed := bytes.TrimSpace([]byte(data))
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var endpoints []externalDNSEndpoint
+		if err := json.Unmarshal(trimmed, &endpoints); err != nil {
+			return nil, fmt.Errorf("parsing endpoints: %w", err)
+		}
+		return endpoints, nil
+	}
+
+	var resource struct {
+		externalDNSEndpointSpec
+		Items []externalDNSEndpointSpec `json:"items"`
+	}
+	if err := json.Unmarshal(trimmed, &resource); err != nil {
+		return nil, fmt.Errorf("parsing endpoints: %w", err)
+	}
+	if resource.Items == nil && resource.Spec.Endpoints == nil {
+		return nil, fmt.Errorf("expected a list of endpoints, a DNSEndpoint or a list of DNSEndpoints")
+	}
+
+	endpoints := resource.Spec.Endpoints
+	for _, item := range resource.Items {
+		endpoints = append(endpoints, item.Spec.Endpoints...)
+	}
+	return endpoints, nil
+}
+
+// externalDNSRecords converts the endpoints in data into records of domain, one per target. Endpoints of
+// other domains are skipped, as are the TXT records external-dns keeps to track which records it owns.
+func externalDNSRecords(data string, domain string) ([]zoneRecord, error) {
+	endpoints, err := externalDNSEndpoints(data)
+	if err != nil {
+		return nil, err
+	}
+	domain = strings.ToLower(normalizeDnsValue(domain))
+
+	records := []zoneRecord{}
+	for _, endpoint := range endpoints {
+		name, err := relativeZoneName(strings.ToLower(normalizeDnsValue(endpoint.DNSName)), domain)
+		if err != nil {
+			continue
+		}
+		recordType := strings.ToUpper(endpoint.RecordType)
+		if recordType == "" {
+			return nil, fmt.Errorf("endpoint %s has no recordType", endpoint.DNSName)
+		}
+		ttl := ""
+		if endpoint.RecordTTL > 0 {
+			ttl = strconv.FormatInt(endpoint.RecordTTL, 10)
+		}
+
+		for _, target := range endpoint.Targets {
+			record := zoneRecord{Name: name, Type: recordType, TTL: ttl, Content: target}
+			switch recordType {
+			case "TXT":
+				if isExternalDNSOwnership(target) {
+					continue
+				}
+			case "MX", "SRV":
+				// The priority comes first, Porkbun keeps it separately
+				prio, content, ok := strings.Cut(strings.TrimSpace(target), " ")
+				if !ok {
+					return nil, fmt.Errorf("%s target %q of %s has no priority", recordType, target, endpoint.DNSName)
+				}
+				record.Prio = prio
+				record.Content = normalizeDnsValue(strings.TrimSpace(content))
+			default:
+				record.Content = normalizeDnsValue(target)
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// isExternalDNSOwnership reports whether a TXT value is one of the registry records external-dns marks
+// the records it manages with
+func isExternalDNSOwnership(value string) bool {
+	return strings.HasPrefix(strings.Trim(value, `"`), "heritage=external-dns")
+}
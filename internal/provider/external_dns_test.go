@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExternalDNSRecords(t *testing.T) {
+	r := require.New(t)
+
+	endpoints := `[
+  {"dnsName": "www.foobar.dev", "targets": ["192.0.2.1", "192.0.2.2"], "recordType": "A", "recordTTL": 3600},
+  {"dnsName": "foobar.dev.", "targets": ["10 mx1.foobar.dev."], "recordType": "MX"},
+  {"dnsName": "_sip._tcp.foobar.dev", "targets": ["10 60 5060 sip.foobar.dev"], "recordType": "SRV"},
+  {"dnsName": "api.foobar.dev", "targets": ["lb.example.com"], "recordType": "cname", "labels": {"owner": "default"}},
+  {"dnsName": "www.foobar.dev", "targets": ["\"heritage=external-dns,external-dns/owner=default\""], "recordType": "TXT"},
+  {"dnsName": "www.other.dev", "targets": ["192.0.2.3"], "recordType": "A"}
+]`
+	records, err := externalDNSRecords(endpoints, "foobar.dev")
+	r.NoError(err)
+	r.Equal([]zoneRecord{
+		{Name: "www", Type: "A", TTL: "3600", Content: "192.0.2.1"},
+		{Name: "www", Type: "A", TTL: "3600", Content: "192.0.2.2"},
+		{Name: "", Type: "MX", Content: "mx1.foobar.dev", Prio: "10"},
+		{Name: "_sip._tcp", Type: "SRV", Content: "60 5060 sip.foobar.dev", Prio: "10"},
+		{Name: "api", Type: "CNAME", Content: "lb.example.com"},
+	}, records)
+
+	_, err = externalDNSRecords(`[{"dnsName": "foobar.dev", "targets": ["mx1.foobar.dev"], "recordType": "MX"}]`, "foobar.dev")
+	r.ErrorContains(err, "has no priority")
+
+	_, err = externalDNSRecords(`[{"dnsName": "foobar.dev", "targets": ["192.0.2.1"]}]`, "foobar.dev")
+	r.ErrorContains(err, "has no recordType")
+}
+
+func Test_ExternalDNSEndpoints(t *testing.T) {
+	r := require.New(t)
+
+	dnsEndpoint := `{
+  "apiVersion": "externaldns.k8s.io/v1alpha1",
+  "kind": "DNSEndpoint",
+  "spec": {"endpoints": [{"dnsName": "www.foobar.dev", "targets": ["192.0.2.1"], "recordType": "A"}]}
+}`
+	endpoints, err := externalDNSEndpoints(dnsEndpoint)
+	r.NoError(err)
+	r.Equal([]externalDNSEndpoint{{DNSName: "www.foobar.dev", Targets: []string{"192.0.2.1"}, RecordType: "A"}}, endpoints)
+
+	list := `{"apiVersion": "v1", "kind": "List", "items": [` + dnsEndpoint + `, ` + dnsEndpoint + `]}`
+	endpoints, err = externalDNSEndpoints(list)
+	r.NoError(err)
+	r.Len(endpoints, 2)
+
+	_, err = externalDNSEndpoints(`{"kind": "Service"}`)
+	r.ErrorContains(err, "expected a list of endpoints")
+
+	_, err = externalDNSEndpoints(`not json`)
+	r.ErrorContains(err, "parsing endpoints")
+}
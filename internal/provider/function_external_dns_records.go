@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ function.Function = &externalDNSRecordsFunction{}
+
+func NewExternalDNSRecordsFunction() function.Function {
+	return &externalDNSRecordsFunction{}
+}
+
+type externalDNSRecordsFunction struct{}
+
+func (f *externalDNSRecordsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "external_dns_records"
+}
+
+func (f *externalDNSRecordsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Converts external-dns endpoints into a list of records",
+		MarkdownDescription: "Converts the endpoints external-dns manages into a list of objects with `name`, `type`, `ttl`, `content` and `prio` " +
+			"attributes matching the arguments of `porkbun_dns_record`, to move records from Kubernetes to Terraform. " +
+			"Every target of an endpoint becomes a record, MX and SRV priorities are split into `prio` and the TXT records external-dns " +
+			"tracks its ownership with are skipped. `ttl` and `prio` are null when not given.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name: "endpoints",
+				MarkdownDescription: "JSON with a list of endpoints, a `DNSEndpoint` resource or a list of them like " +
+					"`kubectl get dnsendpoints -o json` prints",
+			},
+			function.StringParameter{
+				Name:                "domain",
+				MarkdownDescription: "The domain to return records of, endpoints of other domains are skipped",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.ObjectType{
+				AttrTypes: zoneRecordAttrTypes,
+			},
+		},
+	}
+}
+
+func (f *externalDNSRecordsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var endpoints, domain string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &endpoints, &domain))
+	if resp.Error != nil {
+		return
+	}
+
+	records, err := externalDNSRecords(endpoints, domain)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result := make([]zoneRecordData, len(records))
+	for i, record := range records {
+		result[i] = zoneRecordData{
+			Name:    types.StringValue(record.Name),
+			Type:    types.StringValue(record.Type),
+			Ttl:     optionalString(record.TTL),
+			Content: types.StringValue(record.Content),
+			Prio:    optionalString(record.Prio),
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
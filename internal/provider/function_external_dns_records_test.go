@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func Test_ExternalDNSRecordsFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories("http://localhost"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          locals {
+            records = provider::porkbun::external_dns_records(jsonencode([
+              { dnsName = "www.foobar.dev", targets = ["0.0.0.1"], recordType = "A", recordTTL = 3600 },
+              { dnsName = "foobar.dev", targets = ["10 mx1.foobar.dev"], recordType = "MX" },
+            ]), "foobar.dev")
+          }
+          output "count" {
+            value = length(local.records)
+          }
+          output "www_ttl" {
+            value = local.records[0].ttl
+          }
+          output "mx_prio" {
+            value = local.records[1].prio
+          }
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("count", "2"),
+					resource.TestCheckOutput("www_ttl", "3600"),
+					resource.TestCheckOutput("mx_prio", "10"),
+				),
+			},
+			{
+				Config: `
+          output "invalid" {
+            value = provider::porkbun::external_dns_records("{}", "foobar.dev")
+          }
+				`,
+				ExpectError: regexp.MustCompile(`expected a list of endpoints`),
+			},
+		},
+	})
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"golang.org/x/net/idna"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ function.Function = &idnaFunction{}
+var _ function.Function = &idnaUnicodeFunction{}
+
+// Record names regularly carry underscore labels (_dmarc, _acme-challenge) so the
+// lookup profile is used without strict hostname checks.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.Transitional(false),
+	idna.StrictDomainName(false),
+	idna.BidiRule(),
+)
+
+func NewIdnaFunction() function.Function {
+	return &idnaFunction{}
+}
+
+type idnaFunction struct{}
+
+func (f *idnaFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "idna"
+}
+
+func (f *idnaFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Converts a domain name to its ASCII (punycode) form",
+		MarkdownDescription: "Converts a Unicode domain name such as `bücher.example` to the ASCII form Porkbun stores, `xn--bcher-kva.example`. Names that are already ASCII are lowercased and returned unchanged otherwise.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The domain or record name to convert",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *idnaFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	ascii, err := toASCIIName(name)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, ascii))
+}
+
+func NewIdnaUnicodeFunction() function.Function {
+	return &idnaUnicodeFunction{}
+}
+
+type idnaUnicodeFunction struct{}
+
+func (f *idnaUnicodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "idna_unicode"
+}
+
+func (f *idnaUnicodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Converts a punycode domain name back to Unicode",
+		MarkdownDescription: "Converts an ASCII domain name such as `xn--bcher-kva.example` to its Unicode form, `bücher.example`. This is the inverse of `idna`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The domain or record name to convert",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *idnaUnicodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	unicode, err := toUnicodeName(name)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, unicode))
+}
+
+func toASCIIName(name string) (string, error) {
+	// A trailing dot is valid in DNS but trips up the IDNA validation, keep it as given
+	trimmed := strings.TrimSuffix(name, ".")
+	ascii, err := idnaProfile.ToASCII(trimmed)
+	if err != nil {
+		return "", err
+	}
+	return ascii + name[len(trimmed):], nil
+}
+
+func toUnicodeName(name string) (string, error) {
+	trimmed := strings.TrimSuffix(name, ".")
+	unicode, err := idnaProfile.ToUnicode(strings.ToLower(trimmed))
+	if err != nil {
+		return "", err
+	}
+	return unicode + name[len(trimmed):], nil
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IdnaFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories("http://localhost"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          output "ascii" {
+            value = provider::porkbun::idna("Bücher.example")
+          }
+          output "unicode" {
+            value = provider::porkbun::idna_unicode("xn--bcher-kva.example")
+          }
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("ascii", "xn--bcher-kva.example"),
+					resource.TestCheckOutput("unicode", "bücher.example"),
+				),
+			},
+		},
+	})
+}
+
+func Test_IdnaNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		unicode string
+		ascii   string
+	}{
+		{name: "plain", unicode: "example.com", ascii: "example.com"},
+		{name: "idn", unicode: "bücher.example", ascii: "xn--bcher-kva.example"},
+		{name: "underscoreLabel", unicode: "_dmarc.bücher.example", ascii: "_dmarc.xn--bcher-kva.example"},
+		{name: "trailingDot", unicode: "bücher.example.", ascii: "xn--bcher-kva.example."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+
+			ascii, err := toASCIIName(test.unicode)
+			r.NoError(err)
+			r.Equal(test.ascii, ascii)
+
+			unicode, err := toUnicodeName(test.ascii)
+			r.NoError(err)
+			r.Equal(test.unicode, unicode)
+		})
+	}
+}
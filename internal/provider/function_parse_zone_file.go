@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ function.Function = &parseZoneFileFunction{}
+
+var zoneRecordAttrTypes = map[string]attr.Type{
+	"name":    types.StringType,
+	"type":    types.StringType,
+	"ttl":     types.StringType,
+	"content": types.StringType,
+	"prio":    types.StringType,
+}
+
+type zoneRecordData struct {
+	Name    types.String `tfsdk:"name"`
+	Type    types.String `tfsdk:"type"`
+	Ttl     types.String `tfsdk:"ttl"`
+	Content types.String `tfsdk:"content"`
+	Prio    types.String `tfsdk:"prio"`
+}
+
+func NewParseZoneFileFunction() function.Function {
+	return &parseZoneFileFunction{}
+}
+
+type parseZoneFileFunction struct{}
+
+func (f *parseZoneFileFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_zone_file"
+}
+
+func (f *parseZoneFileFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Parses a BIND zone file into a list of records",
+		MarkdownDescription: "Parses a BIND zone file into a list of objects with `name`, `type`, `ttl`, `content` and `prio` attributes " +
+			"matching the arguments of `porkbun_dns_record`. Names are relative to the origin with an empty string for the apex, " +
+			"MX and SRV priorities are split into `prio` and SOA records are skipped. `ttl` and `prio` are null when not given.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "zone",
+				MarkdownDescription: "The contents of the zone file",
+			},
+			function.StringParameter{
+				Name:                "origin",
+				MarkdownDescription: "The domain the zone file describes, used until a `$ORIGIN` directive overrides it",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.ObjectType{
+				AttrTypes: zoneRecordAttrTypes,
+			},
+		},
+	}
+}
+
+func (f *parseZoneFileFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var zone, origin string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &zone, &origin))
+	if resp.Error != nil {
+		return
+	}
+
+	records, err := parseZoneFile(zone, origin)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result := make([]zoneRecordData, len(records))
+	for i, record := range records {
+		result[i] = zoneRecordData{
+			Name:    types.StringValue(record.Name),
+			Type:    types.StringValue(record.Type),
+			Ttl:     optionalString(record.TTL),
+			Content: types.StringValue(record.Content),
+			Prio:    optionalString(record.Prio),
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
+
+// optionalString maps an empty string to null so it can be passed straight to optional arguments
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
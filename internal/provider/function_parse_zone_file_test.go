@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func Test_ParseZoneFileFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories("http://localhost"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          locals {
+            records = provider::porkbun::parse_zone_file(<<-EOT
+              $TTL 600
+              @    A  0.0.0.1
+              mail MX 10 mx1.foobar.dev.
+            EOT
+            , "foobar.dev")
+          }
+          output "count" {
+            value = length(local.records)
+          }
+          output "mx_prio" {
+            value = local.records[1].prio
+          }
+          output "mx_content" {
+            value = local.records[1].content
+          }
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("count", "2"),
+					resource.TestCheckOutput("mx_prio", "10"),
+					resource.TestCheckOutput("mx_content", "mx1.foobar.dev"),
+				),
+			},
+			{
+				Config: `
+          output "invalid" {
+            value = provider::porkbun::parse_zone_file("test A 0.0.0.1", "")
+          }
+				`,
+				ExpectError: regexp.MustCompile(`without an origin`),
+			},
+		},
+	})
+}
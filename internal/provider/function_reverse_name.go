@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ function.Function = &reverseNameFunction{}
+
+func NewReverseNameFunction() function.Function {
+	return &reverseNameFunction{}
+}
+
+type reverseNameFunction struct{}
+
+func (f *reverseNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "reverse_name"
+}
+
+func (f *reverseNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Returns the reverse DNS name of an IP address",
+		MarkdownDescription: "Returns the `in-addr.arpa` name of an IPv4 address or the `ip6.arpa` name of an IPv6 address, e.g. `192.0.2.10` becomes `10.2.0.192.in-addr.arpa`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "address",
+				MarkdownDescription: "The IPv4 or IPv6 address",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *reverseNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var address string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &address))
+	if resp.Error != nil {
+		return
+	}
+
+	name, err := reverseName(address)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, name))
+}
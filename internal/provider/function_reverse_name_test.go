@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func Test_ReverseNameFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories("http://localhost"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          output "ipv4" {
+            value = provider::porkbun::reverse_name("192.0.2.10")
+          }
+          output "ipv6" {
+            value = provider::porkbun::reverse_name("2001:db8::567:89ab")
+          }
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("ipv4", "10.2.0.192.in-addr.arpa"),
+					resource.TestCheckOutput("ipv6", "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"),
+				),
+			},
+			{
+				Config: `
+          output "invalid" {
+            value = provider::porkbun::reverse_name("foobar.dev")
+          }
+				`,
+				ExpectError: regexp.MustCompile(`is not an IP address`),
+			},
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ function.Function = &spfFunction{}
+
+func NewSpfFunction() function.Function {
+	return &spfFunction{}
+}
+
+type spfFunction struct{}
+
+func (f *spfFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "spf"
+}
+
+func (f *spfFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds an SPF record",
+		MarkdownDescription: "Builds the content of an SPF TXT record, e.g. `v=spf1 ip4:192.0.2.1 include:_spf.google.com ~all`. " +
+			"Addresses and include domains are validated and mechanisms are emitted in the order ip4, ip6, include, all.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "includes",
+				ElementType:         types.StringType,
+				MarkdownDescription: "Domains to add as `include:` mechanisms",
+			},
+			function.ListParameter{
+				Name:                "ip4",
+				ElementType:         types.StringType,
+				MarkdownDescription: "IPv4 addresses or CIDR networks to add as `ip4:` mechanisms",
+			},
+			function.ListParameter{
+				Name:                "ip6",
+				ElementType:         types.StringType,
+				MarkdownDescription: "IPv6 addresses or CIDR networks to add as `ip6:` mechanisms",
+			},
+			function.StringParameter{
+				Name:                "all",
+				MarkdownDescription: "The trailing `all` mechanism, one of `+all`, `-all`, `~all`, `?all` or an empty string to leave it out",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *spfFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var includes, ip4, ip6 []string
+	var all string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &includes, &ip4, &ip6, &all))
+	if resp.Error != nil {
+		return
+	}
+
+	spf, err := buildSPF(includes, ip4, ip6, all)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, spf))
+}
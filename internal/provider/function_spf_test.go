@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func Test_SpfFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories("http://localhost"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          output "spf" {
+            value = provider::porkbun::spf(["_spf.google.com"], ["192.0.2.1", "198.51.100.0/24"], ["2001:db8::/32"], "~all")
+          }
+          output "empty" {
+            value = provider::porkbun::spf([], [], [], "")
+          }
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("spf", "v=spf1 ip4:192.0.2.1 ip4:198.51.100.0/24 ip6:2001:db8::/32 include:_spf.google.com ~all"),
+					resource.TestCheckOutput("empty", "v=spf1"),
+				),
+			},
+			{
+				Config: `
+          output "invalid" {
+            value = provider::porkbun::spf([], ["2001:db8::1"], [], "-all")
+          }
+				`,
+				ExpectError: regexp.MustCompile(`is not an IPv4 address or network`),
+			},
+		},
+	})
+}
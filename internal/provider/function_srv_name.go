@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ function.Function = &srvNameFunction{}
+
+func NewSrvNameFunction() function.Function {
+	return &srvNameFunction{}
+}
+
+type srvNameFunction struct{}
+
+func (f *srvNameFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "srv_name"
+}
+
+func (f *srvNameFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Builds the owner name of an SRV record",
+		MarkdownDescription: "Builds the `_service._proto.name` owner name of an SRV record for use as the `name` of a `porkbun_dns_record`, " +
+			"e.g. `srv_name(\"sip\", \"tcp\", \"voip\")` returns `_sip._tcp.voip`. The leading underscores are optional and service names are validated against RFC 6335.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "service",
+				MarkdownDescription: "The service name, e.g. `sip` or `_xmpp-client`",
+			},
+			function.StringParameter{
+				Name:                "protocol",
+				MarkdownDescription: "The protocol, e.g. `tcp` or `udp`",
+			},
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The record name below the domain, an empty string for the apex",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *srvNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var service, protocol, name string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &service, &protocol, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	owner, err := srvName(service, protocol, name)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, owner))
+}
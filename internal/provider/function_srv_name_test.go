@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func Test_SrvNameFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories("http://localhost"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          output "apex" {
+            value = provider::porkbun::srv_name("sip", "tcp", "")
+          }
+          output "name" {
+            value = provider::porkbun::srv_name("_xmpp-client", "_TCP", "chat")
+          }
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("apex", "_sip._tcp"),
+					resource.TestCheckOutput("name", "_xmpp-client._tcp.chat"),
+				),
+			},
+			{
+				Config: `
+          output "invalid" {
+            value = provider::porkbun::srv_name("-sip", "tcp", "")
+          }
+				`,
+				ExpectError: regexp.MustCompile(`is not a valid service name`),
+			},
+		},
+	})
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ function.Function = &txtChunksFunction{}
+var _ function.Function = &txtJoinFunction{}
+
+func NewTxtChunksFunction() function.Function {
+	return &txtChunksFunction{}
+}
+
+type txtChunksFunction struct{}
+
+func (f *txtChunksFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "txt_chunks"
+}
+
+func (f *txtChunksFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Splits a long value into quoted TXT character-strings",
+		MarkdownDescription: "Splits a value such as a DKIM public key into quoted character-strings of at most 255 bytes each, " +
+			"escaping quotes and backslashes. Use `join(\" \", ...)` on the result to build record content.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "The unquoted TXT value",
+			},
+		},
+		Return: function.ListReturn{
+			ElementType: types.StringType,
+		},
+	}
+}
+
+func (f *txtChunksFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &value))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, txtChunks(value)))
+}
+
+func NewTxtJoinFunction() function.Function {
+	return &txtJoinFunction{}
+}
+
+type txtJoinFunction struct{}
+
+func (f *txtJoinFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "txt_join"
+}
+
+func (f *txtJoinFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Joins quoted TXT character-strings back into a single value",
+		MarkdownDescription: "Concatenates the quoted character-strings of TXT content such as `\"v=DKIM1; p=MIIB\" \"IjAN\"` into the unquoted value. This is the inverse of `txt_chunks`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "content",
+				MarkdownDescription: "The TXT content made of quoted character-strings",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *txtJoinFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var content string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &content))
+	if resp.Error != nil {
+		return
+	}
+
+	joined, err := joinTXT(content)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, joined))
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func Test_TxtChunksFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories("http://localhost"),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          locals {
+            key = join("", [for i in range(300) : "a"])
+          }
+          output "quoted" {
+            value = join(" ", provider::porkbun::txt_chunks("v=DKIM1; p=\"x\""))
+          }
+          output "count" {
+            value = length(provider::porkbun::txt_chunks(local.key))
+          }
+          output "round_trip" {
+            value = provider::porkbun::txt_join(join(" ", provider::porkbun::txt_chunks(local.key))) == local.key
+          }
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("quoted", `"v=DKIM1; p=\"x\""`),
+					resource.TestCheckOutput("count", "2"),
+					resource.TestCheckOutput("round_trip", "true"),
+				),
+			},
+			{
+				Config: `
+          output "invalid" {
+            value = provider::porkbun::txt_join("\"unterminated")
+          }
+				`,
+				ExpectError: regexp.MustCompile(`unterminated quoted string`),
+			},
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The import IDs porkbun_dns_record accepts, listed in the errors of parseRecordImportID
+const recordImportFormats = "Expected one of:\n" +
+	"  - example.com/123456789, the domain and Porkbun ID of the record\n" +
+	"  - example.com:TXT:_dmarc, the domain, type and name of the only record of that type at the name, with @ or nothing after the last colon for the apex\n" +
+	"  - 123456789, the Porkbun ID of the record alone, the domain is searched for in the account"
+
+// recordImportID is an import ID of porkbun_dns_record. Either ID is set, with Domain when it was given, or
+// Domain, Type and Name are to find the record by.
+type recordImportID struct {
+	Domain string
+	ID     string
+	Type   string
+	Name   string
+}
+
+// parseRecordImportID reads an import ID in one of recordImportFormats
+func parseRecordImportID(importID string) (recordImportID, error) {
+	importID = strings.TrimSpace(importID)
+
+	if domain, id, ok := strings.Cut(importID, "/"); ok {
+		if err := validateImportDomain(domain); err != nil {
+			return recordImportID{}, importIDError(importID, err)
+		}
+		if !isRecordID(id) {
+			return recordImportID{}, importIDError(importID, fmt.Errorf("record ID %q isn't a number", id))
+		}
+		return recordImportID{Domain: strings.ToLower(domain), ID: id}, nil
+	}
+
+	if strings.Contains(importID, ":") {
+		parts := strings.Split(importID, ":")
+		if len(parts) != 3 {
+			return recordImportID{}, importIDError(importID, fmt.Errorf("expected a domain, type and name separated by colons, got %d parts", len(parts)))
+		}
+		domain, recordType, name := parts[0], parts[1], parts[2]
+		if err := validateImportDomain(domain); err != nil {
+			return recordImportID{}, importIDError(importID, err)
+		}
+		if recordType == "" {
+			return recordImportID{}, importIDError(importID, fmt.Errorf("the record type is empty"))
+		}
+		domain = strings.ToLower(domain)
+		name = strings.ToLower(normalizeDnsValue(name))
+		if name == "@" {
+			name = ""
+		}
+		// Names given fully qualified are made relative like Porkbun's
+		if name == domain || strings.HasSuffix(name, "."+domain) {
+			name = relativeRecordName(name, domain)
+		}
+		return recordImportID{Domain: domain, Type: strings.ToUpper(recordType), Name: name}, nil
+	}
+
+	if !isRecordID(importID) {
+		return recordImportID{}, importIDError(importID, fmt.Errorf("not a record ID"))
+	}
+	return recordImportID{ID: importID}, nil
+}
+
+func importIDError(importID string, err error) error {
+	return fmt.Errorf("invalid import ID %q: %w\n\n%s", importID, err, recordImportFormats)
+}
+
+func validateImportDomain(domain string) error {
+	if domain == "" {
+		return fmt.Errorf("the domain is empty")
+	}
+	if !strings.Contains(domain, ".") || strings.ContainsAny(domain, " /:") {
+		return fmt.Errorf("%q isn't a domain like example.com", domain)
+	}
+	return nil
+}
+
+// isRecordID reports whether id looks like the numeric IDs Porkbun gives records
+func isRecordID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseRecordImportID(t *testing.T) {
+	tests := []struct {
+		importID string
+		expected recordImportID
+		err      string
+	}{
+		{"Foobar.dev/123456789", recordImportID{Domain: "foobar.dev", ID: "123456789"}, ""},
+		{" 123456789 ", recordImportID{ID: "123456789"}, ""},
+		{"foobar.dev:txt:_dmarc", recordImportID{Domain: "foobar.dev", Type: "TXT", Name: "_dmarc"}, ""},
+		{"foobar.dev:MX:", recordImportID{Domain: "foobar.dev", Type: "MX", Name: ""}, ""},
+		{"foobar.dev:MX:@", recordImportID{Domain: "foobar.dev", Type: "MX", Name: ""}, ""},
+		{"foobar.dev:A:WWW.foobar.dev.", recordImportID{Domain: "foobar.dev", Type: "A", Name: "www"}, ""},
+		{"foobar.dev:A:foobar.dev", recordImportID{Domain: "foobar.dev", Type: "A", Name: ""}, ""},
+		{"", recordImportID{}, "not a record ID"},
+		{"www", recordImportID{}, "not a record ID"},
+		{"foobar.dev/www", recordImportID{}, `record ID "www" isn't a number`},
+		{"/123456789", recordImportID{}, "the domain is empty"},
+		{"foobar/123456789", recordImportID{}, `"foobar" isn't a domain`},
+		{"foobar.dev:A", recordImportID{}, "got 2 parts"},
+		{"foobar.dev::www", recordImportID{}, "the record type is empty"},
+	}
+	for _, test := range tests {
+		t.Run(test.importID, func(t *testing.T) {
+			r := require.New(t)
+
+			parsed, err := parseRecordImportID(test.importID)
+			if test.err != "" {
+				r.ErrorContains(err, test.err)
+				// Every error explains what would have been accepted
+				r.ErrorContains(err, "example.com:TXT:_dmarc")
+				return
+			}
+			r.NoError(err)
+			r.Equal(test.expected, parsed)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ list.ListResource = &porkbunDnsRecordListResource{}
+var _ list.ListResourceWithConfigure = &porkbunDnsRecordListResource{}
+
+func NewDnsRecordListResource() list.ListResource {
+	return &porkbunDnsRecordListResource{}
+}
+
+// porkbunDnsRecordListResource lets `terraform query` enumerate the records of a domain
+// and generate import blocks and configuration for porkbun_dns_record.
+type porkbunDnsRecordListResource struct {
+	provider *porkbunProvider
+}
+
+type porkbunDnsRecordListConfigData struct {
+	Domain types.String `tfsdk:"domain"`
+	Type   types.String `tfsdk:"type"`
+}
+
+func (r *porkbunDnsRecordListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_record"
+}
+
+func (r *porkbunDnsRecordListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		MarkdownDescription: "Lists the DNS records of a domain",
+		Attributes: map[string]listschema.Attribute{
+			"domain": listschema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain to list records for",
+			},
+			"type": listschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list records of this type",
+			},
+		},
+	}
+}
+
+func (r *porkbunDnsRecordListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunDnsRecordListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config porkbunDnsRecordListConfigData
+	rt := r.provider.runtime()
+
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	domain := config.Domain.ValueString()
+	ctx = tflog.SetField(ctx, "domain", domain)
+	records, err := rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	})
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf(`Could not retrieve records for %s.`, domain),
+			apiErrorDetail(err),
+		)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		var pushed int64
+		for _, record := range sortedRecords(records) {
+			if !config.Type.IsNull() && config.Type.ValueString() != record.Type {
+				continue
+			}
+			// Terraform stops reading once it has req.Limit results, a limit of 0 means all of them
+			if req.Limit > 0 && pushed == req.Limit {
+				return
+			}
+
+			data := porkbunDnsRecordResourceData{
+				Id:               types.StringValue(record.ID),
+				Name:             types.StringValue(relativeRecordName(record.Name, domain)),
+				Type:             types.StringValue(record.Type),
+				Content:          types.StringValue(record.Content),
+				Ttl:              optionalString(record.TTL),
+				Notes:            optionalString(record.Notes),
+				Prio:             types.StringNull(),
+				Domain:           types.StringValue(domain),
+				ContentFields:    types.MapNull(types.StringType),
+				ContentWo:        types.StringNull(),
+				ContentWoVersion: types.Int64Null(),
+				ContentFromFile:  types.StringNull(),
+				ContentBase64:    types.StringNull(),
+				SemanticCompare:  types.BoolNull(),
+				KeepOnDestroy:    types.BoolNull(),
+				Disabled:         types.BoolNull(),
+
+				AllowCriticalChanges: types.BoolNull(),
+
+				WaitForPropagation:   types.BoolNull(),
+				PropagationTimeout:   types.StringNull(),
+				PropagationInterval:  types.StringNull(),
+				PropagationResolvers: types.ListNull(types.StringType),
+
+				WaitForDelegation: types.BoolNull(),
+			}
+
+			if record.Type == "MX" || record.Type == "SRV" {
+				data.Prio = optionalString(record.Prio)
+			}
+			data.ContentFields = contentFieldsValue(data)
+
+			result := req.NewListResult(ctx)
+			result.DisplayName = fmt.Sprintf("%s %s %s", record.Name, record.Type, record.Content)
+			result.Diagnostics.Append(result.Identity.Set(ctx, porkbunDnsRecordIdentityData{
+				Domain: data.Domain,
+				Id:     data.Id,
+			})...)
+			if req.IncludeResource {
+				result.Diagnostics.Append(result.Resource.Set(ctx, data)...)
+			}
+
+			pushed++
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
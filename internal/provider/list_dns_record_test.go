@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListRecords(t *testing.T) {
+	r := require.New(t)
+
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev",
+		porkbuntest.Record{Name: "", Type: "MX", Content: "mail.foobar.dev", Prio: "10"},
+		porkbuntest.Record{Name: "www", Type: "A", Content: "192.0.2.1", TTL: "3600"},
+		porkbuntest.Record{Name: "api", Type: "A", Content: "192.0.2.2"},
+		porkbuntest.Record{Name: "docs", Type: "CNAME", Content: "foobar.dev"},
+	))
+	l := newUnitListResource(t, server.URL)
+
+	all := listRecords(t, l, "foobar.dev", "", true, 0)
+	r.Len(all, 4)
+	r.Equal("foobar.dev MX mail.foobar.dev", all[0].DisplayName)
+
+	var identity porkbunDnsRecordIdentityData
+	r.False(all[1].Identity.Get(context.Background(), &identity).HasError())
+	r.Equal("foobar.dev", identity.Domain.ValueString())
+	r.Equal(server.Records("foobar.dev")[1].ID, identity.Id.ValueString())
+
+	var data porkbunDnsRecordResourceData
+	r.False(all[0].Resource.Get(context.Background(), &data).HasError())
+	r.Equal("", data.Name.ValueString())
+	r.Equal("10", data.Prio.ValueString())
+	r.False(all[1].Resource.Get(context.Background(), &data).HasError())
+	r.Equal("www", data.Name.ValueString())
+	r.Equal("3600", data.Ttl.ValueString())
+	r.True(data.Prio.IsNull())
+
+	// Only the A records, in the order they were created
+	filtered := listRecords(t, l, "foobar.dev", "A", true, 0)
+	r.Len(filtered, 2)
+	r.Equal("www.foobar.dev A 192.0.2.1", filtered[0].DisplayName)
+	r.Equal("api.foobar.dev A 192.0.2.2", filtered[1].DisplayName)
+
+	// The limit of a query counts the results after filtering, without resources only identities are returned
+	page := listRecords(t, l, "foobar.dev", "", false, 3)
+	r.Len(page, 3)
+	r.Equal(all[2].DisplayName, page[2].DisplayName)
+	r.True(page[0].Resource.Raw.IsNull())
+
+	limited := listRecords(t, l, "foobar.dev", "A", true, 1)
+	r.Len(limited, 1)
+	r.Equal(filtered[0].DisplayName, limited[0].DisplayName)
+
+	// Every query is answered from a single listing of the zone
+	r.Equal(1, server.Calls("dns/retrieve"))
+}
+
+func Test_ListRecordsUnknownDomain(t *testing.T) {
+	r := require.New(t)
+
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+	l := newUnitListResource(t, server.URL)
+
+	results := listRecords(t, l, "other.dev", "", true, 0)
+	r.Len(results, 1)
+	r.True(results[0].Diagnostics.HasError())
+	r.Equal("Could not retrieve records for other.dev.", results[0].Diagnostics[0].Summary())
+}
+
+// newUnitListResource returns a porkbun_dns_record list resource talking to the test server at baseUrl
+func newUnitListResource(t *testing.T, baseUrl string) *porkbunDnsRecordListResource {
+	client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	var err error
+	client.BaseURL, err = url.Parse(baseUrl)
+	require.NoError(t, err)
+
+	return &porkbunDnsRecordListResource{provider: newUnitRecordResource(client).provider}
+}
+
+// listRecords runs a query of the records of domain, optionally only those of recordType, and collects what it streams
+func listRecords(t *testing.T, l *porkbunDnsRecordListResource, domain string, recordType string, includeResource bool, limit int64) []list.ListResult {
+	ctx := context.Background()
+
+	var configSchema list.ListResourceSchemaResponse
+	l.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &configSchema)
+	require.False(t, configSchema.Diagnostics.HasError())
+
+	var resourceSchema fwresource.SchemaResponse
+	var identitySchema fwresource.IdentitySchemaResponse
+	r := &porkbunDnsRecordResource{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, &resourceSchema)
+	r.IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, &identitySchema)
+
+	typeValue := tftypes.NewValue(tftypes.String, nil)
+	if recordType != "" {
+		typeValue = tftypes.NewValue(tftypes.String, recordType)
+	}
+	config := tfsdk.Config{
+		Schema: configSchema.Schema,
+		Raw: tftypes.NewValue(configSchema.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"domain": tftypes.NewValue(tftypes.String, domain),
+			"type":   typeValue,
+		}),
+	}
+
+	stream := &list.ListResultsStream{}
+	l.List(ctx, list.ListRequest{
+		Config:                 config,
+		IncludeResource:        includeResource,
+		Limit:                  limit,
+		ResourceSchema:         resourceSchema.Schema,
+		ResourceIdentitySchema: identitySchema.IdentitySchema,
+	}, stream)
+
+	var results []list.ListResult
+	for result := range stream.Results {
+		results = append(results, result)
+	}
+	return results
+}
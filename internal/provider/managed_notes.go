@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// How many entries notes_audit_trail keeps in the notes of a record, older ones are dropped to keep them short
+const maxNotesAuditEntries = 5
+
+// An entry appendNotesAudit adds, with the whitespace separating it from what comes before
+var notesAuditEntryPattern = regexp.MustCompile(`\s*\[tf \d{4}-\d\d-\d\dT[^\]]*\]`)
+
+// stampNotes adds marker to the notes of a record the provider writes while managed_notes_marker is set,
+// so records owned by other tools like external-dns can be told apart. Notes already carrying it are left alone.
+func stampNotes(notes string, marker string) string {
+	if marker == "" || hasNotesMarker(notes, marker) {
+		return notes
+	}
+	if notes == "" {
+		return marker
+	}
+	return notes + " " + marker
+}
+
+// unstampNotes removes the marker stampNotes added and the entries of appendNotesAudit, leaving the notes
+// that were configured
+func unstampNotes(notes string, marker string) string {
+	notes = withoutNotesAudit(notes)
+	if marker == "" {
+		return notes
+	}
+	if notes == marker {
+		return ""
+	}
+	return strings.TrimSuffix(notes, " "+marker)
+}
+
+func hasNotesMarker(notes string, marker string) bool {
+	return strings.Contains(notes, marker)
+}
+
+// refreshNotes updates the configured notes from the API like refreshString, without the marker or audit
+// entries showing up as drift
+func refreshNotes(current types.String, live string, marker string) types.String {
+	live = withoutNotesAudit(live)
+	if current.IsNull() || live == stampNotes(current.ValueString(), marker) {
+		return current
+	}
+	return types.StringValue(unstampNotes(live, marker))
+}
+
+// notesAuditTrail is where the writes notes_audit_trail records come from, Terraform doesn't tell providers
+// so it is taken from the environment of the run
+type notesAuditTrail struct {
+	workspace string
+	runID     string
+}
+
+// entry describes a write at now, like [tf 2026-10-16T09:30:00Z ws:production run:run-abc123]
+func (a *notesAuditTrail) entry(now time.Time) string {
+	entry := "[tf " + now.UTC().Format(time.RFC3339)
+	if a.workspace != "" {
+		entry += " ws:" + strings.Join(strings.Fields(a.workspace), "_")
+	}
+	if a.runID != "" {
+		entry += " run:" + strings.Join(strings.Fields(a.runID), "_")
+	}
+	return entry + "]"
+}
+
+// appendNotesAudit appends entry to notes, after the audit entries previous held. previous are the notes
+// of the record before the write, so the trail is carried over; only the last maxNotesAuditEntries are kept.
+func appendNotesAudit(notes string, previous string, entry string) string {
+	var entries []string
+	for _, existing := range notesAuditEntryPattern.FindAllString(previous, -1) {
+		entries = append(entries, strings.TrimSpace(existing))
+	}
+	entries = append(entries, entry)
+	if len(entries) > maxNotesAuditEntries {
+		entries = entries[len(entries)-maxNotesAuditEntries:]
+	}
+
+	trail := strings.Join(entries, " ")
+	if notes == "" {
+		return trail
+	}
+	return fmt.Sprintf("%s %s", notes, trail)
+}
+
+func withoutNotesAudit(notes string) string {
+	return notesAuditEntryPattern.ReplaceAllString(notes, "")
+}
+
+// recordNotes are the notes written to a record configured with notes, given its notes before the write
+func (rt *providerRuntime) recordNotes(notes string, previous string) string {
+	notes = stampNotes(notes, rt.managedNotesMarker)
+	if rt.notesAudit == nil {
+		return notes
+	}
+	return appendNotesAudit(notes, previous, rt.notesAudit.entry(time.Now()))
+}
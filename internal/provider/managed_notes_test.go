@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StampNotes(t *testing.T) {
+	r := require.New(t)
+	marker := "managed-by:terraform"
+
+	r.Equal("managed-by:terraform", stampNotes("", marker))
+	r.Equal("web managed-by:terraform", stampNotes("web", marker))
+	r.Equal("managed-by:terraform web", stampNotes("managed-by:terraform web", marker))
+	r.Equal("web", stampNotes("web", ""))
+
+	r.Equal("", unstampNotes("managed-by:terraform", marker))
+	r.Equal("web", unstampNotes("web managed-by:terraform", marker))
+	r.Equal("web", unstampNotes("web", marker))
+	r.Equal("web managed-by:terraform", unstampNotes("web managed-by:terraform", ""))
+}
+
+func Test_RefreshNotes(t *testing.T) {
+	r := require.New(t)
+	marker := "managed-by:terraform"
+
+	r.Equal(types.StringValue("web"), refreshNotes(types.StringValue("web"), "web managed-by:terraform", marker))
+	r.Equal(types.StringValue("managed-by:terraform web"), refreshNotes(types.StringValue("managed-by:terraform web"), "managed-by:terraform web", marker))
+	r.Equal(types.StringValue("api"), refreshNotes(types.StringValue("web"), "api managed-by:terraform", marker))
+	r.Equal(types.StringValue("api"), refreshNotes(types.StringValue("web"), "api", marker))
+	r.True(refreshNotes(types.StringNull(), "managed-by:terraform", marker).IsNull())
+	r.Equal(types.StringValue("web managed-by:terraform"), refreshNotes(types.StringValue("web"), "web managed-by:terraform", ""))
+}
+
+func Test_NotesAuditTrail(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2026, 10, 16, 11, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	audit := &notesAuditTrail{workspace: "production", runID: "run-abc123"}
+	r.Equal("[tf 2026-10-16T09:30:00Z ws:production run:run-abc123]", audit.entry(now))
+	r.Equal("[tf 2026-10-16T09:30:00Z ws:my_workspace]", (&notesAuditTrail{workspace: "my workspace"}).entry(now))
+	r.Equal("[tf 2026-10-16T09:30:00Z]", (&notesAuditTrail{}).entry(now))
+
+	first := appendNotesAudit("web managed-by:terraform", "", "[tf 2026-10-16T09:30:00Z]")
+	r.Equal("web managed-by:terraform [tf 2026-10-16T09:30:00Z]", first)
+	second := appendNotesAudit("web", first, "[tf 2026-10-17T09:30:00Z ws:prod]")
+	r.Equal("web [tf 2026-10-16T09:30:00Z] [tf 2026-10-17T09:30:00Z ws:prod]", second)
+	r.Equal("[tf 2026-10-17T09:30:00Z ws:prod]", appendNotesAudit("", "", "[tf 2026-10-17T09:30:00Z ws:prod]"))
+
+	// Only the last entries are kept
+	notes := ""
+	for day := 1; day <= maxNotesAuditEntries+2; day++ {
+		notes = appendNotesAudit("web", notes, fmt.Sprintf("[tf 2026-10-%02dT09:30:00Z]", day))
+	}
+	r.Equal("web [tf 2026-10-03T09:30:00Z] [tf 2026-10-04T09:30:00Z] [tf 2026-10-05T09:30:00Z] [tf 2026-10-06T09:30:00Z] [tf 2026-10-07T09:30:00Z]", notes)
+
+	// Entries are neither configured notes nor drift
+	r.Equal("web", unstampNotes("web managed-by:terraform [tf 2026-10-16T09:30:00Z]", "managed-by:terraform"))
+	r.Equal("", unstampNotes("[tf 2026-10-16T09:30:00Z ws:prod]", ""))
+	r.Equal("web [tf]", unstampNotes("web [tf]", ""))
+	r.Equal(types.StringValue("web"), refreshNotes(types.StringValue("web"), second, ""))
+	r.Equal(types.StringValue("api"), refreshNotes(types.StringValue("web"), "api "+audit.entry(now), ""))
+}
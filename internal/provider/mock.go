@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+)
+
+// Terraform restarts the provider between plan and apply, so the mock keeps its domains next to the
+// other files of the working directory
+var defaultMockStateFile = filepath.Join(".terraform", "porkbun-mock.json")
+
+// mockHost is where the client points in mock mode, requests never leave the process
+const mockHost = "porkbun.invalid"
+
+// mockTransport answers requests from the in-memory fake of the API
+type mockTransport struct {
+	server *porkbuntest.Server
+}
+
+func (t *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.server.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// useMock serves the client from a fake of the API that accepts any domain and keeps its state in
+// stateFile. Logging, tracing and the response size limit still apply.
+func useMock(c *porkbunapi.Client, stateFile string) error {
+	logged, err := loggingTransportOf(c.HTTPClient)
+	if err != nil {
+		return err
+	}
+
+	server := porkbuntest.NewUnstartedServer(porkbuntest.WithAnyDomain())
+	if err := server.Persist(stateFile); err != nil {
+		return err
+	}
+	logged.next = &mockTransport{server: server}
+
+	// The fake serves its endpoints at the root for every version
+	c.BaseURL = &url.URL{Scheme: "https", Host: mockHost, Path: "/"}
+	return nil
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MockProvider(t *testing.T) {
+	t.Setenv("PORKBUN_API_KEY", "")
+	t.Setenv("PORKBUN_SECRET_KEY", "")
+	t.Setenv("PORKBUN_MOCK_STATE_FILE", filepath.Join(t.TempDir(), "mock.json"))
+
+	resource.UnitTest(t, resource.TestCase{
+		// Every step configures a new provider, like separate Terraform runs do
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"porkbun": func() (tfprotov6.ProviderServer, error) {
+				return providerserver.NewProtocol6WithError(New("test")())()
+			},
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          provider "porkbun" {
+            mock = true
+          }
+
+          resource "porkbun_dns_record" "test" {
+            name    = "www"
+            domain  = "example.com"
+            content = "0.0.0.1"
+            type    = "A"
+          }
+        `,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("porkbun_dns_record.test", "id"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "ttl", "600"),
+				),
+			},
+			{
+				Config: `
+          provider "porkbun" {
+            mock = true
+          }
+
+          resource "porkbun_dns_record" "test" {
+            name    = "www"
+            domain  = "example.com"
+            content = "0.0.0.2"
+            type    = "A"
+          }
+        `,
+				Check: resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", "0.0.0.2"),
+			},
+		},
+	})
+}
+
+func Test_UseMock(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	stateFile := filepath.Join(t.TempDir(), "mock.json")
+
+	newClient := func(apiKey string, secretKey string) *porkbunapi.Client {
+		c := porkbunapi.New(apiKey, secretKey)
+		c.HTTPClient = newHTTPClient(defaultMaxResponseBytes)
+		r.NoError(useMock(c, stateFile))
+		r.Equal(mockHost, c.BaseURL.Host)
+		return c
+	}
+
+	// The fake still checks keys, Configure swaps the configured ones for the fake's
+	_, err := newClient("pk1_other", "sk1_other").Ping(ctx)
+	r.Error(err)
+
+	id, err := newClient(porkbuntest.APIKey, porkbuntest.SecretKey).CreateRecord(ctx, "example.com", porkbunapi.Record{Name: "www", Type: "A", Content: "0.0.0.1"})
+	r.NoError(err)
+
+	// A client set up later, like the one of the next Terraform run, sees the record
+	record, found, err := newClient(porkbuntest.APIKey, porkbuntest.SecretKey).RetrieveRecord(ctx, "example.com", id)
+	r.NoError(err)
+	r.True(found)
+	r.Equal("0.0.0.1", record.Content)
+}
@@ -6,22 +6,34 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/nrdcg/porkbun"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ provider.Provider = &porkbunProvider{}
+var _ provider.ProviderWithFunctions = &porkbunProvider{}
+var _ provider.ProviderWithEphemeralResources = &porkbunProvider{}
+var _ provider.ProviderWithListResources = &porkbunProvider{}
+var _ provider.ProviderWithValidateConfig = &porkbunProvider{}
 
 type porkbunProvider struct {
-	client     *porkbun.Client
-	configured bool
-	version    string
-	MaxRetries int
+	runtimeHolder
+
+	version string
 }
 
 // providerData can be used to store data from the Terraform configuration.
@@ -30,6 +42,55 @@ type providerData struct {
 	SecretKey  types.String `tfsdk:"secret_key"`
 	BaseUrl    types.String `tfsdk:"base_url"`
 	MaxRetries types.Int64  `tfsdk:"max_retries"`
+	RetryWait  types.String `tfsdk:"retry_wait"`
+
+	SkipCredentialsValidation types.Bool   `tfsdk:"skip_credentials_validation"`
+	MaxResponseBytes          types.Int64  `tfsdk:"max_response_bytes"`
+	CheckLiveDns              types.Bool   `tfsdk:"check_live_dns"`
+	ProtectCriticalRecords    types.Bool   `tfsdk:"protect_critical_records"`
+	ManagedNotesMarker        types.String `tfsdk:"managed_notes_marker"`
+	NotesAuditTrail           types.Bool   `tfsdk:"notes_audit_trail"`
+	SuggestImports            types.Bool   `tfsdk:"suggest_imports"`
+	StrictDecode              types.Bool   `tfsdk:"strict_decode"`
+	DefaultTtls               types.Map    `tfsdk:"default_ttls"`
+
+	ApiVersion types.String `tfsdk:"api_version"`
+	ApiHost    types.String `tfsdk:"api_host"`
+
+	Mock types.Bool `tfsdk:"mock"`
+}
+
+func (p *porkbunProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "porkbun"
+	resp.Version = p.version
+}
+
+// ValidateConfig checks what can be checked without the environment, so mistakes fail at plan time
+// instead of when the provider is configured
+func (p *porkbunProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data providerData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.RetryWait.IsNull() && !data.RetryWait.IsUnknown() {
+		if _, err := parseRetryWait(data.RetryWait.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("retry_wait"), "Invalid retry wait", err.Error())
+		}
+	}
+}
+
+// parseRetryWait reads retry_wait, which may be zero to retry right away
+func parseRetryWait(value string) (time.Duration, error) {
+	wait, err := parseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if wait < 0 {
+		return 0, fmt.Errorf("%q is negative", value)
+	}
+	return wait, nil
 }
 
 func (p *porkbunProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -41,122 +102,463 @@ func (p *porkbunProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
+	mock := boolSetting(data.Mock, "PORKBUN_MOCK", "mock", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keys that come from resources not applied yet leave nothing to call the API with. Terraform can
+	// defer everything using the provider to a later round instead of failing the plan.
+	if !mock && req.ClientCapabilities.DeferralAllowed && (data.ApiKey.IsUnknown() || data.SecretKey.IsUnknown()) {
+		resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+		return
+	}
+
+	var apiKey, secretKey string
+	if mock {
+		// The fake only accepts its own keys, so nobody needs real ones to use it
+		apiKey, secretKey = porkbuntest.APIKey, porkbuntest.SecretKey
+	} else {
+		var ok bool
+		apiKey, secretKey, ok = credentials(data, &resp.Diagnostics)
+		if !ok {
+			return
+		}
+	}
+
+	c := porkbunapi.New(apiKey, secretKey)
+	c.UserAgent = "terraform-provider-porkbun/" + p.version
+
+	maxResponseBytes := int64Setting(data.MaxResponseBytes, "PORKBUN_MAX_RESPONSE_BYTES", defaultMaxResponseBytes, "max response bytes", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if maxResponseBytes <= 0 {
+		resp.Diagnostics.AddError(
+			"Invalid max_response_bytes",
+			"max_response_bytes must be greater than zero",
+		)
+		return
+	}
+
+	c.HTTPClient = newHTTPClient(maxResponseBytes)
+
+	strictDecode := boolSetting(data.StrictDecode, "PORKBUN_STRICT_DECODE", "strict decode", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if strictDecode {
+		c.UnknownFields = func(ctx context.Context, endpoint string, fields []string) {
+			tflog.Warn(ctx, "Porkbun API response has fields the provider doesn't know", map[string]any{"endpoint": endpoint, "fields": fields})
+		}
+	}
+
+	// Record/replay of API traffic is only meant for tests, so it is driven by the environment alone
+	if mode, ok := os.LookupEnv("PORKBUN_FIXTURE_MODE"); ok && mode != "" {
+		if err := useFixture(c.HTTPClient, mode, os.Getenv("PORKBUN_FIXTURE")); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to set up API fixture",
+				fmt.Sprintf("Error: %s", err),
+			)
+			return
+		}
+	}
+
+	// Plan and apply run in separate provider processes, sharing read-only responses between them is opt-in
+	// with a handle naming the run
+	if handle, ok := os.LookupEnv("PORKBUN_RESPONSE_CACHE"); ok && handle != "" {
+		if err := useResponseCache(c.HTTPClient, handle); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to set up response cache",
+				fmt.Sprintf("Error: %s", err),
+			)
+			return
+		}
+	}
+
+	apiVersion := porkbunapi.V3.Name
+	if data.ApiVersion.IsNull() {
+		if v, ok := os.LookupEnv("PORKBUN_API_VERSION"); ok && v != "" {
+			apiVersion = v
+		}
+	} else {
+		apiVersion = data.ApiVersion.ValueString()
+	}
+
+	version, err := porkbunapi.LookupVersion(apiVersion)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid api_version",
+			err.Error(),
+		)
+		return
+	}
+
+	apiHost := porkbunapi.DefaultHost
+	if data.ApiHost.IsNull() {
+		if h, ok := os.LookupEnv("PORKBUN_API_HOST"); ok && h != "" {
+			apiHost = h
+		}
+	} else {
+		apiHost = data.ApiHost.ValueString()
+	}
+
+	c.SetEndpoint(apiHost, version)
+
+	// A full base URL wins over the host, the version still decides where endpoints live under it
+	if baseUrl, ok := os.LookupEnv("PORKBUN_BASE_URL"); ok {
+		c.BaseURL, _ = url.Parse(baseUrl)
+	}
+
+	if mock {
+		stateFile := defaultMockStateFile
+		if f, ok := os.LookupEnv("PORKBUN_MOCK_STATE_FILE"); ok && f != "" {
+			stateFile = f
+		}
+		if err := useMock(c, stateFile); err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to set up mock API",
+				fmt.Sprintf("Error: %s", err),
+			)
+			return
+		}
+	}
+
+	maxRetries := int64Setting(data.MaxRetries, "PORKBUN_MAX_RETRIES", defaultRetryAttempts, "max retries", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	retryWait := defaultRetryWait
+	wait := os.Getenv("PORKBUN_RETRY_WAIT")
+	if !data.RetryWait.IsNull() {
+		wait = data.RetryWait.ValueString()
+	}
+	if wait != "" {
+		waitd, err := parseRetryWait(wait)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"failed converting retry wait",
+				err.Error(),
+			)
+			return
+		}
+		retryWait = waitd
+	}
+
+	skipCredentialsValidation := boolSetting(data.SkipCredentialsValidation, "PORKBUN_SKIP_CREDENTIALS_VALIDATION", "skip credentials validation", &resp.Diagnostics)
+	checkLiveDNS := boolSetting(data.CheckLiveDns, "PORKBUN_CHECK_LIVE_DNS", "check live dns", &resp.Diagnostics)
+	protectCriticalRecords := boolSetting(data.ProtectCriticalRecords, "PORKBUN_PROTECT_CRITICAL_RECORDS", "protect critical records", &resp.Diagnostics)
+	suggestImports := boolSetting(data.SuggestImports, "PORKBUN_SUGGEST_IMPORTS", "suggest imports", &resp.Diagnostics)
+	notesAuditTrailEnabled := boolSetting(data.NotesAuditTrail, "PORKBUN_NOTES_AUDIT_TRAIL", "notes audit trail", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managedNotesMarker := os.Getenv("PORKBUN_MANAGED_NOTES_MARKER")
+	if !data.ManagedNotesMarker.IsNull() {
+		managedNotesMarker = data.ManagedNotesMarker.ValueString()
+	}
+
+	var notesAudit *notesAuditTrail
+	if notesAuditTrailEnabled {
+		// HCP Terraform names the workspace and run of remote runs, local runs only have TF_WORKSPACE if it is set
+		notesAudit = &notesAuditTrail{workspace: os.Getenv("TFC_WORKSPACE_NAME"), runID: os.Getenv("TFC_RUN_ID")}
+		if notesAudit.workspace == "" {
+			notesAudit.workspace = os.Getenv("TF_WORKSPACE")
+		}
+	}
+
+	var defaultTTLs map[string]string
+	if !data.DefaultTtls.IsNull() && !data.DefaultTtls.IsUnknown() {
+		var configured map[string]string
+		resp.Diagnostics.Append(data.DefaultTtls.ElementsAs(ctx, &configured, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		var err error
+		if defaultTTLs, err = parseDefaultTTLs(configured); err != nil {
+			resp.Diagnostics.AddError(
+				"failed converting default ttls",
+				err.Error(),
+			)
+			return
+		}
+	}
+
+	retries := retryPolicy{attempts: int(maxRetries), wait: retryWait}
+
+	if !skipCredentialsValidation {
+		// Ping is the cheapest authenticated call, so use it to fail fast on bad keys. It is tried once with a
+		// short timeout instead of the retries of other calls, every plan goes through here and shouldn't stall
+		// when the API can't be reached.
+		pingCtx, cancel := context.WithTimeout(ctx, credentialsValidationTimeout)
+		_, err := c.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to validate Porkbun credentials",
+				apiErrorDetail(err)+"\n\nSet skip_credentials_validation to configure the provider without calling the API.",
+			)
+			return
+		}
+	}
+
+	p.setRuntime(&providerRuntime{
+		client:       c,
+		retries:      retries,
+		checkLiveDNS: checkLiveDNS,
+		records:      newRecordCache(),
+		usage:        apiUsageOf(c.HTTPClient),
+
+		protectCriticalRecords: protectCriticalRecords,
+		managedNotesMarker:     managedNotesMarker,
+		notesAudit:             notesAudit,
+		suggestImports:         suggestImports,
+		defaultTTLs:            defaultTTLs,
+	})
+
+	resp.ResourceData = p
+	resp.DataSourceData = p
+	resp.EphemeralResourceData = p
+	resp.ListResourceData = p
+}
+
+// boolSetting returns the value of a boolean argument, falling back to the environment variable env when the
+// configuration leaves it out. what names the setting in the error added to diags when env isn't a boolean.
+func boolSetting(value types.Bool, env string, what string, diags *diag.Diagnostics) bool {
+	if !value.IsNull() {
+		return value.ValueBool()
+	}
+	raw, ok := os.LookupEnv(env)
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf("failed converting %s", what),
+			err.Error(),
+		)
+	}
+	return b
+}
+
+// int64Setting is boolSetting for numbers, returning def when neither the configuration nor env set one
+func int64Setting(value types.Int64, env string, def int64, what string, diags *diag.Diagnostics) int64 {
+	if !value.IsNull() {
+		return value.ValueInt64()
+	}
+	raw, ok := os.LookupEnv(env)
+	if !ok {
+		return def
+	}
+	i, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf("failed converting %s", what),
+			err.Error(),
+		)
+	}
+	return i
+}
+
+// credentials reads the API keys from the configuration or the environment. It returns false when the
+// provider can't be configured, with the reason added to diags.
+func credentials(data providerData, diags *diag.Diagnostics) (string, string, bool) {
 	var apiKey string
-	if data.ApiKey.Unknown {
+	if data.ApiKey.IsUnknown() {
 		// Cannot connect to client with an unknown value
-		resp.Diagnostics.AddWarning(
+		diags.AddWarning(
 			"Unable to create client",
 			"Cannot use unknown value as api_key",
 		)
-		return
+		return "", "", false
 	}
 
-	if data.ApiKey.Null {
+	if data.ApiKey.IsNull() {
 		apiKey = os.Getenv("PORKBUN_API_KEY")
 	} else {
-		apiKey = data.ApiKey.Value
+		apiKey = data.ApiKey.ValueString()
 	}
 
 	if apiKey == "" {
 		// Error vs warning - empty value must stop execution
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Unable to find api_key",
 			"api_key cannot be an empty string",
 		)
-		return
+		return "", "", false
 	}
 
 	var secretKey string
-	if data.SecretKey.Unknown {
+	if data.SecretKey.IsUnknown() {
 		// Cannot connect to client with an unknown value
-		resp.Diagnostics.AddWarning(
+		diags.AddWarning(
 			"Unable to create client",
 			"Cannot use unknown value as secret_key",
 		)
-		return
+		return "", "", false
 	}
 
-	if data.SecretKey.Null {
+	if data.SecretKey.IsNull() {
 		secretKey = os.Getenv("PORKBUN_SECRET_KEY")
 	} else {
-		secretKey = data.SecretKey.Value
+		secretKey = data.SecretKey.ValueString()
 	}
 
 	if secretKey == "" {
 		// Error vs warning - empty value must stop execution
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Unable to find secret_key",
 			"secret_key cannot be an empty string",
 		)
-		return
+		return "", "", false
 	}
 
-	c := porkbun.New(secretKey, apiKey)
+	return apiKey, secretKey, true
+}
 
-	if baseUrl, ok := os.LookupEnv("PORKBUN_BASE_URL"); ok {
-		c.BaseURL, _ = url.Parse(baseUrl)
+func (p *porkbunProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewDnsRecordResource,
+		NewFlattenedCnameResource,
+		NewBulkNameserverUpdateResource,
+		NewUrlForwardResource,
+		NewNameserversResource,
+		NewStandardRecordsResource,
+		NewDnssecRecordResource,
+		NewGlueRecordResource,
 	}
+}
 
-	if data.MaxRetries.Null {
-		if mr, ok := os.LookupEnv("PORKBUN_MAX_RETRIES"); ok {
-			mri, err := strconv.Atoi(mr)
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"failed converting max retries",
-					err.Error(),
-				)
-			}
-			p.MaxRetries = mri
-		} else {
-			p.MaxRetries = 10
-		}
-	} else {
-		p.MaxRetries = int(data.MaxRetries.Value)
+func (p *porkbunProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		NewAllRecordsDataSource,
+		NewApiStatusDataSource,
+		NewApiUsageDataSource,
+		NewDnsPropagationDataSource,
+		NewDomainDataSource,
+		NewDomainsByNameserverDataSource,
+		NewPricingDataSource,
+		NewUnmanagedRecordsDataSource,
+		NewZoneImportPlanDataSource,
 	}
+}
 
-	p.client = c
-	p.configured = true
+func (p *porkbunProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewSslBundleEphemeralResource,
+	}
 }
 
-func (p *porkbunProvider) GetResources(ctx context.Context) (map[string]provider.ResourceType, diag.Diagnostics) {
-	return map[string]provider.ResourceType{
-		"porkbun_dns_record": porkbunDnsRecordResourceType{},
-	}, nil
+func (p *porkbunProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewDnsRecordListResource,
+	}
 }
 
-func (p *porkbunProvider) GetDataSources(ctx context.Context) (map[string]provider.DataSourceType, diag.Diagnostics) {
-	return map[string]provider.DataSourceType{}, nil
+func (p *porkbunProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewExternalDNSRecordsFunction,
+		NewIdnaFunction,
+		NewIdnaUnicodeFunction,
+		NewParseZoneFileFunction,
+		NewSpfFunction,
+		NewReverseNameFunction,
+		NewTxtChunksFunction,
+		NewTxtJoinFunction,
+		NewSrvNameFunction,
+	}
 }
 
-func (p *porkbunProvider) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
-	return tfsdk.Schema{
-		Attributes: map[string]tfsdk.Attribute{
-			"api_key": {
+func (p *porkbunProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"api_key": schema.StringAttribute{
 				MarkdownDescription: "API Key for Porkbun",
-				Required:            false,
 				Optional:            true,
-				Type:                types.StringType,
 			},
-			"secret_key": {
+			"secret_key": schema.StringAttribute{
 				MarkdownDescription: "Secret Key for Porkbun",
-				Required:            false,
 				Optional:            true,
-				Type:                types.StringType,
 			},
-			"base_url": {
+			"base_url": schema.StringAttribute{
 				MarkdownDescription: "Override Porkbun Base URL",
-				Required:            false,
 				Optional:            true,
-				Type:                types.StringType,
 			},
-			"max_retries": {
+			"max_retries": schema.Int64Attribute{
 				MarkdownDescription: "Should only be changed if needing to work around Porkbun API rate limits",
-				Required:            false,
 				Optional:            true,
-				Type:                types.Int64Type,
+			},
+			"retry_wait": schema.StringAttribute{
+				MarkdownDescription: "How long to wait before retrying a failed API call in seconds or as a duration like `5s`, doubling with every further retry. " +
+					"Defaults to `" + defaultRetryWait.String() + "`, can also be set with PORKBUN_RETRY_WAIT",
+				Optional: true,
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				MarkdownDescription: "Skip the API call that validates credentials while configuring the provider, useful for plan-only runs without network access",
+				Optional:            true,
+			},
+			"max_response_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Maximum size in bytes of a decompressed API response, defaults to 10MiB",
+				Optional:            true,
+			},
+			"check_live_dns": schema.BoolAttribute{
+				MarkdownDescription: "Look records about to be created up in public DNS while planning and warn when the domain isn't delegated to Porkbun or the name already resolves to something else. " +
+					"SPF policies in TXT records are also resolved to warn when their includes take more than the 10 DNS lookups receivers allow",
+				Optional: true,
+			},
+			"protect_critical_records": schema.BoolAttribute{
+				MarkdownDescription: "Refuse to change or delete MX, NS and apex A, AAAA and ALIAS records unless their resource sets `allow_critical_changes`, " +
+					"protecting mail and website availability from accidental refactors",
+				Optional: true,
+			},
+			"managed_notes_marker": schema.StringAttribute{
+				MarkdownDescription: "Text added to the notes of every record the provider creates or updates, like `managed-by:terraform`. " +
+					"Updates and deletes of records whose notes don't carry it fail, so records owned by other tools like external-dns or cert-manager aren't clobbered",
+				Optional: true,
+			},
+			"notes_audit_trail": schema.BoolAttribute{
+				MarkdownDescription: fmt.Sprintf("Append an entry like `[tf 2026-10-16T09:30:00Z ws:production run:run-abc123]` to the notes of every record "+
+					"`porkbun_dns_record` creates or updates, so the Porkbun dashboard shows when and by which run records last changed. "+
+					"The workspace and run come from `TFC_WORKSPACE_NAME` and `TFC_RUN_ID` in HCP Terraform, or `TF_WORKSPACE`. "+
+					"The last %d entries are kept and they don't show up as drift of `notes`, can also be set with PORKBUN_NOTES_AUDIT_TRAIL", maxNotesAuditEntries),
+				Optional: true,
+			},
+			"suggest_imports": schema.BoolAttribute{
+				MarkdownDescription: "Warn while planning a new record when the zone already has records of its name and type with other content, " +
+					"suggesting to import one of them instead of creating a near duplicate next to it. Records carrying `managed_notes_marker` are " +
+					"left out, can also be set with PORKBUN_SUGGEST_IMPORTS",
+				Optional: true,
+			},
+			"strict_decode": schema.BoolAttribute{
+				MarkdownDescription: "Log a warning for every API response with fields the provider doesn't know, like attributes Porkbun added to records, " +
+					"so API changes are noticed instead of silently dropped. The warnings show with `TF_LOG=WARN`, can also be set with PORKBUN_STRICT_DECODE",
+				Optional: true,
+			},
+			"default_ttls": schema.MapAttribute{
+				MarkdownDescription: "TTLs of records that don't set `ttl`, keyed by record type like `{ TXT = \"600\", A = \"1h\", MX = \"86400\" }`. " +
+					"Records are only changed to a new default when they are created or updated for another reason",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"api_version": schema.StringAttribute{
+				MarkdownDescription: "Version of the Porkbun API to use, defaults to `v3`",
+				Optional:            true,
+			},
+			"api_host": schema.StringAttribute{
+				MarkdownDescription: "Host the Porkbun API is called on, defaults to `" + porkbunapi.DefaultHost + "`. Set it to `" + porkbunapi.IPv4Host +
+					"` to always call the API over IPv4.",
+				Optional: true,
+			},
+			"mock": schema.BoolAttribute{
+				MarkdownDescription: "Serve every API call from a built-in fake instead of Porkbun, for running `terraform test` without credentials. " +
+					"Any domain is accepted and changes are kept in `.terraform/porkbun-mock.json`, or the file named by `PORKBUN_MOCK_STATE_FILE`.",
+				Optional: true,
 			},
 		},
-	}, nil
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -167,7 +569,9 @@ func New(version string) func() provider.Provider {
 	}
 }
 
-func convertProviderType(in provider.Provider) (porkbunProvider, diag.Diagnostics) {
+// convertProviderType extracts the configured provider from the ProviderData handed to
+// resources and data sources. A nil value means the provider hasn't been configured yet.
+func convertProviderType(in any) (*porkbunProvider, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	p, ok := in.(*porkbunProvider)
@@ -175,9 +579,9 @@ func convertProviderType(in provider.Provider) (porkbunProvider, diag.Diagnostic
 	if !ok {
 		diags.AddError(
 			"Unexpected Provider Instance Type",
-			fmt.Sprintf("While creating the data source or resource, an unexpected provider type (%T) was received. This is always a bug in the provider code and should be reported to the provider developers.", p),
+			fmt.Sprintf("While creating the data source or resource, an unexpected provider type (%T) was received. This is always a bug in the provider code and should be reported to the provider developers.", in),
 		)
-		return porkbunProvider{}, diags
+		return nil, diags
 	}
 
 	if p == nil {
@@ -185,8 +589,8 @@ func convertProviderType(in provider.Provider) (porkbunProvider, diag.Diagnostic
 			"Unexpected Provider Instance Type",
 			"While creating the data source or resource, an unexpected empty provider instance was received. This is always a bug in the provider code and should be reported to the provider developers.",
 		)
-		return porkbunProvider{}, diags
+		return nil, diags
 	}
 
-	return *p, diags
+	return p, diags
 }
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/metaschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ provider.ProviderWithMetaSchema = &porkbunProvider{}
+
+// A module name has to fit into a User-Agent product token
+var moduleNamePattern = regexp.MustCompile(`^[A-Za-z0-9._~+-]+(/[A-Za-z0-9._~+-]+)?$`)
+
+// providerMetaData is what a module sets in its provider_meta "porkbun" block
+type providerMetaData struct {
+	ModuleName types.String `tfsdk:"module_name"`
+}
+
+func (p *porkbunProvider) MetaSchema(ctx context.Context, req provider.MetaSchemaRequest, resp *provider.MetaSchemaResponse) {
+	resp.Schema = metaschema.Schema{
+		Attributes: map[string]metaschema.Attribute{
+			"module_name": metaschema.StringAttribute{
+				MarkdownDescription: "Name of the module, like `dns-records/1.2.0`, added to the User-Agent of the API calls made for its resources",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// withProviderMeta attributes the API calls made with the returned context to the module that declared
+// meta, both in the User-Agent and in the logs
+func withProviderMeta(ctx context.Context, meta tfsdk.Config) (context.Context, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if meta.Raw.IsNull() {
+		return ctx, diags
+	}
+
+	var data providerMetaData
+	diags.Append(meta.Get(ctx, &data)...)
+	if diags.HasError() || data.ModuleName.IsNull() || data.ModuleName.IsUnknown() {
+		return ctx, diags
+	}
+
+	moduleName := data.ModuleName.ValueString()
+	if !moduleNamePattern.MatchString(moduleName) {
+		diags.AddWarning(
+			"Invalid module_name in provider_meta",
+			fmt.Sprintf("%q is left out of the User-Agent, expected a name and an optional version like dns-records/1.2.0", moduleName),
+		)
+		return ctx, diags
+	}
+
+	ctx = tflog.SetField(ctx, "module", moduleName)
+	return porkbunapi.WithUserAgent(ctx, moduleName), diags
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func providerMeta(t *testing.T, moduleName *string) tfsdk.Config {
+	ctx := context.Background()
+
+	var resp provider.MetaSchemaResponse
+	(&porkbunProvider{}).MetaSchema(ctx, provider.MetaSchemaRequest{}, &resp)
+	require.False(t, resp.Diagnostics.HasError())
+
+	objectType := resp.Schema.Type().TerraformType(ctx)
+	return tfsdk.Config{
+		Schema: resp.Schema,
+		Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"module_name": tftypes.NewValue(tftypes.String, moduleName),
+		}),
+	}
+}
+
+func Test_ProviderMetaUserAgent(t *testing.T) {
+	r := require.New(t)
+
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userAgent = req.UserAgent()
+		_, _ = io.WriteString(w, `{"status":"SUCCESS","yourIp":"127.0.0.1"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	client.BaseURL, _ = url.Parse(server.URL)
+	client.UserAgent = "terraform-provider-porkbun/test"
+
+	ping := func(meta tfsdk.Config) (string, bool) {
+		ctx, diags := withProviderMeta(context.Background(), meta)
+		r.False(diags.HasError(), "%v", diags)
+		_, err := client.Ping(ctx)
+		r.NoError(err)
+		return userAgent, diags.WarningsCount() > 0
+	}
+
+	name := "dns-records/1.2.0"
+	ua, warned := ping(providerMeta(t, &name))
+	r.Equal("terraform-provider-porkbun/test dns-records/1.2.0", ua)
+	r.False(warned)
+
+	ua, warned = ping(providerMeta(t, nil))
+	r.Equal("terraform-provider-porkbun/test", ua)
+	r.False(warned)
+
+	// Modules without a provider_meta block
+	ua, _ = ping(tfsdk.Config{})
+	r.Equal("terraform-provider-porkbun/test", ua)
+
+	name = "my module (v1)"
+	ua, warned = ping(providerMeta(t, &name))
+	r.Equal("terraform-provider-porkbun/test", ua)
+	r.True(warned)
+}
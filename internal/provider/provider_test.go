@@ -1,22 +1,30 @@
 package provider
 
 import (
+	"context"
 	"net/url"
+	"testing"
+	"time"
 
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
-	"github.com/nrdcg/porkbun"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
 )
 
 func newPorkbunProvider(testUrl string) provider.Provider {
-	client := porkbun.New("sk1_foobarbaz", "pk1_foobarbaz")
+	client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
 	client.BaseURL, _ = url.Parse(testUrl)
-	return &porkbunProvider{
-		client:     client,
-		configured: true,
-		version:    "test",
-	}
+	p := &porkbunProvider{version: "test"}
+	p.setRuntime(&providerRuntime{
+		client:  client,
+		records: newRecordCache(),
+	})
+	return p
 }
 
 func protoV6ProviderFactories(url string) map[string]func() (tfprotov6.ProviderServer, error) {
@@ -24,3 +32,95 @@ func protoV6ProviderFactories(url string) map[string]func() (tfprotov6.ProviderS
 		"porkbun": providerserver.NewProtocol6WithError(newPorkbunProvider(url)),
 	}
 }
+
+// newTestServer starts a fake Porkbun API and points the provider configuration at it
+func newTestServer(t *testing.T, opts ...porkbuntest.Option) *porkbuntest.Server {
+	server := porkbuntest.NewServer(opts...)
+	t.Cleanup(server.Close)
+
+	t.Setenv("PORKBUN_API_KEY", porkbuntest.APIKey)
+	t.Setenv("PORKBUN_SECRET_KEY", porkbuntest.SecretKey)
+	t.Setenv("PORKBUN_BASE_URL", server.URL)
+	t.Setenv("PORKBUN_MAX_RETRIES", "1")
+
+	return server
+}
+
+func Test_ConfigureSettingsFromEnvironment(t *testing.T) {
+	r := require.New(t)
+
+	newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+	t.Setenv("PORKBUN_MAX_RETRIES", "4")
+	t.Setenv("PORKBUN_CHECK_LIVE_DNS", "true")
+	t.Setenv("PORKBUN_SUGGEST_IMPORTS", "1")
+
+	p := &porkbunProvider{version: "test"}
+	resp := configureProvider(t, p)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	rt := p.runtime()
+	r.Equal(4, rt.retries.attempts)
+	r.True(rt.checkLiveDNS)
+	r.True(rt.suggestImports)
+	r.False(rt.protectCriticalRecords)
+
+	// Every setting stops the provider from being configured when its variable doesn't parse
+	t.Setenv("PORKBUN_PROTECT_CRITICAL_RECORDS", "maybe")
+	resp = configureProvider(t, &porkbunProvider{version: "test"})
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("failed converting protect critical records", resp.Diagnostics[0].Summary())
+
+	t.Setenv("PORKBUN_MAX_RETRIES", "lots")
+	resp = configureProvider(t, &porkbunProvider{version: "test"})
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("failed converting max retries", resp.Diagnostics[0].Summary())
+}
+
+func Test_ConfigurePingsOnce(t *testing.T) {
+	r := require.New(t)
+
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"), porkbuntest.WithRateLimit(1, time.Minute))
+	t.Setenv("PORKBUN_MAX_RETRIES", "3")
+	t.Setenv("PORKBUN_RETRY_WAIT", "300ms")
+
+	// Use up the rate limit so the credentials check is turned away
+	client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	client.BaseURL, _ = url.Parse(server.URL)
+	_, err := client.Ping(context.Background())
+	r.NoError(err)
+
+	// The check isn't retried, configuring every plan shouldn't wait out the retry policy
+	resp := configureProvider(t, &porkbunProvider{version: "test"})
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("Unable to validate Porkbun credentials", resp.Diagnostics[0].Summary())
+	r.Equal(2, server.Calls("ping"))
+
+	t.Setenv("PORKBUN_SKIP_CREDENTIALS_VALIDATION", "true")
+	resp = configureProvider(t, &porkbunProvider{version: "test"})
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Equal(2, server.Calls("ping"))
+}
+
+// configureProvider configures p with an empty provider block, leaving every setting to the environment
+func configureProvider(t *testing.T, p *porkbunProvider) *provider.ConfigureResponse {
+	ctx := context.Background()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	resp := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(objectType, attributes),
+		},
+	}, resp)
+	return resp
+}
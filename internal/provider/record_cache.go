@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// recordCache holds the records retrieved for each domain during a single provider run,
+// indexed by record ID so every resource in a refresh doesn't rescan the whole zone.
+// The provider builds a fresh cache on every Configure, and patches it with the records it writes so later
+// checks in the same run see them.
+type recordCache struct {
+	mu      sync.Mutex
+	domains map[string]*domainRecords
+
+	// hits and misses count calls to get, for the debug logs
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type domainRecords struct {
+	mu     sync.Mutex
+	loaded bool
+	byID   map[string]porkbunapi.Record
+	// shared is set once byID has been handed out by get, a write then has to copy it first
+	shared bool
+	// byValue groups the IDs of records serving the same name, type and content
+	byValue map[string][]string
+}
+
+func newRecordCache() *recordCache {
+	return &recordCache{
+		domains: map[string]*domainRecords{},
+	}
+}
+
+// get returns the records of domain keyed by ID, calling fetch only the first time a domain is requested.
+// Concurrent callers for the same domain wait for the in-flight fetch instead of issuing their own.
+// The map must not be changed, put and remove leave maps handed out before as they were.
+func (c *recordCache) get(ctx context.Context, domain string, fetch func() ([]porkbunapi.Record, error)) (map[string]porkbunapi.Record, error) {
+	return c.load(ctx, domain, fetch, true)
+}
+
+// load makes sure the records of domain are retrieved, returning them only when share is set so callers
+// that just need them loaded, like duplicates, don't make the next write copy the zone
+func (c *recordCache) load(ctx context.Context, domain string, fetch func() ([]porkbunapi.Record, error), share bool) (map[string]porkbunapi.Record, error) {
+	key := strings.ToLower(domain)
+
+	c.mu.Lock()
+	entry, ok := c.domains[key]
+	if !ok {
+		entry = &domainRecords{}
+		c.domains[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.loaded {
+		c.hits.Add(1)
+		c.logStats(ctx, "Record cache hit", key)
+		return entry.handOut(share), nil
+	}
+	c.misses.Add(1)
+	c.logStats(ctx, "Record cache miss", key)
+
+	records, err := fetch()
+	if err != nil {
+		// Don't remember failures, the next caller gets to try again
+		return nil, err
+	}
+
+	entry.byID = indexRecords(records)
+	entry.byValue = indexRecordValues(records)
+	entry.loaded = true
+	return entry.handOut(share), nil
+}
+
+func (e *domainRecords) handOut(share bool) map[string]porkbunapi.Record {
+	if !share {
+		return nil
+	}
+	e.shared = true
+	return e.byID
+}
+
+// put adds or replaces a record the provider created or edited in the cached records of domain. Domains that
+// weren't retrieved yet are left alone, they'll be fetched with the record in them.
+func (c *recordCache) put(ctx context.Context, domain string, record porkbunapi.Record) {
+	c.patch(ctx, domain, record.ID, &record)
+}
+
+// remove drops a record the provider deleted from the cached records of domain
+func (c *recordCache) remove(ctx context.Context, domain string, id string) {
+	c.patch(ctx, domain, id, nil)
+}
+
+// patch replaces the record with id by record, or drops it when record is nil. Only the records involved are
+// reindexed, so an apply writing many records to a large zone doesn't rebuild the whole zone for each of them.
+func (c *recordCache) patch(ctx context.Context, domain string, id string, record *porkbunapi.Record) {
+	key := strings.ToLower(domain)
+
+	c.mu.Lock()
+	entry, ok := c.domains[key]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if !entry.loaded {
+		return
+	}
+
+	if entry.shared {
+		// Callers of get may still hold the map, so it is copied once rather than changed in place
+		entry.byID = maps.Clone(entry.byID)
+		entry.shared = false
+	}
+
+	if old, ok := entry.byID[id]; ok {
+		valueKey := recordValueKey(old)
+		entry.byValue[valueKey] = slices.DeleteFunc(entry.byValue[valueKey], func(other string) bool { return other == id })
+		if len(entry.byValue[valueKey]) == 0 {
+			delete(entry.byValue, valueKey)
+		}
+		delete(entry.byID, id)
+	}
+	if record != nil {
+		valueKey := recordValueKey(*record)
+		entry.byID[id] = *record
+		entry.byValue[valueKey] = append(entry.byValue[valueKey], id)
+	}
+	tflog.Debug(ctx, "Patched record cache", map[string]any{"domain": key, "record_count": len(entry.byID)})
+}
+
+// writtenRecord is how the API will return record once it has been written to domain with id
+func writtenRecord(domain string, id string, record porkbunapi.Record) porkbunapi.Record {
+	record.ID = id
+	record.Name = recordFQDN(record.Name, domain)
+	return record
+}
+
+func (c *recordCache) logStats(ctx context.Context, message string, domain string) {
+	tflog.Debug(ctx, message, map[string]any{
+		"domain":       domain,
+		"cache_hits":   c.hits.Load(),
+		"cache_misses": c.misses.Load(),
+	})
+}
+
+func indexRecords(records []porkbunapi.Record) map[string]porkbunapi.Record {
+	byID := make(map[string]porkbunapi.Record, len(records))
+	for _, record := range records {
+		byID[record.ID] = record
+	}
+	return byID
+}
+
+// duplicates returns the IDs of the other records of domain serving the same name, type and content as
+// record, sorted. The domain's records have to have been retrieved with get before.
+func (c *recordCache) duplicates(domain string, record porkbunapi.Record) []string {
+	c.mu.Lock()
+	entry, ok := c.domains[strings.ToLower(domain)]
+	c.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	var ids []string
+	for _, id := range entry.byValue[recordValueKey(record)] {
+		if id != record.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func indexRecordValues(records []porkbunapi.Record) map[string][]string {
+	byValue := make(map[string][]string, len(records))
+	for _, record := range records {
+		key := recordValueKey(record)
+		byValue[key] = append(byValue[key], record.ID)
+	}
+	return byValue
+}
+
+// recordValueKey identifies what a record serves, record names are fully qualified as the API returns them
+func recordValueKey(record porkbunapi.Record) string {
+	return strings.ToLower(normalizeDnsValue(record.Name)) + "\x00" + strings.ToUpper(record.Type) + "\x00" + normalizeDnsValue(record.Content)
+}
+
+// sortedRecords returns the records ordered by ID so listings are stable between runs
+func sortedRecords(byID map[string]porkbunapi.Record) []porkbunapi.Record {
+	records := make([]porkbunapi.Record, 0, len(byID))
+	for _, record := range byID {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if len(records[i].ID) != len(records[j].ID) {
+			return len(records[i].ID) < len(records[j].ID)
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records
+}
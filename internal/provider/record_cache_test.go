@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordCacheFetchesOncePerDomain(t *testing.T) {
+	r := require.New(t)
+	cache := newRecordCache()
+
+	var mu sync.Mutex
+	fetches := 0
+	fetch := func() ([]porkbunapi.Record, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		return []porkbunapi.Record{
+			{ID: "1", Name: "foobar.dev", Type: "A", Content: "0.0.0.1"},
+			{ID: "2", Name: "www.foobar.dev", Type: "CNAME", Content: "foobar.dev"},
+		}, nil
+	}
+
+	var wg sync.WaitGroup
+	for range []int{1, 2, 3, 4, 5} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records, err := cache.get(context.Background(), "foobar.dev", fetch)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if records["2"].Name != "www.foobar.dev" {
+				t.Errorf("unexpected record %v", records["2"])
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Domains are case-insensitive so this must be served from the same entry
+	_, err := cache.get(context.Background(), "FooBar.dev", fetch)
+	r.NoError(err)
+	r.Equal(1, fetches)
+}
+
+func Test_RecordCacheRetriesAfterError(t *testing.T) {
+	r := require.New(t)
+	cache := newRecordCache()
+
+	_, err := cache.get(context.Background(), "foobar.dev", func() ([]porkbunapi.Record, error) {
+		return nil, errors.New("boom")
+	})
+	r.Error(err)
+
+	records, err := cache.get(context.Background(), "foobar.dev", func() ([]porkbunapi.Record, error) {
+		return []porkbunapi.Record{{ID: "1"}}, nil
+	})
+	r.NoError(err)
+	r.Contains(records, "1")
+}
+
+func Test_RecordCacheDuplicates(t *testing.T) {
+	r := require.New(t)
+	cache := newRecordCache()
+
+	// Nothing is known before the domain is retrieved
+	r.Empty(cache.duplicates("foobar.dev", porkbunapi.Record{Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"}))
+
+	_, err := cache.get(context.Background(), "foobar.dev", func() ([]porkbunapi.Record, error) {
+		return []porkbunapi.Record{
+			{ID: "3", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"},
+			{ID: "1", Name: "WWW.foobar.dev", Type: "a", Content: "0.0.0.1"},
+			{ID: "2", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.2"},
+			{ID: "4", Name: "www.foobar.dev", Type: "CNAME", Content: "foobar.dev."},
+			{ID: "5", Name: "www.foobar.dev", Type: "CNAME", Content: "foobar.dev"},
+		}, nil
+	})
+	r.NoError(err)
+
+	r.Equal([]string{"1", "3"}, cache.duplicates("FOOBAR.dev", porkbunapi.Record{Name: "www.foobar.dev.", Type: "A", Content: "0.0.0.1"}))
+	r.Equal([]string{"1"}, cache.duplicates("foobar.dev", porkbunapi.Record{ID: "3", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"}))
+	r.Equal([]string{"4"}, cache.duplicates("foobar.dev", porkbunapi.Record{ID: "5", Name: "www.foobar.dev", Type: "CNAME", Content: "foobar.dev"}))
+	r.Empty(cache.duplicates("foobar.dev", porkbunapi.Record{ID: "2", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.2"}))
+}
+
+func Test_RecordCachePatches(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	cache := newRecordCache()
+
+	// Domains that weren't retrieved aren't made up from the records written to them
+	cache.put(ctx, "foobar.dev", porkbunapi.Record{ID: "9", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"})
+
+	fetch := func() ([]porkbunapi.Record, error) {
+		return []porkbunapi.Record{{ID: "1", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"}}, nil
+	}
+	records, err := cache.get(ctx, "foobar.dev", fetch)
+	r.NoError(err)
+	r.Len(records, 1)
+
+	cache.put(ctx, "FooBar.dev", porkbunapi.Record{ID: "2", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	cache.put(ctx, "foobar.dev", porkbunapi.Record{ID: "1", Name: "www.foobar.dev", Type: "A", Content: "0.0.0.3"})
+	patched, err := cache.get(ctx, "foobar.dev", fetch)
+	r.NoError(err)
+	r.Len(patched, 2)
+	r.Equal("0.0.0.3", patched["1"].Content)
+	r.Equal([]string{"2"}, cache.duplicates("foobar.dev", porkbunapi.Record{Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"}))
+	// Maps handed out before stay as they were
+	r.Len(records, 1)
+	r.Equal("0.0.0.1", records["1"].Content)
+
+	cache.remove(ctx, "foobar.dev", "2")
+	patched, err = cache.get(ctx, "foobar.dev", fetch)
+	r.NoError(err)
+	r.NotContains(patched, "2")
+	r.Empty(cache.duplicates("foobar.dev", porkbunapi.Record{Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1"}))
+
+	r.Equal(int64(2), cache.hits.Load())
+	r.Equal(int64(1), cache.misses.Load())
+}
+
+func syntheticZone(size int) []porkbunapi.Record {
+	records := make([]porkbunapi.Record, size)
+	for i := range records {
+		records[i] = porkbunapi.Record{
+			ID:      strconv.Itoa(100000 + i),
+			Name:    fmt.Sprintf("host%d.foobar.dev", i),
+			Type:    "A",
+			Content: fmt.Sprintf("10.0.%d.%d", i/256%256, i%256),
+			TTL:     "600",
+		}
+	}
+	return records
+}
+
+func Benchmark_IndexRecords(b *testing.B) {
+	for _, size := range []int{1000, 10000} {
+		zone := syntheticZone(size)
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				indexRecords(zone)
+			}
+		})
+	}
+}
+
+// Benchmark_RefreshZone simulates a refresh where every record in the zone is a managed resource
+func Benchmark_RefreshZone(b *testing.B) {
+	for _, size := range []int{1000, 10000} {
+		zone := syntheticZone(size)
+		fetch := func() ([]porkbunapi.Record, error) { return zone, nil }
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				cache := newRecordCache()
+				for _, want := range zone {
+					records, err := cache.get(context.Background(), "foobar.dev", fetch)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, ok := records[want.ID]; !ok {
+						b.Fatalf("record %s not found", want.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Benchmark_RecordCachePatch writes records to a loaded zone, as an apply does for every record it creates,
+// edits or deletes. After a get the first write copies the zone, the writes following it don't.
+func Benchmark_RecordCachePatch(b *testing.B) {
+	ctx := context.Background()
+	for _, size := range []int{1000, 10000} {
+		zone := syntheticZone(size)
+		fetch := func() ([]porkbunapi.Record, error) { return zone, nil }
+		load := func(b *testing.B) *recordCache {
+			cache := newRecordCache()
+			if _, err := cache.get(ctx, "foobar.dev", fetch); err != nil {
+				b.Fatal(err)
+			}
+			return cache
+		}
+
+		b.Run(strconv.Itoa(size)+"/put", func(b *testing.B) {
+			cache := load(b)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				record := zone[i%size]
+				record.Content = "192.0.2.1"
+				cache.put(ctx, "foobar.dev", record)
+			}
+		})
+
+		b.Run(strconv.Itoa(size)+"/remove", func(b *testing.B) {
+			cache := load(b)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cache.remove(ctx, "foobar.dev", zone[i%size].ID)
+				b.StopTimer()
+				cache.put(ctx, "foobar.dev", zone[i%size])
+				b.StartTimer()
+			}
+		})
+
+		// Every write follows a read holding the zone, so each of them has to copy it
+		b.Run(strconv.Itoa(size)+"/put-after-get", func(b *testing.B) {
+			cache := load(b)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := cache.get(ctx, "foobar.dev", fetch); err != nil {
+					b.Fatal(err)
+				}
+				cache.put(ctx, "foobar.dev", zone[i%size])
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Fixture modes, selected with PORKBUN_FIXTURE_MODE
+const (
+	fixtureModeRecord = "record"
+	fixtureModeReplay = "replay"
+)
+
+// Credentials are stripped from request bodies before they are written to or matched against a fixture
+var fixtureRedactedFields = []string{"apikey", "secretapikey"}
+
+// Key material and the address of whoever recorded the fixture are replaced in response bodies, keeping the
+// field so replayed responses still decode
+var fixtureRedactedResponseFields = []string{"privatekey", "yourIp"}
+
+const fixtureRedactedValue = "REDACTED"
+
+type fixture struct {
+	Interactions []fixtureInteraction `json:"interactions"`
+}
+
+type fixtureInteraction struct {
+	Request  fixtureRequest  `json:"request"`
+	Response fixtureResponse `json:"response"`
+}
+
+type fixtureRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Body   string `json:"body,omitempty"`
+}
+
+type fixtureResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// recorderTransport either captures every API exchange into a fixture file or serves responses from one.
+// Replayed interactions are consumed in order, so a fixture can hold a 503 followed by a success for the same request.
+type recorderTransport struct {
+	mode string
+	path string
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	fixture  fixture
+	consumed []bool
+}
+
+// useFixture puts a recorder between the logging and the network for client, which must come from newHTTPClient
+func useFixture(client *http.Client, mode string, path string) error {
+	logged, err := loggingTransportOf(client)
+	if err != nil {
+		return err
+	}
+
+	recorder, err := newRecorderTransport(mode, path, logged.next)
+	if err != nil {
+		return err
+	}
+	logged.next = recorder
+	return nil
+}
+
+func newRecorderTransport(mode string, path string, next http.RoundTripper) (*recorderTransport, error) {
+	if path == "" {
+		return nil, fmt.Errorf("a fixture path is required in %s mode", mode)
+	}
+
+	t := &recorderTransport{
+		mode: mode,
+		path: path,
+		next: next,
+	}
+
+	switch mode {
+	case fixtureModeRecord:
+	case fixtureModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading fixture: %w", err)
+		}
+		if err := json.Unmarshal(data, &t.fixture); err != nil {
+			return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+		}
+		t.consumed = make([]bool, len(t.fixture.Interactions))
+	default:
+		return nil, fmt.Errorf("unknown fixture mode %q, expected %q or %q", mode, fixtureModeRecord, fixtureModeReplay)
+	}
+
+	return t, nil
+}
+
+func (t *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newFixtureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.mode == fixtureModeReplay {
+		return t.replay(req, recorded)
+	}
+	return t.record(req, recorded)
+}
+
+func (t *recorderTransport) replay(req *http.Request, recorded fixtureRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.fixture.Interactions {
+		if t.consumed[i] || interaction.Request != recorded {
+			continue
+		}
+		t.consumed[i] = true
+
+		header := http.Header{}
+		if interaction.Response.ContentType != "" {
+			header.Set("Content-Type", interaction.Response.ContentType)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no unused interaction in fixture %s for %s %s %s", t.path, recorded.Method, recorded.Path, recorded.Body)
+}
+
+func (t *recorderTransport) record(req *http.Request, recorded fixtureRequest) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fixture.Interactions = append(t.fixture.Interactions, fixtureInteraction{
+		Request: recorded,
+		Response: fixtureResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        redactFixtureResponseBody(body),
+		},
+	})
+
+	// The provider has no shutdown hook, so the whole fixture is rewritten after every exchange
+	data, err := json.MarshalIndent(t.fixture, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(t.path, append(data, '\n'), 0o600); err != nil {
+		return nil, fmt.Errorf("writing fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// newFixtureRequest reads the request body, restoring it for the real transport, and returns the redacted form
+func newFixtureRequest(req *http.Request) (fixtureRequest, error) {
+	recorded := fixtureRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+	}
+	if req.Body == nil {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded.Body = redactFixtureBody(body)
+	return recorded, nil
+}
+
+// redactFixtureBody drops credentials from a JSON body and re-encodes it with sorted keys so matching doesn't depend on field order
+func redactFixtureBody(body []byte) string {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return string(body)
+	}
+	for _, field := range fixtureRedactedFields {
+		delete(payload, field)
+	}
+	if len(payload) == 0 {
+		return ""
+	}
+
+	redacted, err := json.Marshal(payload)
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// redactFixtureResponseBody replaces sensitive values anywhere in a JSON response body. Bodies without any are
+// kept as they came, as are those that aren't JSON like maintenance pages.
+func redactFixtureResponseBody(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Numbers are kept as written, record IDs don't survive a round trip through float64
+	decoder.UseNumber()
+	var payload any
+	if err := decoder.Decode(&payload); err != nil {
+		return string(body)
+	}
+	if !redactFixtureValue(payload) {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(payload)
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+// redactFixtureValue replaces the sensitive fields of value in place and reports whether it found any
+func redactFixtureValue(value any) bool {
+	redacted := false
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if slices.Contains(fixtureRedactedResponseFields, key) {
+				value[key] = fixtureRedactedValue
+				redacted = true
+				continue
+			}
+			redacted = redactFixtureValue(field) || redacted
+		}
+	case []any:
+		for _, item := range value {
+			redacted = redactFixtureValue(item) || redacted
+		}
+	}
+	return redacted
+}
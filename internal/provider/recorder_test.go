@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func newFixtureClient(t *testing.T, baseUrl string, mode string, path string) *porkbunapi.Client {
+	client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	if baseUrl != "" {
+		client.BaseURL, _ = url.Parse(baseUrl)
+	}
+	client.HTTPClient = newHTTPClient(defaultMaxResponseBytes)
+	require.NoError(t, useFixture(client.HTTPClient, mode, path))
+	return client
+}
+
+func Test_RecorderRoundTrip(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	server := porkbuntest.NewServer(porkbuntest.WithDomain("foobar.dev"))
+	recording := newFixtureClient(t, server.URL, fixtureModeRecord, path)
+
+	id, err := recording.CreateRecord(ctx, "foobar.dev", porkbunapi.Record{Name: "test", Type: "A", Content: "0.0.0.1"})
+	r.NoError(err)
+	recorded, err := recording.RetrieveRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	r.NoError(err)
+	r.NotContains(string(data), porkbuntest.APIKey)
+	r.NotContains(string(data), porkbuntest.SecretKey)
+
+	// The server is gone, so these can only be answered from the fixture
+	replaying := newFixtureClient(t, server.URL, fixtureModeReplay, path)
+
+	replayedId, err := replaying.CreateRecord(ctx, "foobar.dev", porkbunapi.Record{Name: "test", Type: "A", Content: "0.0.0.1"})
+	r.NoError(err)
+	r.Equal(id, replayedId)
+	replayed, err := replaying.RetrieveRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Equal(recorded, replayed)
+
+	// Every interaction is used once
+	_, err = replaying.RetrieveRecords(ctx, "foobar.dev")
+	r.ErrorContains(err, "no unused interaction")
+}
+
+func Test_RecorderRedactsResponses(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	server := porkbuntest.NewServer(porkbuntest.WithDomain("foobar.dev"))
+	recording := newFixtureClient(t, server.URL, fixtureModeRecord, path)
+
+	ip, err := recording.Ping(ctx)
+	r.NoError(err)
+	r.Equal("127.0.0.1", ip)
+	bundle, err := recording.RetrieveSSLBundle(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Contains(bundle.PrivateKey, "PRIVATE KEY")
+	server.Close()
+
+	// The caller gets the real response, the fixture doesn't
+	data, err := os.ReadFile(path)
+	r.NoError(err)
+	r.NotContains(string(data), "PRIVATE KEY")
+	r.NotContains(string(data), "127.0.0.1")
+	r.Contains(string(data), "PUBLIC KEY")
+
+	replaying := newFixtureClient(t, server.URL, fixtureModeReplay, path)
+	ip, err = replaying.Ping(ctx)
+	r.NoError(err)
+	r.Equal(fixtureRedactedValue, ip)
+	replayed, err := replaying.RetrieveSSLBundle(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Equal(fixtureRedactedValue, replayed.PrivateKey)
+	r.Equal(bundle.PublicKey, replayed.PublicKey)
+}
+
+func Test_RecorderReplaysRateLimit(t *testing.T) {
+	r := require.New(t)
+	client := newFixtureClient(t, "", fixtureModeReplay, "testdata/fixtures/rate_limited.json")
+
+	id, err := retry(context.Background(), retryPolicy{attempts: 2}, func(ctx context.Context) (string, error) {
+		return client.CreateRecord(ctx, "foobar.dev", porkbunapi.Record{Name: "test", Type: "A", Content: "0.0.0.1"})
+	})
+	r.NoError(err)
+	r.Equal("106926659", id)
+}
+
+func Test_RecorderReplaysMaintenance(t *testing.T) {
+	r := require.New(t)
+	client := newFixtureClient(t, "", fixtureModeReplay, "testdata/fixtures/maintenance.json")
+
+	_, err := retry(context.Background(), retryPolicy{attempts: 2}, func(ctx context.Context) ([]porkbunapi.Record, error) {
+		return client.RetrieveRecords(ctx, "foobar.dev")
+	})
+	r.ErrorContains(err, "after 2 attempts")
+	r.ErrorContains(err, "scheduled maintenance")
+}
+
+func Test_RecorderRejectsBadConfig(t *testing.T) {
+	r := require.New(t)
+
+	r.ErrorContains(useFixture(newHTTPClient(defaultMaxResponseBytes), "rewind", "fixture.json"), "unknown fixture mode")
+	r.ErrorContains(useFixture(newHTTPClient(defaultMaxResponseBytes), fixtureModeReplay, ""), "fixture path is required")
+	r.ErrorContains(useFixture(newHTTPClient(defaultMaxResponseBytes), fixtureModeReplay, "testdata/fixtures/missing.json"), "reading fixture")
+}
+
+func Test_RedactFixtureBody(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("", redactFixtureBody([]byte(`{"apikey":"pk1","secretapikey":"sk1"}`)))
+	r.Equal(`{"content":"0.0.0.1","name":"test"}`, redactFixtureBody([]byte(`{"secretapikey":"sk1","name":"test","apikey":"pk1",   "content":"0.0.0.1"}`)))
+	r.Equal("not json", redactFixtureBody([]byte("not json")))
+}
+
+func Test_RedactFixtureResponseBody(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(`{"records":[{"id":106926659,"privatekey":"REDACTED"}],"status":"SUCCESS"}`, redactFixtureResponseBody([]byte(`{"status":"SUCCESS","records":[{"id":106926659,"privatekey":"key"}]}`)))
+	r.Equal(`{"status": "SUCCESS", "id": 106926659}`, redactFixtureResponseBody([]byte(`{"status": "SUCCESS", "id": 106926659}`)))
+	r.Equal("<html>maintenance</html>", redactFixtureResponseBody([]byte("<html>maintenance</html>")))
+}
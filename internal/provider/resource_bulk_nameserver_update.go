@@ -0,0 +1,414 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &porkbunBulkNameserverUpdateResource{}
+var _ resource.ResourceWithConfigure = &porkbunBulkNameserverUpdateResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunBulkNameserverUpdateResource{}
+var _ resource.ResourceWithModifyPlan = &porkbunBulkNameserverUpdateResource{}
+
+// What happened to the nameservers of each domain, reported in status
+const (
+	nameserverStatusUpdated   = "updated"
+	nameserverStatusUnchanged = "unchanged"
+	nameserverStatusFailed    = "failed"
+	nameserverStatusDrifted   = "drifted"
+)
+
+func NewBulkNameserverUpdateResource() resource.Resource {
+	return &porkbunBulkNameserverUpdateResource{}
+}
+
+type porkbunBulkNameserverUpdateResource struct {
+	provider *porkbunProvider
+}
+
+type porkbunBulkNameserverUpdateResourceData struct {
+	Id          types.String `tfsdk:"id"`
+	Nameservers types.List   `tfsdk:"nameservers"`
+	Domains     types.Set    `tfsdk:"domains"`
+	Label       types.String `tfsdk:"label"`
+	Status      types.Map    `tfsdk:"status"`
+}
+
+func (r *porkbunBulkNameserverUpdateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bulk_nameserver_update"
+}
+
+func (r *porkbunBulkNameserverUpdateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sets the same nameservers on a list of domains, or on every domain of the account with a label, for moving many domains " +
+			"to other DNS hosting at once. Domains already using the nameservers are left alone and one failing domain doesn't stop the others, " +
+			"`status` tells what happened to each. A refresh finding nameservers changed outside of Terraform, or new domains with the label, " +
+			"plans to set them again. Destroying the resource leaves the nameservers as they are",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The domains the nameservers are set on, `label:` followed by the label or the sorted domains joined with commas",
+			},
+			"nameservers": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The nameservers to set, like `[\"kim.ns.cloudflare.com\", \"bob.ns.cloudflare.com\"]`",
+			},
+			"domains": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The domains to set the nameservers on. Exactly one of `domains` and `label` is required",
+			},
+			"label": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The title of a label in the Porkbun dashboard, the nameservers are set on every domain carrying it. " +
+					"Exactly one of `domains` and `label` is required",
+			},
+			"status": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "What happened to the nameservers of each domain, keyed by domain. " +
+					"`updated` when the last apply set them, `unchanged` when the domain already used them, `failed` when setting them failed " +
+					"and `drifted` when a refresh found other nameservers",
+			},
+		},
+	}
+}
+
+func (r *porkbunBulkNameserverUpdateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunBulkNameserverUpdateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunBulkNameserverUpdateResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Domains.IsUnknown() && !data.Label.IsUnknown() && data.Domains.IsNull() == data.Label.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("domains"),
+			"Invalid domain selection",
+			"Exactly one of domains and label is required",
+		)
+	}
+
+	if !data.Nameservers.IsUnknown() && len(data.Nameservers.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("nameservers"),
+			"Missing nameservers",
+			"At least one nameserver is required, Porkbun doesn't accept removing all of them",
+		)
+	}
+}
+
+// ModifyPlan plans the nameservers to be set again when the last refresh found domains that don't use them
+func (r *porkbunBulkNameserverUpdateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan porkbunBulkNameserverUpdateResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), bulkNameserverUpdateID(plan))...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state porkbunBulkNameserverUpdateResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	var status map[string]string
+	resp.Diagnostics.Append(state.Status.ElementsAs(ctx, &status, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var pending []string
+	for domain, s := range status {
+		if s == nameserverStatusFailed || s == nameserverStatusDrifted {
+			pending = append(pending, domain)
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		tflog.Info(ctx, "Domains don't use the nameservers, planning to set them again", map[string]any{"domains": pending})
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.MapUnknown(types.StringType))...)
+	}
+}
+
+func (r *porkbunBulkNameserverUpdateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunBulkNameserverUpdateResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	data, diags = r.apply(ctx, data)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunBulkNameserverUpdateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunBulkNameserverUpdateResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	var desired []string
+	var previous map[string]string
+	resp.Diagnostics.Append(data.Nameservers.ElementsAs(ctx, &desired, false)...)
+	resp.Diagnostics.Append(data.Status.ElementsAs(ctx, &previous, false)...)
+	domains, diags := r.targetDomains(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status := make(map[string]string, len(domains))
+	for _, domain := range domains {
+		nameservers, err := retry(ctx, rt.retries, func(ctx context.Context) ([]string, error) {
+			return rt.client.GetNameservers(ctx, domain)
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Could not retrieve the nameservers of %s.", domain),
+				apiErrorDetail(err),
+			)
+			return
+		}
+		status[domain] = refreshNameserverStatus(previous[domain], nameservers, desired)
+	}
+
+	data.Status = stringMapValue(status)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunBulkNameserverUpdateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunBulkNameserverUpdateResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	data, diags = r.apply(ctx, data)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunBulkNameserverUpdateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// The domains keep their nameservers, there is no telling which ones they should go back to
+	tflog.Debug(ctx, "Removed bulk nameserver update from state, nameservers are left as they are")
+}
+
+// apply sets the nameservers on every domain that doesn't use them yet. Domains failing are reported as
+// errors and in status while the others are still updated.
+func (r *porkbunBulkNameserverUpdateResource) apply(ctx context.Context, data porkbunBulkNameserverUpdateResourceData) (porkbunBulkNameserverUpdateResourceData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	var desired []string
+	diags.Append(data.Nameservers.ElementsAs(ctx, &desired, false)...)
+	domains, targetDiags := r.targetDomains(ctx, data)
+	diags.Append(targetDiags...)
+	data.Id = bulkNameserverUpdateID(data)
+	if diags.HasError() {
+		data.Status = stringMapValue(map[string]string{})
+		return data, diags
+	}
+
+	status := make(map[string]string, len(domains))
+	for _, domain := range domains {
+		ctx := tflog.SetField(ctx, "domain", domain)
+
+		current, err := retry(ctx, rt.retries, func(ctx context.Context) ([]string, error) {
+			return rt.client.GetNameservers(ctx, domain)
+		})
+		if err != nil {
+			status[domain] = nameserverStatusFailed
+			diags.AddError(
+				fmt.Sprintf("Could not retrieve the nameservers of %s.", domain),
+				apiErrorDetail(err),
+			)
+			continue
+		}
+		if sameNameservers(current, desired) {
+			status[domain] = nameserverStatusUnchanged
+			continue
+		}
+
+		err = retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+			return rt.client.UpdateNameservers(ctx, domain, desired)
+		})
+		if err != nil {
+			status[domain] = nameserverStatusFailed
+			diags.AddError(
+				fmt.Sprintf("Could not set the nameservers of %s.", domain),
+				apiErrorDetail(err),
+			)
+			continue
+		}
+		tflog.Debug(ctx, "Set nameservers", map[string]any{"previous": current, "nameservers": desired})
+		status[domain] = nameserverStatusUpdated
+	}
+
+	if len(domains) == 0 && !data.Label.IsNull() {
+		diags.AddWarning(
+			"No domains to update",
+			fmt.Sprintf("No domain of the account carries the label %q, the nameservers are set once domains get it and the resource is refreshed", data.Label.ValueString()),
+		)
+	}
+
+	data.Status = stringMapValue(status)
+	return data, diags
+}
+
+// targetDomains returns the domains data selects, lower case and sorted
+func (r *porkbunBulkNameserverUpdateResource) targetDomains(ctx context.Context, data porkbunBulkNameserverUpdateResourceData) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	var domains []string
+	if !data.Domains.IsNull() {
+		diags.Append(data.Domains.ElementsAs(ctx, &domains, false)...)
+		for i, domain := range domains {
+			domains[i] = strings.ToLower(normalizeDnsValue(domain))
+		}
+		sort.Strings(domains)
+		return domains, diags
+	}
+
+	all, err := retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Domain, error) {
+		return rt.client.ListDomains(ctx)
+	})
+	if err != nil {
+		diags.AddError(
+			"Could not list domains.",
+			apiErrorDetail(err),
+		)
+		return nil, diags
+	}
+	domains = labeledDomains(all, data.Label.ValueString())
+	tflog.Debug(ctx, "Found domains with the label", map[string]any{"label": data.Label.ValueString(), "domain_count": len(domains)})
+	return domains, diags
+}
+
+// labeledDomains returns the names of the domains carrying a label titled label, lower case and sorted
+func labeledDomains(domains []porkbunapi.Domain, label string) []string {
+	var labeled []string
+	for _, domain := range domains {
+		for _, l := range domain.Labels {
+			if strings.EqualFold(strings.TrimSpace(l.Title), strings.TrimSpace(label)) {
+				labeled = append(labeled, strings.ToLower(domain.Domain))
+				break
+			}
+		}
+	}
+	sort.Strings(labeled)
+	return labeled
+}
+
+// sameNameservers reports whether current and desired are the same nameservers in any order
+func sameNameservers(current []string, desired []string) bool {
+	return strings.Join(sortedNameservers(current), ",") == strings.Join(sortedNameservers(desired), ",")
+}
+
+// refreshNameserverStatus is the status of a domain using nameservers after a refresh. Domains keep what
+// the last apply did to them while they use the desired nameservers.
+func refreshNameserverStatus(previous string, nameservers []string, desired []string) string {
+	if !sameNameservers(nameservers, desired) {
+		if previous == nameserverStatusFailed {
+			return nameserverStatusFailed
+		}
+		return nameserverStatusDrifted
+	}
+	if previous == nameserverStatusUpdated || previous == nameserverStatusUnchanged {
+		return previous
+	}
+	return nameserverStatusUnchanged
+}
+
+func bulkNameserverUpdateID(data porkbunBulkNameserverUpdateResourceData) types.String {
+	if data.Domains.IsUnknown() || data.Label.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if !data.Label.IsNull() {
+		return types.StringValue("label:" + data.Label.ValueString())
+	}
+
+	domains := make([]string, 0, len(data.Domains.Elements()))
+	for _, domain := range data.Domains.Elements() {
+		domain, ok := domain.(types.String)
+		if !ok || domain.IsUnknown() {
+			return types.StringUnknown()
+		}
+		domains = append(domains, strings.ToLower(normalizeDnsValue(domain.ValueString())))
+	}
+	sort.Strings(domains)
+	return types.StringValue(strings.Join(domains, ","))
+}
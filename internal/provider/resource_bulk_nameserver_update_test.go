@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BulkNameserverUpdate(t *testing.T) {
+	server := newTestServer(t,
+		porkbuntest.WithLabels("foobar.dev", "Migrate"),
+		porkbuntest.WithLabels("other.dev", "migrate", "prod"),
+		porkbuntest.WithNameservers("moved.dev", "bob.ns.cloudflare.com", "kim.ns.cloudflare.com"),
+		porkbuntest.WithLabels("moved.dev", "migrate"),
+		porkbuntest.WithDomain("stays.dev"),
+	)
+	cloudflare := []string{"kim.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+	porkbun := []string{"curitiba.ns.porkbun.com", "fortaleza.ns.porkbun.com", "maceio.ns.porkbun.com", "salvador.ns.porkbun.com"}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_bulk_nameserver_update" "test" {
+            label       = "migrate"
+            nameservers = ["kim.ns.cloudflare.com", "bob.ns.cloudflare.com"]
+          }
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_bulk_nameserver_update.test", "id", "label:migrate"),
+					resource.TestCheckResourceAttr("porkbun_bulk_nameserver_update.test", "status.%", "3"),
+					resource.TestCheckResourceAttr("porkbun_bulk_nameserver_update.test", "status.foobar.dev", "updated"),
+					resource.TestCheckResourceAttr("porkbun_bulk_nameserver_update.test", "status.other.dev", "updated"),
+					resource.TestCheckResourceAttr("porkbun_bulk_nameserver_update.test", "status.moved.dev", "unchanged"),
+					func(*terraform.State) error {
+						require.Equal(t, cloudflare, server.Nameservers("foobar.dev"))
+						require.Equal(t, porkbun, server.Nameservers("stays.dev"))
+						return nil
+					},
+				),
+			},
+			{
+				// Changed back in the dashboard, the refresh notices and the apply sets them again
+				PreConfig: func() {
+					client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+					client.BaseURL, _ = url.Parse(server.URL)
+					require.NoError(t, client.UpdateNameservers(context.Background(), "other.dev", porkbun))
+				},
+				Config: `
+          resource "porkbun_bulk_nameserver_update" "test" {
+            label       = "migrate"
+            nameservers = ["kim.ns.cloudflare.com", "bob.ns.cloudflare.com"]
+          }
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_bulk_nameserver_update.test", "status.other.dev", "updated"),
+					resource.TestCheckResourceAttr("porkbun_bulk_nameserver_update.test", "status.foobar.dev", "unchanged"),
+					func(*terraform.State) error {
+						require.Equal(t, cloudflare, server.Nameservers("other.dev"))
+						return nil
+					},
+				),
+			},
+			{
+				Config: `
+          resource "porkbun_bulk_nameserver_update" "test" {
+            domains     = ["Stays.dev", "missing.dev"]
+            nameservers = ["ns1.example.com", "ns2.example.com"]
+          }
+				`,
+				ExpectError: regexp.MustCompile(`Could not retrieve the nameservers of missing.dev`),
+			},
+		},
+	})
+	// The domain that exists was still updated
+	require.Equal(t, []string{"ns1.example.com", "ns2.example.com"}, server.Nameservers("stays.dev"))
+}
+
+func Test_BulkNameserverUpdateRequiresOneSelection(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_bulk_nameserver_update" "test" {
+            domains     = ["foobar.dev"]
+            label       = "migrate"
+            nameservers = ["ns1.example.com"]
+          }
+				`,
+				ExpectError: regexp.MustCompile(`Exactly one of domains and label is required`),
+			},
+		},
+	})
+}
+
+func Test_RefreshNameserverStatus(t *testing.T) {
+	r := require.New(t)
+	desired := []string{"kim.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+
+	r.Equal("updated", refreshNameserverStatus("updated", []string{"Bob.NS.Cloudflare.com.", "kim.ns.cloudflare.com"}, desired))
+	r.Equal("unchanged", refreshNameserverStatus("", desired, desired))
+	r.Equal("unchanged", refreshNameserverStatus("drifted", desired, desired))
+	r.Equal("drifted", refreshNameserverStatus("updated", []string{"curitiba.ns.porkbun.com"}, desired))
+	r.Equal("drifted", refreshNameserverStatus("", []string{"curitiba.ns.porkbun.com"}, desired))
+	r.Equal("failed", refreshNameserverStatus("failed", []string{"curitiba.ns.porkbun.com"}, desired))
+}
+
+func Test_LabeledDomains(t *testing.T) {
+	domains := []porkbunapi.Domain{
+		{Domain: "Foobar.dev", Labels: []porkbunapi.Label{{Title: "prod"}, {Title: "Migrate "}}},
+		{Domain: "other.dev", Labels: []porkbunapi.Label{{Title: "prod"}}},
+		{Domain: "another.dev", Labels: []porkbunapi.Label{{Title: "migrate"}}},
+		{Domain: "plain.dev"},
+	}
+	require.Equal(t, []string{"another.dev", "foobar.dev"}, labeledDomains(domains, "migrate"))
+	require.Empty(t, labeledDomains(domains, "staging"))
+}
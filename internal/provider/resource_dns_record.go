@@ -2,151 +2,705 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
-	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/nrdcg/porkbun"
 	"golang.org/x/exp/slices"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
-var _ provider.ResourceType = porkbunDnsRecordResourceType{}
-var _ resource.Resource = porkbunDnsRecordResource{}
-var _ resource.ResourceWithImportState = porkbunDnsRecordResource{}
+var _ resource.Resource = &porkbunDnsRecordResource{}
+var _ resource.ResourceWithConfigure = &porkbunDnsRecordResource{}
+var _ resource.ResourceWithImportState = &porkbunDnsRecordResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunDnsRecordResource{}
+var _ resource.ResourceWithIdentity = &porkbunDnsRecordResource{}
+var _ resource.ResourceWithModifyPlan = &porkbunDnsRecordResource{}
 
-// The API returns a string of "SUCCESS" or "ERROR" except for when we're rate limited
-// We get a 503 and the go library expects a string so we need to treat this as a string for now
-var retryableCodes = []int{503}
+func NewDnsRecordResource() resource.Resource {
+	return &porkbunDnsRecordResource{lookup: lookupRecord}
+}
 
-var (
-	sleep = 10
-)
+type porkbunDnsRecordResource struct {
+	provider *porkbunProvider
+	lookup   dnsLookupFunc
+}
 
-type porkbunDnsRecordResourceType struct{}
+type porkbunDnsRecordResourceData struct {
+	Id      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Type    types.String `tfsdk:"type"`
+	Content types.String `tfsdk:"content"`
+	Ttl     types.String `tfsdk:"ttl"`
+	Notes   types.String `tfsdk:"notes"`
+	Prio    types.String `tfsdk:"prio"`
+	Domain  types.String `tfsdk:"domain"`
 
-func (t porkbunDnsRecordResourceType) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
-	return tfsdk.Schema{
+	ContentFields    types.Map    `tfsdk:"content_fields"`
+	ContentWo        types.String `tfsdk:"content_wo"`
+	ContentWoVersion types.Int64  `tfsdk:"content_wo_version"`
+	ContentFromFile  types.String `tfsdk:"content_from_file"`
+	ContentBase64    types.String `tfsdk:"content_base64"`
+	SemanticCompare  types.Bool   `tfsdk:"semantic_compare"`
+	KeepOnDestroy    types.Bool   `tfsdk:"keep_on_destroy"`
+	Disabled         types.Bool   `tfsdk:"disabled"`
+
+	AllowCriticalChanges types.Bool `tfsdk:"allow_critical_changes"`
+
+	WaitForPropagation   types.Bool   `tfsdk:"wait_for_propagation"`
+	PropagationTimeout   types.String `tfsdk:"propagation_timeout"`
+	PropagationInterval  types.String `tfsdk:"propagation_interval"`
+	PropagationResolvers types.List   `tfsdk:"propagation_resolvers"`
+
+	WaitForDelegation types.Bool `tfsdk:"wait_for_delegation"`
+}
+
+func (r *porkbunDnsRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_record"
+}
+
+func (r *porkbunDnsRecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Example resource",
+		Version:             dnsRecordSchemaVersion,
 
-		Attributes: map[string]tfsdk.Attribute{
-			"name": {
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
 				MarkdownDescription: "The subdomain for the record itself without the base domain",
 				Required:            true,
-				Type:                types.StringType,
 			},
-			"domain": {
+			"domain": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "The base domain to to create the record on",
-				//PlanModifiers: tfsdk.AttributePlanModifiers{
-				//	resource.UseStateForUnknown(),
-				//},
-				Type: types.StringType,
+				MarkdownDescription: "The base domain to to create the record on. A domain only known after apply defers the record to a later plan when Terraform supports deferred actions. Changing it moves the record, creating it in the new domain before deleting the old one",
 			},
-			"id": {
+			"id": schema.StringAttribute{
 				Computed:            true,
-				Optional:            false,
-				MarkdownDescription: "The Porkbun ID of the Record",
-				Type:                types.StringType,
+				MarkdownDescription: "The Porkbun ID of the Record, null while it is `disabled`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"ttl": {
+			"ttl": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The ttl of the record, the minimum  is 600",
-				Type:                types.StringType,
+				MarkdownDescription: "The ttl of the record in seconds or as a duration like `1h`, between 600 and 86400 seconds. Values other than common ones like 600, 3600 or 86400 get a warning. Defaults to the provider's `default_ttls` for the type, or Porkbun's default of 600",
 			},
-			"type": {
+			"type": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "The type of DNS Record to create",
-				Type:                types.StringType,
 			},
-			"notes": {
+			"notes": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "Notes to add to the record",
-				Type:                types.StringType,
 			},
-			"prio": {
+			"prio": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "The priority of the record",
-				Type:                types.StringType,
 			},
-			"content": {
+			"content": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The content of the record. Computed from `content_from_file` or `content_base64` when one of them is set. HTTPS and SVCB parameters are validated while planning and their order doesn't cause a diff. DMARC policies in TXT records named `_dmarc` get warnings for weak settings like `p=none` without `rua`. DKIM keys in TXT records at a `_domainkey` selector are validated, and a new one can't be planned at a selector that already holds a different key",
+			},
+			"content_fields": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "The parts of structured content, so plans show which one changes: `weight`, `port` and `target` of SRV records, " +
+					"the tags of DKIM keys and DMARC policies and the terms of SPF policies grouped by mechanism, like `include` or `all`. " +
+					"Null for other records",
+			},
+			"content_wo": schema.StringAttribute{
 				Optional:            true,
-				MarkdownDescription: "The content of the record",
-				Type:                types.StringType,
+				WriteOnly:           true,
+				Sensitive:           true,
+				MarkdownDescription: "Write-only alternative to `content` for secret values such as verification tokens. It is sent to Porkbun but never stored in plan or state, requires Terraform 1.11 or later",
+			},
+			"content_wo_version": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Change this value to send a new `content_wo` to Porkbun, write-only values are not compared between runs",
+			},
+			"content_from_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Path of a file holding the content, like a generated DKIM or TLSA key, relative to the directory Terraform runs in. " +
+					"The file is read while planning, its lines are joined and surrounding whitespace is dropped. A changed file plans a change of `content`",
+			},
+			"content_base64": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The content encoded as standard base64, decoded and normalized like `content_from_file`, " +
+					"for content from a source that only hands out base64",
+			},
+			"semantic_compare": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Compare the content of TXT records by their value, ignoring how it is split into quoted strings and surrounding whitespace, " +
+					"so the way Porkbun stores long values doesn't show up as drift",
+			},
+			"allow_critical_changes": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Allow changing or deleting this record when the provider sets `protect_critical_records`. " +
+					"Deleting uses the value in state, so it has to be applied before the destroy",
+			},
+			"keep_on_destroy": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Leave the record at Porkbun when the resource is destroyed, only removing it from state. " +
+					"The value in state is used, so it has to be applied before the destroy",
+			},
+			"disabled": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Remove the record from the zone while keeping the resource, for cutovers and maintenance windows. " +
+					"Setting it back to false creates the record again with a new ID. Disabled records aren't refreshed",
+			},
+			"wait_for_propagation": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Wait after creating or updating the record until every resolver in `propagation_resolvers` serves the new content, " +
+					"so resources depending on it don't start before it resolves. Supported for " + strings.Join(lookupRecordTypes, ", ") + " records",
+			},
+			"wait_for_delegation": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Only create the record once the registry lists Porkbun's nameservers for the domain, " +
+					"like when the same configuration moves the domain to Porkbun with `porkbun_bulk_nameserver_update`. " +
+					"Until then the record is deferred to a later run, which needs Terraform with deferred actions, otherwise the plan fails",
+			},
+			"propagation_timeout": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long to wait for propagation in seconds or as a duration like `10m`, defaults to `" + defaultPropagationTimeout.String() + "`",
+			},
+			"propagation_interval": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "How long to wait between checks of the resolvers in seconds or as a duration like `30s`, defaults to `" + defaultPropagationInterval.String() + "`. " +
+					"Propagation is polled apart from API calls, so waiting for it doesn't count against `max_retries`",
+			},
+			"propagation_resolvers": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Resolvers to wait for as IP addresses or `host:port`, defaults to Porkbun's authoritative nameservers. " +
+					"Public resolvers may keep serving a cached answer until its TTL runs out",
 			},
 		},
-	}, nil
+	}
 }
 
-func (t porkbunDnsRecordResourceType) NewResource(ctx context.Context, in provider.Provider) (resource.Resource, diag.Diagnostics) {
-	provider, diags := convertProviderType(in)
+func (r *porkbunDnsRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
 
-	return porkbunDnsRecordResource{
-		provider: provider,
-	}, diags
+	r.provider = provider
 }
 
-type porkbunDnsRecordResourceData struct {
-	Id      types.String `tfsdk:"id"`
-	Name    types.String `tfsdk:"name"`
-	Type    types.String `tfsdk:"type"`
-	Content types.String `tfsdk:"content"`
-	Ttl     types.String `tfsdk:"ttl"`
-	Notes   types.String `tfsdk:"notes"`
-	Prio    types.String `tfsdk:"prio"`
-	Domain  types.String `tfsdk:"domain"`
+func (r *porkbunDnsRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunDnsRecordResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sources := 0
+	for _, source := range []types.String{data.Content, data.ContentWo, data.ContentFromFile, data.ContentBase64} {
+		if !source.IsNull() {
+			sources++
+		}
+	}
+	if sources > 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Conflicting content",
+			"Only one of content, content_wo, content_from_file and content_base64 can be set",
+		)
+	}
+
+	if !data.ContentBase64.IsNull() && !data.ContentBase64.IsUnknown() {
+		if _, err := contentFromBase64(data.ContentBase64.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("content_base64"), "Invalid content", err.Error())
+		}
+	}
+
+	if isSvcbType(data.Type.ValueString()) && !data.Content.IsNull() && !data.Content.IsUnknown() {
+		if _, err := parseSvcbContent(data.Content.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("content"),
+				"Invalid service binding",
+				fmt.Sprintf("The content of %s records is a priority, a target and parameters like alpn=h2,h3: %s", strings.ToUpper(data.Type.ValueString()), err),
+			)
+		}
+	}
+
+	if !data.Ttl.IsNull() && !data.Ttl.IsUnknown() {
+		warning, err := validateTTL(data.Ttl.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid TTL", err.Error())
+		} else if warning != "" {
+			resp.Diagnostics.AddAttributeWarning(path.Root("ttl"), "Unusual TTL", warning)
+		}
+	}
+
+	if isDKIMRecord(data.Type.ValueString(), data.Name.ValueString()) && !data.Content.IsNull() && !data.Content.IsUnknown() {
+		if err := validateDKIM(data.Content.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("content"),
+				"Invalid DKIM key",
+				fmt.Sprintf("Records at a _domainkey selector publish a DKIM key like v=DKIM1; k=rsa; p=<base64 key>: %s", err),
+			)
+		}
+	}
+
+	if isDMARCRecord(data.Type.ValueString(), data.Name.ValueString()) && !data.Content.IsUnknown() {
+		for _, warning := range dmarcWarnings(data.Content.ValueString()) {
+			resp.Diagnostics.AddAttributeWarning(path.Root("content"), "Weak DMARC policy", warning)
+		}
+	}
+
+	if data.SemanticCompare.ValueBool() && !data.Type.IsUnknown() && !strings.EqualFold(data.Type.ValueString(), "TXT") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("semantic_compare"),
+			"Unsupported semantic comparison",
+			fmt.Sprintf("semantic_compare only applies to TXT records, not %s", strings.ToUpper(data.Type.ValueString())),
+		)
+	}
+
+	resp.Diagnostics.Append(validatePropagationConfig(data)...)
 }
 
-type porkbunDnsRecordResource struct {
-	provider porkbunProvider
+// isCriticalRecord reports whether a record is one mail or the website of a domain depends on
+func isCriticalRecord(recordType string, name string) bool {
+	switch strings.ToUpper(recordType) {
+	case "MX", "NS":
+		return true
+	case "A", "AAAA", "ALIAS":
+		return name == "" || name == "@"
+	}
+	return false
+}
+
+// recordChanged reports whether plan changes anything about the record at Porkbun
+func recordChanged(state porkbunDnsRecordResourceData, plan porkbunDnsRecordResourceData) bool {
+	return !state.Domain.Equal(plan.Domain) ||
+		!state.Name.Equal(plan.Name) ||
+		!state.Type.Equal(plan.Type) ||
+		!state.Content.Equal(plan.Content) ||
+		!state.Ttl.Equal(plan.Ttl) ||
+		!state.Prio.Equal(plan.Prio) ||
+		!state.Notes.Equal(plan.Notes) ||
+		!state.ContentWoVersion.Equal(plan.ContentWoVersion) ||
+		state.Disabled.ValueBool() != plan.Disabled.ValueBool()
 }
 
-func (r porkbunDnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+// criticalRecordDiagnostics fails plans changing or deleting critical records while protect_critical_records
+// is enabled, unless the resource sets allow_critical_changes. Creating them is always allowed. plan is the
+// plan with content from content_from_file or content_base64, whose change the config doesn't show.
+func (r *porkbunDnsRecordResource) criticalRecordDiagnostics(ctx context.Context, req resource.ModifyPlanRequest, plan tfsdk.Plan) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.provider == nil || !r.provider.runtime().protectCriticalRecords || req.State.Raw.IsNull() {
+		return diags
+	}
+
+	var state porkbunDnsRecordResourceData
+	diags.Append(req.State.Get(ctx, &state)...)
+	if diags.HasError() || !isCriticalRecord(state.Type.ValueString(), state.Name.ValueString()) {
+		return diags
+	}
+
+	outcome, allowed := "deleted", state.AllowCriticalChanges
+	if !plan.Raw.IsNull() {
+		var planned porkbunDnsRecordResourceData
+		diags.Append(plan.Get(ctx, &planned)...)
+		if diags.HasError() || !recordChanged(state, planned) {
+			return diags
+		}
+		outcome, allowed = "changed", planned.AllowCriticalChanges
+	}
+	if allowed.ValueBool() {
+		tflog.Info(ctx, "Critical record allowed to be "+outcome)
+		return diags
+	}
+
+	diags.AddError(
+		"Critical record protected",
+		fmt.Sprintf("protect_critical_records is enabled, so the %s record %s of %s won't be %s. "+
+			"Set allow_critical_changes = true on the resource to go ahead, for a delete it has to be applied first.",
+			strings.ToUpper(state.Type.ValueString()), state.Id.ValueString(), recordFQDN(state.Name.ValueString(), state.Domain.ValueString()), outcome),
+	)
+	return diags
+}
+
+// ModifyPlan plans content read from content_from_file or content_base64, defers records whose domain isn't
+// known yet, plans a new ID for records moving to another domain and stops new DKIM keys from taking over a
+// selector already in use. With wait_for_delegation new records wait for the domain to be delegated to
+// Porkbun. With suggest_imports new records warn about live records they were likely meant to take over. When
+// check_live_dns is enabled it warns about records that won't resolve once created and SPF policies taking too
+// many DNS lookups. Only creates are checked for resolving, an existing record is expected to be found in DNS.
+func (r *porkbunDnsRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(planContentSource(ctx, req, resp)...)
+	}
+	resp.Diagnostics.Append(r.criticalRecordDiagnostics(ctx, req, resp.Plan)...)
+	if req.Plan.Raw.IsNull() || resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The domain may come from a resource that hasn't been applied yet, like a domain registered in the
+	// same run. Terraform can plan the record in a later round once the domain is known.
+	if req.ClientCapabilities.DeferralAllowed {
+		var domain types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("domain"), &domain)...)
+		if domain.IsUnknown() {
+			tflog.Debug(ctx, "Deferring record with unknown domain")
+			resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonResourceConfigUnknown}
+			return
+		}
+	}
+
+	// Moving a record to another domain gives it a new ID, also when the new domain is only known after apply
+	if !req.State.Raw.IsNull() {
+		var stateDomain, planDomain types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("domain"), &stateDomain)...)
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("domain"), &planDomain)...)
+		if planDomain.IsUnknown() || !strings.EqualFold(planDomain.ValueString(), stateDomain.ValueString()) {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+		}
+	}
+
+	// Disabled records have no ID, enabling one again creates it with a new ID
+	var disabled, stateDisabled types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("disabled"), &disabled)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("disabled"), &stateDisabled)...)
+	}
+	if disabled.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringNull())...)
+	} else if stateDisabled.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+	}
+
+	if r.provider == nil {
+		return
+	}
+
 	var data porkbunDnsRecordResourceData
-	attempts := r.provider.MaxRetries
+	diags := resp.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
 
-	diags := req.Config.Get(ctx, &data)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content_fields"), contentFieldsValue(data))...)
+
+	// Nothing is sent to Porkbun for a disabled record, so there's nothing to check
+	if data.Disabled.ValueBool() {
+		return
+	}
+
+	if req.State.Raw.IsNull() && data.WaitForDelegation.ValueBool() && !data.Domain.IsUnknown() {
+		diags, delegated := r.delegationGate(ctx, data.Domain.ValueString(), req.ClientCapabilities.DeferralAllowed)
+		resp.Diagnostics.Append(diags...)
+		if !delegated {
+			if !resp.Diagnostics.HasError() {
+				tflog.Info(ctx, "Deferring record until the domain is delegated to Porkbun")
+				resp.Deferred = &resource.Deferred{Reason: resource.DeferredReasonAbsentPrereq}
+			}
+			return
+		}
+	}
+
+	if data.Domain.IsUnknown() || data.Name.IsUnknown() || data.Type.IsUnknown() || data.Content.IsUnknown() {
+		return
+	}
+
+	if req.State.Raw.IsNull() && isDKIMRecord(data.Type.ValueString(), data.Name.ValueString()) {
+		resp.Diagnostics.Append(r.dkimSelectorDiagnostics(ctx, data)...)
+	}
+
+	if req.State.Raw.IsNull() && r.provider.runtime().suggestImports && !isDKIMRecord(data.Type.ValueString(), data.Name.ValueString()) {
+		resp.Diagnostics.Append(r.adoptionDiagnostics(ctx, data)...)
+	}
+
+	if !r.provider.runtime().checkLiveDNS {
+		return
+	}
+	recordType := strings.ToUpper(data.Type.ValueString())
+	if recordType == "TXT" && isSPF(data.Content.ValueString()) {
+		resp.Diagnostics.Append(r.spfLookupDiagnostics(ctx, data.Content.ValueString())...)
+	}
+	if !req.State.Raw.IsNull() || !slices.Contains(lookupRecordTypes, recordType) {
+		return
+	}
+
+	domain := data.Domain.ValueString()
+	name := recordFQDN(data.Name.ValueString(), domain)
+	expected := expectedLookupValue(recordType, data.Content.ValueString())
+	for _, warning := range liveDnsWarnings(ctx, r.lookup, defaultPropagationResolvers[0], domain, name, recordType, expected) {
+		resp.Diagnostics.AddWarning("Record may not resolve as planned", warning)
+	}
+}
+
+// planContentSource plans content from content_from_file or content_base64. Without either and without
+// content in the config, content stays null like it always was for content_wo.
+func planContentSource(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var config porkbunDnsRecordResourceData
+	diags.Append(req.Config.Get(ctx, &config)...)
+	if diags.HasError() || !config.Content.IsNull() {
+		return diags
+	}
+
+	var content string
+	var err error
+	source := path.Root("content_from_file")
+	switch {
+	case config.ContentFromFile.IsUnknown() || config.ContentBase64.IsUnknown():
+		return diags
+	case !config.ContentFromFile.IsNull():
+		content, err = contentFromFile(config.ContentFromFile.ValueString())
+	case !config.ContentBase64.IsNull():
+		source = path.Root("content_base64")
+		content, err = contentFromBase64(config.ContentBase64.ValueString())
+	default:
+		diags.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), types.StringNull())...)
+		return diags
+	}
+	if err != nil {
+		diags.AddAttributeError(source, "Invalid content", err.Error())
+		return diags
+	}
+
+	if isDKIMRecord(config.Type.ValueString(), config.Name.ValueString()) {
+		if err := validateDKIM(content); err != nil {
+			diags.AddAttributeError(
+				source,
+				"Invalid DKIM key",
+				fmt.Sprintf("Records at a _domainkey selector publish a DKIM key like v=DKIM1; k=rsa; p=<base64 key>: %s", err),
+			)
+			return diags
+		}
+	}
+	diags.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), types.StringValue(content))...)
+	return diags
+}
+
+// delegationGate checks wait_for_delegation for a new record, delegated is false when the record has to
+// wait for the domain to be delegated to Porkbun. That fails the plan unless Terraform can defer it.
+func (r *porkbunDnsRecordResource) delegationGate(ctx context.Context, domain string, deferralAllowed bool) (diag.Diagnostics, bool) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+	if rt.client == nil {
+		return diags, true
+	}
+
+	nameservers, err := retry(ctx, rt.retries, func(ctx context.Context) ([]string, error) {
+		return rt.client.GetNameservers(ctx, domain)
+	})
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("wait_for_delegation"),
+			"Could not verify delegation",
+			fmt.Sprintf("Could not retrieve the nameservers of %s: %s", domain, apiErrorDetail(err)),
+		)
+		return diags, false
+	}
+	if delegatedToPorkbun(nameservers) {
+		return diags, true
+	}
+
+	if !deferralAllowed {
+		diags.AddAttributeError(
+			path.Root("wait_for_delegation"),
+			"Domain not delegated to Porkbun",
+			fmt.Sprintf("%s uses the nameservers %s, so the record waits until the registry lists Porkbun's. "+
+				"Apply the nameservers first, or use a Terraform version supporting deferred actions to create the record in a later run.",
+				domain, strings.Join(sortedNameservers(nameservers), ", ")),
+		)
+	}
+	return diags, false
+}
+
+// dkimSelectorDiagnostics fails the plan when the zone already publishes a different key at the selector
+// of a new DKIM record. Records that can't be retrieved are only logged, Create reports those.
+func (r *porkbunDnsRecordResource) dkimSelectorDiagnostics(ctx context.Context, data porkbunDnsRecordResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+	if rt.client == nil {
+		return diags
+	}
+
+	domain := data.Domain.ValueString()
+	records, err := rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	})
+	if err != nil {
+		tflog.Debug(ctx, "Unable to check the DKIM selector", map[string]any{"error": err.Error()})
+		return diags
+	}
+
+	fqdn := recordFQDN(data.Name.ValueString(), domain)
+	if existing, ok := dkimSelectorConflict(sortedRecords(records), fqdn, data.Content.ValueString()); ok {
+		diags.AddAttributeError(
+			path.Root("name"),
+			"DKIM selector already in use",
+			fmt.Sprintf("%s already publishes a different key in record %s. Mail signed with that key would stop verifying "+
+				"once both are served, pick another selector or import record %s to manage it.", fqdn, existing.ID, existing.ID),
+		)
+	}
+	return diags
+}
+
+// adoptionDiagnostics warns when a new record has the name and type of live records with other content that
+// Terraform doesn't manage, see adoptableRecords. Records that can't be retrieved are only logged, Create reports those.
+func (r *porkbunDnsRecordResource) adoptionDiagnostics(ctx context.Context, data porkbunDnsRecordResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	domain := data.Domain.ValueString()
+	records, err := rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	})
+	if err != nil {
+		tflog.Debug(ctx, "Unable to check for records to import", map[string]any{"error": err.Error()})
+		return diags
+	}
+
+	fqdn := recordFQDN(data.Name.ValueString(), domain)
+	for _, record := range adoptableRecords(sortedRecords(records), fqdn, data.Type.ValueString(), data.Content.ValueString(), rt.managedNotesMarker) {
+		diags.AddWarning(
+			"Record may already exist",
+			fmt.Sprintf("%s already has %s record %s with content %q, which stays next to the new record. If this resource is meant "+
+				"to take it over, import it with the ID %s/%s instead, the next apply then changes its content.",
+				fqdn, strings.ToUpper(record.Type), record.ID, record.Content, domain, record.ID),
+		)
+	}
+	return diags
+}
+
+// spfLookupDiagnostics warns when evaluating an SPF policy takes more DNS lookups than receivers allow,
+// which makes SPF fail for all mail and only shows once it bounces. Failed lookups are only logged.
+func (r *porkbunDnsRecordResource) spfLookupDiagnostics(ctx context.Context, content string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	terms, err := countSPFLookups(ctx, r.lookup, defaultPropagationResolvers[0], content)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to count SPF lookups", map[string]any{"error": err.Error()})
+		return diags
+	}
+
+	total := 0
+	var costs []string
+	for _, term := range terms {
+		total += term.Lookups
+		costs = append(costs, fmt.Sprintf("%s (%d)", term.Term, term.Lookups))
+	}
+	tflog.Debug(ctx, "Counted SPF lookups", map[string]any{"spf_lookups": total})
+	if total > spfLookupLimit {
+		diags.AddAttributeWarning(
+			path.Root("content"),
+			"SPF policy takes too many DNS lookups",
+			fmt.Sprintf("Evaluating the policy takes %d DNS lookups and receivers give up after %d, so SPF fails for all mail. "+
+				"Lookups by term: %s. Replace includes with the addresses they list or drop the ones no longer needed.",
+				total, spfLookupLimit, strings.Join(costs, ", ")),
+		)
+	}
+	return diags
+}
+
+func (r *porkbunDnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunDnsRecordResourceData
+	rt := r.provider.runtime()
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	// Write-only values are only ever present in the config
+	var contentWo types.String
+	diags = req.Config.GetAttribute(ctx, path.Root("content_wo"), &contentWo)
 	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	record := porkbun.Record{
-		Name:    data.Name.Value,
-		Type:    data.Type.Value,
-		Content: data.Content.Value,
-		TTL:     data.Ttl.Value,   // Minimum is 600 according to porkbun docs
-		Prio:    data.Prio.Value,  // Doesn't work on .com?
-		Notes:   data.Notes.Value, // Not documented
+	ctx = recordLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	if data.Disabled.ValueBool() {
+		tflog.Info(ctx, "Record is disabled, not creating it")
+		data.Id = types.StringNull()
+		data.ContentFields = contentFieldsValue(data)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		resp.Diagnostics.Append(setRecordIdentity(ctx, resp.Identity, data)...)
+		return
+	}
+
+	record := porkbunapi.Record{
+		Name:    data.Name.ValueString(),
+		Type:    data.Type.ValueString(),
+		Content: recordContent(data.Content, contentWo),
+		TTL:     recordTTL(data.Ttl, data.Type.ValueString(), rt.defaultTTLs),
+		Prio:    data.Prio.ValueString(),                      // Doesn't work on .com?
+		Notes:   rt.recordNotes(data.Notes.ValueString(), ""), // Not documented
 	}
 
-	id, err := retry(attempts, sleep, func() (int, error) { return r.provider.client.CreateRecord(ctx, data.Domain.Value, record) })
+	resp.Diagnostics.Append(r.duplicateDiagnostics(ctx, data.Domain.ValueString(), porkbunapi.Record{
+		Name:    recordFQDN(record.Name, data.Domain.ValueString()),
+		Type:    record.Type,
+		Content: record.Content,
+	})...)
+
+	id, err := createRecord(ctx, rt, data.Domain.ValueString(), record)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating DNS Record",
-			fmt.Sprintf("Error: %s", err),
+			apiErrorDetail(err),
 		)
+		return
 	}
 
-	data.Id = types.String{Value: fmt.Sprint(id)}
+	data.Id = types.StringValue(id)
+	ctx = tflog.SetField(ctx, "record_id", id)
+	tflog.Debug(ctx, "Created DNS record")
+	rt.records.put(ctx, data.Domain.ValueString(), writtenRecord(data.Domain.ValueString(), id, record))
+	data.ContentFields = contentFieldsValue(data)
 
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setRecordIdentity(ctx, resp.Identity, data)...)
+	resp.Diagnostics.Append(r.waitForPropagation(ctx, data, record.Content)...)
 }
 
-func (r porkbunDnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+func (r *porkbunDnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
 	var data porkbunDnsRecordResourceData
-	attempts := r.provider.MaxRetries
+	rt := r.provider.runtime()
 
 	diags := req.State.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
@@ -155,92 +709,357 @@ func (r porkbunDnsRecordResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
-	getRecordsResult, err := retry(attempts, sleep, func() ([]porkbun.Record, error) { return r.getRecords(ctx, data.Domain.Value) })
+	ctx = recordLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	// A disabled record isn't at Porkbun, its state is only what the config asked for
+	if data.Disabled.ValueBool() {
+		return
+	}
+
+	// Imports only know the domain and ID, so everything the API returns is copied into state
+	importing, diags := req.Private.GetKey(ctx, importPrivateKey)
+	resp.Diagnostics.Append(diags...)
+
+	records, err := rt.records.get(ctx, data.Domain.ValueString(), func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, data.Domain.ValueString())
+		})
+	})
 
 	if err != nil {
 		resp.Diagnostics.AddError(
 			fmt.Sprintf(
 				`Could not retrieve records for %s.`,
-				data.Domain.Value,
+				data.Domain.ValueString(),
 			),
-			fmt.Sprintf("Error: %s", err.Error()),
+			apiErrorDetail(err),
 		)
+		return
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Found records: %s", getRecordsResult))
-	for _, record := range getRecordsResult {
-		tflog.Info(ctx, fmt.Sprintf("This record is: %s", record.ID))
-		if record.ID == data.Id.Value {
-			data.Content.Value = record.Content
+	tflog.Info(ctx, "Retrieved records", map[string]any{"record_count": len(records)})
+	record, ok := records[data.Id.ValueString()]
+	if !ok {
+		tflog.Warn(ctx, "Record no longer exists, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
-			// This is to handle if there's no subdomain
-			if data.Domain.Value == record.Name {
-				data.Name.Value = ""
-			} else {
-				// The API returns the full record as the name so we'll strip off the domain at the end to keep it consistent
-				data.Name.Value = strings.ReplaceAll(record.Name, fmt.Sprintf(".%s", data.Domain.Value), "")
-			}
+	resp.Diagnostics.Append(r.duplicateDiagnostics(ctx, data.Domain.ValueString(), record)...)
 
-			data.Notes.Value = record.Notes
-			data.Ttl.Value = record.TTL
-			data.Type.Value = record.Type
+	if importing != nil {
+		data.Content = types.StringValue(record.Content)
+		data.Notes = optionalString(unstampNotes(record.Notes, rt.managedNotesMarker))
+		data.Ttl = optionalString(record.TTL)
+		data.Prio = types.StringNull()
+		// The API reports a priority of 0 on every record, it only means something for these
+		if record.Type == "MX" || record.Type == "SRV" {
+			data.Prio = optionalString(record.Prio)
 		}
+		data.ContentWo = types.StringNull()
+		data.ContentWoVersion = types.Int64Null()
+		data.ContentFromFile = types.StringNull()
+		data.ContentBase64 = types.StringNull()
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, importPrivateKey, nil)...)
+	} else {
+		data.Content = refreshContent(record.Type, data.Content, record.Content, data.SemanticCompare.ValueBool())
+		data.Notes = refreshNotes(data.Notes, record.Notes, rt.managedNotesMarker)
+		data.Ttl = refreshTTL(data.Ttl, record.TTL)
 	}
 
+	data.Name = types.StringValue(relativeRecordName(record.Name, data.Domain.ValueString()))
+	data.Type = types.StringValue(record.Type)
+	data.ContentFields = contentFieldsValue(data)
+
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setRecordIdentity(ctx, resp.Identity, data)...)
 }
 
-func (r porkbunDnsRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+func (r *porkbunDnsRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
 	var data porkbunDnsRecordResourceData
-	var recordId string
-	attempts := r.provider.MaxRetries
+	var state porkbunDnsRecordResourceData
+	rt := r.provider.runtime()
 
 	diags := req.Plan.Get(ctx, &data)
-	req.State.GetAttribute(ctx, path.Root("id"), &recordId)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	var contentWo types.String
+	diags = req.Config.GetAttribute(ctx, path.Root("content_wo"), &contentWo)
 	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	record := porkbun.Record{
-		Name:    data.Name.Value,
-		Type:    data.Type.Value,
-		Content: data.Content.Value,
-		TTL:     data.Ttl.Value,   // Minimum is 600 according to porkbun docs
-		Prio:    data.Prio.Value,  // Doesn't work on .com?
-		Notes:   data.Notes.Value, // Not documented
+	recordId := state.Id.ValueString()
+	ctx = recordLogFields(ctx, state)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	// A disabled record isn't at Porkbun, there are no live notes to check
+	if !state.Disabled.ValueBool() {
+		resp.Diagnostics.Append(r.managedRecordDiagnostics(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
-	intId, err := strconv.Atoi(recordId)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error converting ID to a string",
-			fmt.Sprintf("Error: %s", err),
-		)
+	record := porkbunapi.Record{
+		Name:    data.Name.ValueString(),
+		Type:    data.Type.ValueString(),
+		Content: recordContent(data.Content, contentWo),
+		TTL:     recordTTL(data.Ttl, data.Type.ValueString(), rt.defaultTTLs),
+		Prio:    data.Prio.ValueString(),                                           // Doesn't work on .com?
+		Notes:   rt.recordNotes(data.Notes.ValueString(), r.liveNotes(ctx, state)), // Not documented
 	}
 
-	err = retrySingleReturn(attempts, sleep, func() error { return r.provider.client.EditRecord(ctx, data.Domain.Value, intId, record) })
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating the record",
-			fmt.Sprintf("Error %s", err),
-		)
+	switch {
+	case data.Disabled.ValueBool():
+		if !state.Disabled.ValueBool() {
+			resp.Diagnostics.Append(r.deleteRecord(ctx, state)...)
+		}
+	case state.Disabled.ValueBool():
+		resp.Diagnostics.Append(r.duplicateDiagnostics(ctx, data.Domain.ValueString(), porkbunapi.Record{
+			Name:    recordFQDN(record.Name, data.Domain.ValueString()),
+			Type:    record.Type,
+			Content: record.Content,
+		})...)
+		var err error
+		recordId, err = createRecord(ctx, rt, data.Domain.ValueString(), record)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating DNS Record",
+				apiErrorDetail(err),
+			)
+		} else {
+			rt.records.put(ctx, data.Domain.ValueString(), writtenRecord(data.Domain.ValueString(), recordId, record))
+		}
+	case !strings.EqualFold(data.Domain.ValueString(), state.Domain.ValueString()):
+		recordId, diags = r.moveRecord(ctx, state, data.Domain.ValueString(), record)
+		resp.Diagnostics.Append(diags...)
+	default:
+		// Editing keeps the record ID, so a changed type or name is swapped in one step without the name
+		// going unanswered or two conflicting records existing at once
+		err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+			return rt.client.EditRecord(ctx, data.Domain.ValueString(), recordId, record)
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating the record",
+				apiErrorDetail(err),
+			)
+		} else {
+			rt.records.put(ctx, data.Domain.ValueString(), writtenRecord(data.Domain.ValueString(), recordId, record))
+		}
 	}
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	tflog.Debug(ctx, "Updated DNS record")
+
+	data.Id = types.StringValue(recordId)
+	if data.Disabled.ValueBool() {
+		data.Id = types.StringNull()
+	}
+	data.ContentFields = contentFieldsValue(data)
 
 	diags = resp.State.Set(ctx, &data)
-	resp.State.SetAttribute(ctx, path.Root("id"), &recordId)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setRecordIdentity(ctx, resp.Identity, data)...)
+	if !data.Disabled.ValueBool() {
+		resp.Diagnostics.Append(r.waitForPropagation(ctx, data, record.Content)...)
+	}
 }
 
-func (r porkbunDnsRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+// createRecord creates record in domain, retrying like retry. Creating isn't idempotent, so when an attempt
+// fails without a clear answer from Porkbun the zone is retrieved before giving up or trying again: the record
+// may have been created with only the response lost, and it is adopted instead of adding a duplicate.
+// Every resource creating records goes through here.
+func createRecord(ctx context.Context, rt *providerRuntime, domain string, record porkbunapi.Record) (string, error) {
+	// Records of the zone that existed before, usually cached by duplicateDiagnostics already
+	known := map[string]bool{}
+	cached, err := rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	})
+	if err != nil {
+		tflog.Debug(ctx, "Unable to retrieve the records before creating", map[string]any{"error": err.Error()})
+	}
+	for id := range cached {
+		known[id] = true
+	}
+
+	return retry(ctx, rt.retries, func(ctx context.Context) (string, error) {
+		id, err := rt.client.CreateRecord(ctx, domain, record)
+		if err == nil || !ambiguousError(err) {
+			return id, err
+		}
+
+		records, retrieveErr := rt.client.RetrieveRecords(ctx, domain)
+		if retrieveErr != nil {
+			tflog.Debug(ctx, "Unable to check whether the record was created", map[string]any{"error": retrieveErr.Error()})
+			return "", err
+		}
+		if id, ok := createdRecord(records, known, writtenRecord(domain, "", record)); ok {
+			tflog.Warn(ctx, "Creating the record failed but it exists, adopting it", map[string]any{"record_id": id, "error": err.Error()})
+			return id, nil
+		}
+		return "", err
+	})
+}
+
+// createdRecord returns the ID of a record in records serving the same as record that isn't in known
+func createdRecord(records []porkbunapi.Record, known map[string]bool, record porkbunapi.Record) (string, bool) {
+	key := recordValueKey(record)
+	var created []porkbunapi.Record
+	for _, existing := range records {
+		if !known[existing.ID] && recordValueKey(existing) == key {
+			created = append(created, existing)
+		}
+	}
+	if len(created) == 0 {
+		return "", false
+	}
+	// The oldest if several turned up, the others are reported as duplicates by the next refresh
+	return sortedRecords(indexRecords(created))[0].ID, true
+}
+
+// duplicateDiagnostics warns when other records of domain serve the same name, type and content as record.
+// The zone is read through the record cache, so Read gets this for free and Create only pays for it once
+// per domain. Failures to read the zone are only logged, Create reports its own.
+func (r *porkbunDnsRecordResource) duplicateDiagnostics(ctx context.Context, domain string, record porkbunapi.Record) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	_, err := rt.records.load(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	}, false)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to check for duplicate records", map[string]any{"error": err.Error()})
+		return diags
+	}
+
+	if duplicates := rt.records.duplicates(domain, record); len(duplicates) > 0 {
+		diags.AddWarning(
+			"Duplicate records",
+			fmt.Sprintf("%s also serves the same %s content in record %s. Duplicates are usually left by an apply that "+
+				"failed halfway or a change in the Porkbun dashboard, delete the extra records or import them.",
+				normalizeDnsValue(record.Name), strings.ToUpper(record.Type), strings.Join(duplicates, ", ")),
+		)
+	}
+	return diags
+}
+
+// managedRecordDiagnostics fails updates and deletes of records whose live notes don't carry the
+// managed_notes_marker of the provider. The zone is retrieved again instead of read from the record cache,
+// another tool may have taken the record over since the refresh.
+func (r *porkbunDnsRecordResource) managedRecordDiagnostics(ctx context.Context, state porkbunDnsRecordResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+	if rt.managedNotesMarker == "" {
+		return diags
+	}
+
+	records, err := retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+		return rt.client.RetrieveRecords(ctx, state.Domain.ValueString())
+	})
+	if err != nil {
+		diags.AddError(
+			"Could not check the record is managed by Terraform",
+			apiErrorDetail(err),
+		)
+		return diags
+	}
+
+	for _, record := range records {
+		if record.ID != state.Id.ValueString() {
+			continue
+		}
+		if !hasNotesMarker(record.Notes, rt.managedNotesMarker) {
+			diags.AddError(
+				"Record not managed by Terraform",
+				fmt.Sprintf("The notes of record %s of %s don't contain %q, so another tool like external-dns or cert-manager may own it "+
+					"and it wasn't changed. If Terraform should manage it, add %q to its notes in the Porkbun dashboard, "+
+					"otherwise remove it from the configuration with a removed block so it's only forgotten.",
+					state.Id.ValueString(), state.Domain.ValueString(), rt.managedNotesMarker, rt.managedNotesMarker),
+			)
+		}
+		return diags
+	}
+	// Gone already, deleting treats that as done and editing reports it
+	return diags
+}
+
+// liveNotes returns the notes the record in state has at Porkbun, for notes_audit_trail to carry its entries
+// over. The zone is usually cached by the refresh already, failures to retrieve it only start a new trail.
+func (r *porkbunDnsRecordResource) liveNotes(ctx context.Context, state porkbunDnsRecordResourceData) string {
+	rt := r.provider.runtime()
+	if rt.notesAudit == nil {
+		return ""
+	}
+
+	records, err := rt.records.get(ctx, state.Domain.ValueString(), func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, state.Domain.ValueString())
+		})
+	})
+	if err != nil {
+		tflog.Debug(ctx, "Unable to retrieve the audit trail of the record", map[string]any{"error": err.Error()})
+		return ""
+	}
+	return records[state.Id.ValueString()].Notes
+}
+
+// moveRecord replaces the record in state with record in another domain. Records can't be edited into
+// another zone, and as names in different zones can't conflict the new record is created before the old
+// one is deleted so both names keep answering throughout.
+func (r *porkbunDnsRecordResource) moveRecord(ctx context.Context, state porkbunDnsRecordResourceData, domain string, record porkbunapi.Record) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	id, err := createRecord(ctx, rt, domain, record)
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf("Error creating the record in %s", domain),
+			apiErrorDetail(err),
+		)
+		return "", diags
+	}
+	tflog.Debug(ctx, "Created the record in the new domain", map[string]any{"new_domain": domain, "new_record_id": id})
+	rt.records.put(ctx, domain, writtenRecord(domain, id, record))
+
+	err = retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.DeleteRecord(ctx, state.Domain.ValueString(), state.Id.ValueString())
+	})
+	if err == nil || errors.Is(err, porkbunapi.ErrNotFound) {
+		rt.records.remove(ctx, state.Domain.ValueString(), state.Id.ValueString())
+	} else {
+		diags.AddWarning(
+			"Old record left behind",
+			fmt.Sprintf("The record was created in %s as %s, but record %s of %s couldn't be deleted and is still served: %s",
+				domain, id, state.Id.ValueString(), state.Domain.ValueString(), apiErrorDetail(err)),
+		)
+	}
+	return id, diags
+}
+
+func (r *porkbunDnsRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
 	var state porkbunDnsRecordResourceData
-	attempts := r.provider.MaxRetries
 
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -249,86 +1068,362 @@ func (r porkbunDnsRecordResource) Delete(ctx context.Context, req resource.Delet
 		return
 	}
 
-	intId, err := strconv.Atoi(state.Id.Value)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error converting ID to a string",
-			fmt.Sprintf("Error: %s", err),
+	ctx = recordLogFields(ctx, state)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	if state.Disabled.ValueBool() {
+		tflog.Debug(ctx, "Record is disabled, there's nothing to delete")
+		return
+	}
+
+	if state.KeepOnDestroy.ValueBool() {
+		tflog.Info(ctx, "Keeping record on destroy")
+		resp.Diagnostics.AddWarning(
+			"Record kept",
+			fmt.Sprintf("keep_on_destroy is set, so record %s of %s was only removed from state and is still served.", state.Id.ValueString(), state.Domain.ValueString()),
 		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.managedRecordDiagnostics(ctx, state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	err = retrySingleReturn(attempts, sleep, func() error { return r.provider.client.DeleteRecord(ctx, state.Domain.Value, intId) })
+	resp.Diagnostics.Append(r.deleteRecord(ctx, state)...)
+}
+
+// deleteRecord deletes the record in state from Porkbun, for destroying or disabling it
+func (r *porkbunDnsRecordResource) deleteRecord(ctx context.Context, state porkbunDnsRecordResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.DeleteRecord(ctx, state.Domain.ValueString(), state.Id.ValueString())
+	})
+	err = ignoreAlreadyDeleted(ctx, "Record", err, nil)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error deleting record",
-			fmt.Sprintf("Error: %s", err),
+			apiErrorDetail(err),
 		)
+		return diags
+	}
+	tflog.Debug(ctx, "Deleted DNS record")
+	rt.records.remove(ctx, state.Domain.ValueString(), state.Id.ValueString())
+	return diags
+}
+
+func (r *porkbunDnsRecordResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"domain": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The base domain of the record",
+			},
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "The Porkbun ID of the Record",
+			},
+		},
+	}
+}
+
+func (r *porkbunDnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, importPrivateKey, []byte(`true`))...)
+
+	if req.ID != "" {
+		importID, err := parseRecordImportID(req.ID)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid import ID", err.Error())
+			return
+		}
+
+		// Read needs the domain and ID, whichever of them the import ID leaves out is looked up
+		domain, id, diags := r.resolveImportID(ctx, importID)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+		return
 	}
 
+	// Import blocks using identity (Terraform 1.12+) and list results carry the domain as well
+	var identity porkbunDnsRecordIdentityData
+	resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-}
 
-func (r porkbunDnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.Id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), identity.Domain)...)
 }
 
-// Originally from https://stackoverflow.com/questions/67069723/keep-retrying-a-function-in-golang
-func retry[T any](attempts int, sleep int, f func() (T, error)) (result T, err error) {
-	for i := 0; i < attempts; i++ {
-		if i > 0 {
-			time.Sleep(time.Duration(sleep) * time.Second)
-			sleep *= 2
+// resolveImportID returns the domain and ID of the record importID names, searching the domains of the
+// account for a bare record ID and the zone for a record given by type and name
+func (r *porkbunDnsRecordResource) resolveImportID(ctx context.Context, importID recordImportID) (string, string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	if importID.Domain != "" && importID.ID != "" {
+		return importID.Domain, importID.ID, diags
+	}
+
+	if importID.ID != "" {
+		domains, err := retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Domain, error) {
+			return rt.client.ListDomains(ctx)
+		})
+		if err != nil {
+			diags.AddError(
+				"Could not list domains.",
+				apiErrorDetail(err),
+			)
+			return "", "", diags
 		}
-		result, err = f()
-		if err == nil {
-			return result, nil
+		names := make([]string, 0, len(domains))
+		for _, domain := range domains {
+			names = append(names, strings.ToLower(domain.Domain))
 		}
-		status, ok := err.(porkbun.Status)
-		if ok {
-			if status.Status != "SUCCESS" {
-				return result, fmt.Errorf("cannot be retried: %s", status.Error())
+		slices.Sort(names)
+
+		zones, errs := retrieveZones(ctx, rt, names, defaultAllRecordsConcurrency)
+		var unsearched []string
+		for i, zone := range zones {
+			if _, ok := zone[importID.ID]; ok {
+				return names[i], importID.ID, diags
 			}
+			if errs[i] != nil {
+				unsearched = append(unsearched, names[i])
+			}
+		}
+
+		detail := fmt.Sprintf("None of the %d domains of the account has record %s.", len(names), importID.ID)
+		if len(unsearched) > 0 {
+			detail += fmt.Sprintf(" The records of %s couldn't be retrieved, import it with the domain like %s/%s.",
+				strings.Join(unsearched, ", "), unsearched[0], importID.ID)
+		}
+		diags.AddError("Record not found", detail)
+		return "", "", diags
+	}
+
+	records, err := rt.records.get(ctx, importID.Domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, importID.Domain)
+		})
+	})
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf(`Could not retrieve records for %s.`, importID.Domain),
+			apiErrorDetail(err),
+		)
+		return "", "", diags
+	}
+
+	var matches []string
+	for _, record := range sortedRecords(records) {
+		if strings.EqualFold(record.Type, importID.Type) && relativeRecordName(strings.ToLower(record.Name), importID.Domain) == importID.Name {
+			matches = append(matches, record.ID)
 		}
-		servererr, ok := err.(*porkbun.ServerError)
-		if ok {
-			if !isRetryable(servererr.StatusCode) {
-				return result, fmt.Errorf("received error is not retryable: %s", servererr.Error())
+	}
+	name := recordFQDN(importID.Name, importID.Domain)
+	switch len(matches) {
+	case 0:
+		diags.AddError(
+			"Record not found",
+			fmt.Sprintf("%s has no %s record.", name, importID.Type),
+		)
+	case 1:
+		return importID.Domain, matches[0], diags
+	default:
+		diags.AddError(
+			"Ambiguous import ID",
+			fmt.Sprintf("%s has %d %s records: %s. Import one of them by ID, like %s/%s.",
+				name, len(matches), importID.Type, strings.Join(matches, ", "), importID.Domain, matches[0]),
+		)
+	}
+	return "", "", diags
+}
+
+// importPrivateKey marks state written by ImportState until the following Read has filled it in
+const importPrivateKey = "importing"
+
+type porkbunDnsRecordIdentityData struct {
+	Domain types.String `tfsdk:"domain"`
+	Id     types.String `tfsdk:"id"`
+}
+
+// setRecordIdentity is a no-op when Terraform is too old to support resource identity
+func setRecordIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, data porkbunDnsRecordResourceData) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	return identity.Set(ctx, porkbunDnsRecordIdentityData{
+		Domain: data.Domain,
+		Id:     data.Id,
+	})
+}
+
+// relativeRecordName strips the domain from the full name the API returns, the apex becomes ""
+func relativeRecordName(name string, domain string) string {
+	// This is to handle if there's no subdomain
+	if name == domain {
+		return ""
+	}
+	// The API returns the full record as the name so we'll strip off the domain at the end to keep it consistent
+	return strings.ReplaceAll(name, fmt.Sprintf(".%s", domain), "")
+}
+
+// recordContent picks whichever of content and content_wo is set, ValidateConfig ensures it's at most one
+func recordContent(content types.String, contentWo types.String) string {
+	if !contentWo.IsNull() {
+		return contentWo.ValueString()
+	}
+	return content.ValueString()
+}
+
+// refreshString updates an optional attribute from the API unless it was left unset,
+// otherwise values the API fills in (like the default ttl) would show up as drift.
+func refreshString(current types.String, live string) types.String {
+	if current.IsNull() {
+		return current
+	}
+	return types.StringValue(live)
+}
+
+// refreshContent updates the configured content from the API like refreshString, except for content that
+// only differs in form: the order and quoting of HTTPS and SVCB parameters, and with semantic set, the
+// split of TXT values into character-strings
+func refreshContent(recordType string, current types.String, live string, semantic bool) types.String {
+	if current.IsNull() {
+		return current
+	}
+	switch {
+	case isSvcbType(recordType):
+		configured, err := parseSvcbContent(current.ValueString())
+		if err == nil {
+			if served, err := parseSvcbContent(live); err == nil && configured.String() == served.String() {
+				return current
 			}
 		}
+	case semantic && strings.EqualFold(recordType, "TXT"):
+		if txtEquivalent(current.ValueString(), live) {
+			return current
+		}
 	}
-	return result, fmt.Errorf("after %d attempts, last error: %s", attempts, err)
+	return refreshString(current, live)
 }
 
-func retrySingleReturn(attempts int, sleep int, f func() error) (err error) {
-	for i := 0; i < attempts; i++ {
+// Originally from https://stackoverflow.com/questions/67069723/keep-retrying-a-function-in-golang
+// f is handed a context carrying the span covering all attempts, so each HTTP request is traced beneath it.
+func retry[T any](ctx context.Context, policy retryPolicy, f func(ctx context.Context) (T, error)) (result T, err error) {
+	ctx, span := startRetrySpan(ctx)
+	tried := 0
+	defer func() { endRetrySpan(span, tried, err) }()
+
+	wait := policy.wait
+	for i := 0; i < policy.attempts; i++ {
 		if i > 0 {
-			time.Sleep(time.Duration(sleep) * time.Second)
-			sleep *= 2
+			tflog.Debug(ctx, "Retrying Porkbun API call", map[string]any{"attempt": i + 1, "wait_seconds": wait.Seconds(), "error": err.Error()})
+			if stats := retryStatsFrom(ctx); stats != nil {
+				stats.add(wait, err)
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return result, ctx.Err()
+			case <-timer.C:
+			}
+			wait *= 2
 		}
-		err = f()
+		tried++
+		result, err = f(ctx)
 		if err == nil {
-			return nil
+			return result, nil
 		}
-		err, ok := err.(*porkbun.ServerError)
-		if ok {
-			if !isRetryable(err.StatusCode) {
-				return fmt.Errorf("received error is not retryable: %s", err)
-			}
+		// The deadline or cancellation of ctx ended the call, another attempt would end the same way
+		if ctx.Err() != nil {
+			return result, err
+		}
+		if !retryableError(err) {
+			return result, fmt.Errorf("received error is not retryable: %w", err)
 		}
 	}
-	return fmt.Errorf("after %d attempts, last error: %s", attempts, err)
+	return result, fmt.Errorf("after %d attempts, last error: %w", policy.attempts, err)
 }
 
-func (r porkbunDnsRecordResource) getRecords(ctx context.Context, domain string) ([]porkbun.Record, error) {
-	records, err := r.provider.client.RetrieveRecords(ctx, domain)
-	if err != nil {
-		return []porkbun.Record{}, err
+func retrySingleReturn(ctx context.Context, policy retryPolicy, f func(ctx context.Context) error) error {
+	_, err := retry(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	})
+	return err
+}
+
+// recordLogFields adds the fields identifying a record to every log line written with the returned context
+func recordLogFields(ctx context.Context, data porkbunDnsRecordResourceData) context.Context {
+	ctx = tflog.SetField(ctx, "domain", data.Domain.ValueString())
+	ctx = tflog.SetField(ctx, "type", data.Type.ValueString())
+	if !data.Id.IsNull() && !data.Id.IsUnknown() {
+		ctx = tflog.SetField(ctx, "record_id", data.Id.ValueString())
+	}
+	return ctx
+}
+
+// ambiguousError reports whether err leaves open if Porkbun carried the call out: no answer arrived, or the
+// answer was a server error other than a rate limit, which may have been produced after the change was made
+func ambiguousError(err error) bool {
+	var apiErr *porkbunapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 && !errors.Is(err, porkbunapi.ErrRateLimited)
+	}
+	return true
+}
+
+// ignoreAlreadyDeleted drops the error of deleting something that doesn't exist anymore: someone deleted it
+// already, which is what was asked for. Porkbun rejects unknown IDs of some kinds like invalid requests, for
+// those exists looks for it before the error is returned.
+func ignoreAlreadyDeleted(ctx context.Context, what string, err error, exists func(ctx context.Context) (bool, error)) error {
+	if err == nil {
+		return nil
+	}
+
+	gone := errors.Is(err, porkbunapi.ErrNotFound)
+	if !gone && exists != nil {
+		found, listErr := exists(ctx)
+		if listErr != nil {
+			tflog.Debug(ctx, "Unable to check whether it was already deleted", map[string]any{"error": listErr.Error()})
+		}
+		gone = listErr == nil && !found
+	}
+	if !gone {
+		return err
 	}
-	return records, nil
+	tflog.Warn(ctx, what+" was already deleted", map[string]any{"error": err.Error()})
+	return nil
 }
 
-func isRetryable(status int) bool {
-	return slices.Contains(retryableCodes, status)
+// retryableError reports whether err is worth another attempt. API errors are only retried when Porkbun
+// rate limited the call, failures that never got an answer such as timeouts always are. A cancelled call, a
+// response that was too large to read or didn't decode would come back the same way. Client timeouts match
+// context.DeadlineExceeded too, retry tells those apart from the deadline of the call by its context.
+func retryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, errResponseTooLarge) {
+		return false
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return false
+	}
+	var apiErr *porkbunapi.Error
+	if errors.As(err, &apiErr) {
+		return errors.Is(err, porkbunapi.ErrRateLimited)
+	}
+	return true
 }
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithUpgradeState = &porkbunDnsRecordResource{}
+var _ resource.ResourceWithMoveState = &porkbunDnsRecordResource{}
+
+// Version 1 added content_wo and content_wo_version, version 0 is the layout the original
+// cullenmcdermott/porkbun provider writes
+const dnsRecordSchemaVersion = 1
+
+func (r *porkbunDnsRecordResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// No PriorSchema, the raw JSON is read instead so states written by any release of the
+		// original provider are accepted, whatever attributes they carry
+		0: {StateUpgrader: upgradeDnsRecordStateV0},
+	}
+}
+
+func upgradeDnsRecordStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	if req.RawState == nil || req.RawState.JSON == nil {
+		resp.Diagnostics.AddError("Unable to upgrade porkbun_dns_record state", "The prior state is empty")
+		return
+	}
+
+	data, diags := legacyDnsRecordData(req.RawState.JSON)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *porkbunDnsRecordResource) MoveState(ctx context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{StateMover: moveDnsRecordState},
+	}
+}
+
+// moveDnsRecordState takes over the record resources of other Porkbun providers, for moved blocks like
+// from = otherporkbun_dns_record.www. Their states are translated with the same aliases as upgrades.
+func moveDnsRecordState(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	// Leaving the response untouched tells the framework this mover doesn't handle the source
+	if !strings.HasSuffix(req.SourceProviderAddress, "/porkbun") || !strings.HasSuffix(req.SourceTypeName, "_record") {
+		return
+	}
+	if req.SourceRawState == nil || req.SourceRawState.JSON == nil {
+		resp.Diagnostics.AddError("Unable to move state to porkbun_dns_record", "The source state is empty")
+		return
+	}
+
+	data, diags := legacyDnsRecordData(req.SourceRawState.JSON)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.TargetState.Set(ctx, &data)...)
+}
+
+// Other Porkbun providers name some attributes differently, the first one present in a prior state is used
+var legacyDnsRecordAttributes = []struct {
+	names  []string
+	target func(*porkbunDnsRecordResourceData) *types.String
+}{
+	{[]string{"id", "record_id"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Id }},
+	{[]string{"domain", "zone"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Domain }},
+	{[]string{"name", "subdomain"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Name }},
+	{[]string{"type"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Type }},
+	{[]string{"content"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Content }},
+	{[]string{"ttl"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Ttl }},
+	{[]string{"prio", "priority"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Prio }},
+	{[]string{"notes"}, func(d *porkbunDnsRecordResourceData) *types.String { return &d.Notes }},
+}
+
+// legacyDnsRecordData reads a record stored with the original provider's attributes: id, domain,
+// name, type, content, ttl, prio and notes, or their aliases. Some releases stored ttl and prio as
+// numbers and some IDs combine the domain and record ID as domain/id.
+func legacyDnsRecordData(raw []byte) (porkbunDnsRecordResourceData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	data := porkbunDnsRecordResourceData{
+		ContentFields:    types.MapNull(types.StringType),
+		ContentWo:        types.StringNull(),
+		ContentWoVersion: types.Int64Null(),
+		ContentFromFile:  types.StringNull(),
+		ContentBase64:    types.StringNull(),
+		SemanticCompare:  types.BoolNull(),
+		KeepOnDestroy:    types.BoolNull(),
+		Disabled:         types.BoolNull(),
+
+		AllowCriticalChanges: types.BoolNull(),
+
+		WaitForPropagation:   types.BoolNull(),
+		PropagationTimeout:   types.StringNull(),
+		PropagationInterval:  types.StringNull(),
+		PropagationResolvers: types.ListNull(types.StringType),
+
+		WaitForDelegation: types.BoolNull(),
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &attributes); err != nil {
+		diags.AddError("Unable to read prior porkbun_dns_record state", fmt.Sprintf("Error: %s", err))
+		return data, diags
+	}
+
+	for _, field := range legacyDnsRecordAttributes {
+		target := field.target(&data)
+		*target = types.StringNull()
+		for _, name := range field.names {
+			value, err := legacyString(attributes[name])
+			if err != nil {
+				diags.AddError(
+					"Unable to read prior porkbun_dns_record state",
+					fmt.Sprintf("Attribute %s: %s", name, err),
+				)
+				break
+			}
+			if !value.IsNull() {
+				*target = value
+				break
+			}
+		}
+	}
+	if diags.HasError() {
+		return data, diags
+	}
+
+	if domain, id, ok := strings.Cut(data.Id.ValueString(), "/"); ok {
+		if data.Domain.IsNull() {
+			data.Domain = types.StringValue(domain)
+		}
+		data.Id = types.StringValue(id)
+	}
+
+	if data.Id.IsNull() || data.Domain.IsNull() {
+		diags.AddError(
+			"Unable to read prior porkbun_dns_record state",
+			"The prior state has no id or domain, the record has to be imported again",
+		)
+		return data, diags
+	}
+
+	// Read always stores the name relative to the domain and the type as the API reports it,
+	// doing the same here keeps the first plan after the upgrade free of noise
+	if !data.Name.IsNull() {
+		data.Name = types.StringValue(relativeRecordName(data.Name.ValueString(), data.Domain.ValueString()))
+	}
+	if !data.Type.IsNull() {
+		data.Type = types.StringValue(strings.ToUpper(data.Type.ValueString()))
+	}
+	data.ContentFields = contentFieldsValue(data)
+
+	return data, diags
+}
+
+// legacyString accepts a JSON string, number or null
+func legacyString(raw json.RawMessage) (types.String, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return types.StringNull(), nil
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return types.StringValue(str), nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(raw, &num); err != nil {
+		return types.StringNull(), fmt.Errorf("expected a string or number, got %s", raw)
+	}
+	return types.StringValue(num.String()), nil
+}
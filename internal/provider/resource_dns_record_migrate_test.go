@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+func upgradeRecordState(t *testing.T, priorState string) (porkbunDnsRecordResourceData, fwresource.UpgradeStateResponse) {
+	ctx := context.Background()
+	res := newUnitRecordResource(newFakeClient())
+
+	var schemaResp fwresource.SchemaResponse
+	res.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	req := fwresource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(priorState)}}
+	resp := fwresource.UpgradeStateResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+	res.UpgradeState(ctx)[0].StateUpgrader(ctx, req, &resp)
+
+	var data porkbunDnsRecordResourceData
+	if !resp.Diagnostics.HasError() {
+		require.False(t, resp.State.Get(ctx, &data).HasError())
+	}
+	return data, resp
+}
+
+func Test_UpgradeRecordStateFromOriginalProvider(t *testing.T) {
+	r := require.New(t)
+
+	data, resp := upgradeRecordState(t, `{
+		"id": "106926659",
+		"domain": "foobar.dev",
+		"name": "www.foobar.dev",
+		"type": "cname",
+		"content": "foobar.dev",
+		"ttl": 600,
+		"prio": null,
+		"notes": "managed"
+	}`)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	r.Equal(types.StringValue("106926659"), data.Id)
+	r.Equal(types.StringValue("www"), data.Name)
+	r.Equal(types.StringValue("CNAME"), data.Type)
+	r.Equal(types.StringValue("600"), data.Ttl)
+	r.Equal(types.StringValue("managed"), data.Notes)
+	r.True(data.Prio.IsNull())
+	r.True(data.ContentWo.IsNull())
+	r.True(data.ContentWoVersion.IsNull())
+}
+
+func Test_UpgradeRecordStateIgnoresUnknownAttributes(t *testing.T) {
+	r := require.New(t)
+
+	data, resp := upgradeRecordState(t, `{"id": "1", "domain": "foobar.dev", "name": "", "type": "A", "content": "0.0.0.1", "content_wo_version": null, "proxied": false}`)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Equal(types.StringValue(""), data.Name)
+	r.True(data.Ttl.IsNull())
+}
+
+func Test_UpgradeRecordStateRejectsIncompleteState(t *testing.T) {
+	_, resp := upgradeRecordState(t, `{"name": "www", "type": "A"}`)
+	require.True(t, resp.Diagnostics.HasError())
+
+	_, resp = upgradeRecordState(t, `{"id": "1", "domain": "foobar.dev", "ttl": true}`)
+	require.True(t, resp.Diagnostics.HasError())
+}
+
+func moveRecordState(t *testing.T, providerAddress string, typeName string, sourceState string) (porkbunDnsRecordResourceData, fwresource.MoveStateResponse) {
+	ctx := context.Background()
+	res := newUnitRecordResource(newFakeClient())
+
+	var schemaResp fwresource.SchemaResponse
+	res.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	req := fwresource.MoveStateRequest{
+		SourceProviderAddress: providerAddress,
+		SourceTypeName:        typeName,
+		SourceRawState:        &tfprotov6.RawState{JSON: []byte(sourceState)},
+	}
+	resp := fwresource.MoveStateResponse{TargetState: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+	res.MoveState(ctx)[0].StateMover(ctx, req, &resp)
+
+	var data porkbunDnsRecordResourceData
+	if !resp.Diagnostics.HasError() && !resp.TargetState.Raw.IsNull() {
+		require.False(t, resp.TargetState.Get(ctx, &data).HasError())
+	}
+	return data, resp
+}
+
+func Test_MoveRecordStateTranslatesAliases(t *testing.T) {
+	r := require.New(t)
+
+	data, resp := moveRecordState(t, "registry.terraform.io/someone/porkbun", "porkbun_dns_record", `{
+		"id": "foobar.dev/106926659",
+		"subdomain": "mail",
+		"type": "MX",
+		"content": "mx.foobar.dev",
+		"ttl": "3600",
+		"priority": 10
+	}`)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	r.Equal(types.StringValue("106926659"), data.Id)
+	r.Equal(types.StringValue("foobar.dev"), data.Domain)
+	r.Equal(types.StringValue("mail"), data.Name)
+	r.Equal(types.StringValue("10"), data.Prio)
+	r.True(data.Notes.IsNull())
+}
+
+func Test_MoveRecordStateIgnoresOtherProviders(t *testing.T) {
+	r := require.New(t)
+
+	for _, source := range [][2]string{
+		{"registry.terraform.io/hashicorp/aws", "aws_route53_record"},
+		{"registry.terraform.io/someone/porkbun", "porkbun_domain"},
+	} {
+		_, resp := moveRecordState(t, source[0], source[1], `{"id": "1", "domain": "foobar.dev"}`)
+		r.False(resp.Diagnostics.HasError())
+		r.True(resp.TargetState.Raw.IsNull(), source[1])
+	}
+}
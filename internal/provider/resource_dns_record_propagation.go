@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+// Porkbun's nameservers usually pick a change up within a minute, public resolvers take up to the TTL
+const defaultPropagationTimeout = 5 * time.Minute
+
+func validatePropagationConfig(data porkbunDnsRecordResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if data.WaitForPropagation.ValueBool() && !data.Type.IsUnknown() && !slices.Contains(lookupRecordTypes, strings.ToUpper(data.Type.ValueString())) {
+		diags.AddAttributeError(
+			path.Root("wait_for_propagation"),
+			"Unsupported record type",
+			fmt.Sprintf("Propagation of %s records can't be checked, expected one of %s", data.Type.ValueString(), strings.Join(lookupRecordTypes, ", ")),
+		)
+	}
+
+	if !data.PropagationTimeout.IsNull() && !data.PropagationTimeout.IsUnknown() {
+		if _, err := parsePositiveDuration(data.PropagationTimeout.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("propagation_timeout"),
+				"Invalid propagation timeout",
+				fmt.Sprintf("Expected a number of seconds or a positive duration like 10m: %s", err),
+			)
+		}
+	}
+
+	if !data.PropagationInterval.IsNull() && !data.PropagationInterval.IsUnknown() {
+		if _, err := parsePositiveDuration(data.PropagationInterval.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("propagation_interval"),
+				"Invalid propagation interval",
+				fmt.Sprintf("Expected a number of seconds or a positive duration like 30s: %s", err),
+			)
+		}
+	}
+
+	for i, resolver := range data.PropagationResolvers.Elements() {
+		resolver, ok := resolver.(types.String)
+		if !ok || resolver.IsUnknown() {
+			continue
+		}
+		if _, err := resolverAddress(resolver.ValueString()); err != nil {
+			diags.AddAttributeError(
+				path.Root("propagation_resolvers").AtListIndex(i),
+				"Invalid resolver",
+				err.Error(),
+			)
+		}
+	}
+
+	return diags
+}
+
+// waitForPropagation blocks until the record with content is served by the configured resolvers, when
+// wait_for_propagation is set. The record has been written already, so a timeout is reported as an error
+// to stop dependent resources but the state is kept.
+func (r *porkbunDnsRecordResource) waitForPropagation(ctx context.Context, data porkbunDnsRecordResourceData, content string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !data.WaitForPropagation.ValueBool() {
+		return diags
+	}
+
+	timeout := defaultPropagationTimeout
+	if !data.PropagationTimeout.IsNull() {
+		// ValidateConfig has checked the format
+		timeout, _ = parsePositiveDuration(data.PropagationTimeout.ValueString())
+	}
+	interval := defaultPropagationInterval
+	if !data.PropagationInterval.IsNull() {
+		interval, _ = parsePositiveDuration(data.PropagationInterval.ValueString())
+	}
+
+	resolvers := porkbunNameservers
+	if !data.PropagationResolvers.IsNull() {
+		resolvers = nil
+		diags.Append(data.PropagationResolvers.ElementsAs(ctx, &resolvers, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	name := recordFQDN(data.Name.ValueString(), data.Domain.ValueString())
+	recordType := strings.ToUpper(data.Type.ValueString())
+	tflog.Debug(ctx, "Waiting for DNS record to propagate", map[string]any{"name": name, "interval": interval.String(), "timeout": timeout.String()})
+
+	err := waitForPropagation(ctx, r.lookup, resolvers, name, recordType, expectedLookupValue(recordType, content), interval, timeout)
+	if err != nil {
+		diags.AddError(
+			"DNS record didn't propagate",
+			fmt.Sprintf("The record was saved but isn't served everywhere yet: %s", err),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+// staleLookup answers with stale until it has been asked staleFor times, then with fresh
+type staleLookup struct {
+	mu       sync.Mutex
+	staleFor int
+	stale    string
+	fresh    string
+	queries  []string
+}
+
+func (l *staleLookup) lookup(ctx context.Context, resolver string, name string, recordType string) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.queries = append(l.queries, resolver+" "+name+" "+recordType)
+	if len(l.queries) <= l.staleFor {
+		return []string{l.stale}, nil
+	}
+	return []string{l.fresh}, nil
+}
+
+func updateWithPropagation(t *testing.T, lookup *staleLookup, timeout string) fwresource.UpdateResponse {
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	res := newUnitRecordResource(client)
+	res.lookup = lookup.lookup
+
+	prior := recordState(t, res, unitRecordData(id, "0.0.0.1"))
+	planned := unitRecordData(id, "0.0.0.2")
+	planned.WaitForPropagation = types.BoolValue(true)
+	planned.PropagationTimeout = types.StringValue(timeout)
+	planned.PropagationInterval = types.StringValue("1ms")
+	planned.PropagationResolvers = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("192.0.2.53")})
+	plan := recordState(t, res, planned)
+
+	resp := fwresource.UpdateResponse{State: prior}
+	res.Update(ctx, fwresource.UpdateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  prior,
+	}, &resp)
+	return resp
+}
+
+func Test_UpdateRecordWaitsForPropagation(t *testing.T) {
+	r := require.New(t)
+	lookup := &staleLookup{staleFor: 2, stale: "0.0.0.1", fresh: "0.0.0.2"}
+
+	resp := updateWithPropagation(t, lookup, "1m")
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Equal([]string{
+		"192.0.2.53 test.foobar.dev A",
+		"192.0.2.53 test.foobar.dev A",
+		"192.0.2.53 test.foobar.dev A",
+	}, lookup.queries)
+}
+
+func Test_UpdateRecordPropagationTimeout(t *testing.T) {
+	r := require.New(t)
+	lookup := &staleLookup{staleFor: 1 << 30, stale: "0.0.0.1"}
+
+	resp := updateWithPropagation(t, lookup, "20ms")
+	r.True(resp.Diagnostics.HasError())
+	r.Contains(resp.Diagnostics.Errors()[0].Detail(), "didn't show \"0.0.0.2\" on 192.0.2.53")
+
+	// The edit went through, so the state has to reflect it
+	var data porkbunDnsRecordResourceData
+	r.False(resp.State.Get(context.Background(), &data).HasError())
+	r.Equal("0.0.0.2", data.Content.ValueString())
+}
+
+func Test_ValidatePropagationConfig(t *testing.T) {
+	r := require.New(t)
+
+	data := unitRecordData("1", "0.0.0.1")
+	data.WaitForPropagation = types.BoolValue(true)
+	r.False(validatePropagationConfig(data).HasError())
+
+	data.Type = types.StringValue("CAA")
+	r.True(validatePropagationConfig(data).HasError())
+
+	data = unitRecordData("1", "0.0.0.1")
+	data.PropagationTimeout = types.StringValue("five minutes")
+	r.True(validatePropagationConfig(data).HasError())
+	data.PropagationTimeout = types.StringValue("600")
+	r.False(validatePropagationConfig(data).HasError())
+
+	data = unitRecordData("1", "0.0.0.1")
+	data.PropagationInterval = types.StringValue("30s")
+	r.False(validatePropagationConfig(data).HasError())
+	data.PropagationInterval = types.StringValue("0s")
+	r.True(validatePropagationConfig(data).HasError())
+
+	data = unitRecordData("1", "0.0.0.1")
+	data.PropagationResolvers = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("ns1.porkbun.com")})
+	r.True(validatePropagationConfig(data).HasError())
+}
+
+func Test_ModifyPlanWarnsAboutLiveDns(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+	lookup := &staleLookup{staleFor: 1 << 30, stale: "192.0.2.1"}
+	res.lookup = lookup.lookup
+
+	planned := unitRecordData("", "0.0.0.1")
+	planned.Id = types.StringUnknown()
+	plan := recordState(t, res, planned)
+	req := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+	}
+
+	// Disabled by default
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Empty(lookup.queries)
+
+	rt := *res.provider.runtime()
+	rt.checkLiveDNS = true
+	res.provider.setRuntime(&rt)
+	resp = fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.False(resp.Diagnostics.HasError())
+	r.Len(resp.Diagnostics.Warnings(), 2)
+	r.Equal([]string{"1.1.1.1 foobar.dev NS", "1.1.1.1 test.foobar.dev A"}, lookup.queries)
+
+	// Existing records aren't checked
+	lookup.queries = nil
+	req.State = recordState(t, res, unitRecordData("1", "0.0.0.1"))
+	resp = fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Empty(lookup.queries)
+}
+
+func Test_ModifyPlanWarnsAboutSPFLookups(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+	res.lookup = spfZone{
+		"_spf.mail.dev": {"v=spf1 a mx a:one.mail.dev a:two.mail.dev a:three.mail.dev ~all"},
+	}.lookup
+	rt := *res.provider.runtime()
+	rt.checkLiveDNS = true
+	res.provider.setRuntime(&rt)
+
+	modifyPlan := func(content string) fwresource.ModifyPlanResponse {
+		planned := unitRecordData("1", content)
+		planned.Name = types.StringValue("")
+		planned.Type = types.StringValue("TXT")
+		plan := recordState(t, res, planned)
+		req := fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+			// Updates are checked too
+			State: recordState(t, res, unitRecordData("1", "0.0.0.1")),
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+		res.ModifyPlan(ctx, req, &resp)
+		return resp
+	}
+
+	resp := modifyPlan("v=spf1 include:_spf.mail.dev mx -all")
+	r.Empty(resp.Diagnostics)
+
+	resp = modifyPlan("v=spf1 include:_spf.mail.dev include:_spf.mail.dev mx -all")
+	r.False(resp.Diagnostics.HasError())
+	r.Len(resp.Diagnostics.Warnings(), 1)
+	r.Equal("SPF policy takes too many DNS lookups", resp.Diagnostics.Warnings()[0].Summary())
+	r.Contains(resp.Diagnostics.Warnings()[0].Detail(), "takes 13 DNS lookups")
+	r.Contains(resp.Diagnostics.Warnings()[0].Detail(), "include:_spf.mail.dev (6)")
+
+	// Unresolvable includes only get logged
+	resp = modifyPlan("v=spf1 include:down.foobar.dev include:_spf.mail.dev include:_spf.mail.dev -all")
+	r.Empty(resp.Diagnostics)
+}
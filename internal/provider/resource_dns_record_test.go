@@ -1,56 +1,39 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
-	"net/http"
-	"net/http/httptest"
-	"net/url"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"testing"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/nrdcg/porkbun"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	"github.com/stretchr/testify/require"
 )
 
-type porkbunTestProvider struct {
-	client     *porkbun.Client
-	configured bool
-	version    string
-}
-
-type Client struct {
-	secretApiKey string
-	apiKey       string
-	BaseURL      *url.URL
-	HTTPClient   *http.Client
-}
-
-type createResponse struct {
-	Status string
-	ID     int
-}
-
-type deleteResponse struct {
-	Status string
-}
-
-type retrieveResponse struct {
-	Status  string
-	Records []porkbun.Record
-}
-
 func Test_CreateRecordSuccess(t *testing.T) {
-	testUrl, expectRequest, _ := MockPorkbun(t)
-	os.Setenv("PORKBUN_API_KEY", "pk1_foobarbaz")
-	os.Setenv("PORKBUN_SECRET_KEY", "sk1_foobarbaz")
-	os.Setenv("PORKBUN_BASE_URL", testUrl)
-	os.Setenv("PORKBUN_MAX_RETRIES", "1")
-
 	tests := []struct {
 		name         string
 		testResource string
-		record       porkbun.Record
+		record       porkbuntest.Record
 	}{
 		{
 			name: "withSubdomain",
@@ -62,13 +45,12 @@ func Test_CreateRecordSuccess(t *testing.T) {
             type = "A"
           }
 				`,
-			record: porkbun.Record{
-				Name:    "test",
-				ID:      "987",
+			record: porkbuntest.Record{
+				Name:    "test.foobar.dev",
 				Type:    "A",
 				Content: "0.0.0.1",
 				TTL:     "600",
-				Prio:    "",
+				Prio:    "0",
 				Notes:   "",
 			},
 		},
@@ -82,161 +64,1307 @@ func Test_CreateRecordSuccess(t *testing.T) {
             type = "A"
           }
 				`,
-			record: porkbun.Record{
+			record: porkbuntest.Record{
 				Name:    "foobar.dev",
-				ID:      "987",
 				Type:    "A",
 				Content: "0.0.0.1",
 				TTL:     "600",
-				Prio:    "",
+				Prio:    "0",
 				Notes:   "",
 			},
 		},
 	}
 	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
 
-		t.Log("Running test ", test.name)
-		r := require.New(t)
-		expectRequest(func(w http.ResponseWriter, req *http.Request) {
-			r.Equal(http.MethodPost, req.Method)
-			r.Equal("/dns/create/foobar.dev", req.URL.Path)
-			r.NoError(json.NewEncoder(w).Encode(&createResponse{
-				Status: "SUCCESS",
-				ID:     987,
-			}))
+			// Run the terraform twice to ensure its idempotent
+			resource.UnitTest(t, resource.TestCase{
+				IsUnitTest: true,
+				Steps: []resource.TestStep{
+					{
+						ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+						Config:                   test.testResource,
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", test.record.Content),
+							checkServerRecord(server, "porkbun_dns_record.test", test.record),
+						),
+					},
+					{
+						ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+						Config:                   test.testResource,
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", test.record.Content),
+						),
+					},
+				},
+				CheckDestroy: func(*terraform.State) error {
+					if records := server.Records("foobar.dev"); len(records) != 0 {
+						return fmt.Errorf("expected all records to be deleted, found %v", records)
+					}
+					return nil
+				},
+			})
 		})
+	}
+}
 
-		for range []int{1, 2, 3} {
-			expectRequest(func(w http.ResponseWriter, req *http.Request) {
-				r.Equal(http.MethodPost, req.Method)
-				r.Equal("/dns/retrieve/foobar.dev", req.URL.Path)
-				r.NoError(json.NewEncoder(w).Encode(&retrieveResponse{
-					Status:  "SUCCESS",
-					Records: []porkbun.Record{test.record},
-				}))
-			})
+func Test_UpdateRecord(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
 
+	config := func(content string, ttl string) string {
+		return fmt.Sprintf(`
+          resource "porkbun_dns_record" "test" {
+            name    = "test"
+            domain  = "foobar.dev"
+            content = %q
+            type    = "A"
+            ttl     = %q
+          }
+				`, content, ttl)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: config("0.0.0.1", "600"),
+				Check: checkServerRecord(server, "porkbun_dns_record.test", porkbuntest.Record{
+					Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1", TTL: "600", Prio: "0",
+				}),
+			},
+			{
+				Config: config("0.0.0.2", "3600"),
+				Check: checkServerRecord(server, "porkbun_dns_record.test", porkbuntest.Record{
+					Name: "test.foobar.dev", Type: "A", Content: "0.0.0.2", TTL: "3600", Prio: "0",
+				}),
+			},
+			{
+				ResourceName:      "porkbun_dns_record.test",
+				ImportState:       true,
+				ImportStateIdFunc: importStateIdFunc("porkbun_dns_record.test"),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "porkbun_dns_record.test",
+				ImportState:       true,
+				ImportStateId:     "foobar.dev:a:test",
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "porkbun_dns_record.test",
+				ImportState:   true,
+				ImportStateId: "foobar.dev:test",
+				ExpectError:   regexp.MustCompile(`Invalid import ID`),
+			},
+		},
+	})
+}
+
+func Test_CreateRecordContentWo(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+	config := func(token string, version int) string {
+		return fmt.Sprintf(`
+          resource "porkbun_dns_record" "test" {
+            name = "_verify"
+            domain = "foobar.dev"
+            type = "TXT"
+            content_wo = %q
+            content_wo_version = %d
+          }
+		`, token, version)
+	}
+	serverContent := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			records := server.Records("foobar.dev")
+			require.Len(t, records, 1)
+			require.Equal(t, expected, records[0].Content)
+			return nil
 		}
+	}
+	// The token reaches Porkbun but neither the plan nor the state
+	contentIsNull := []plancheck.PlanCheck{
+		plancheck.ExpectKnownValue("porkbun_dns_record.test", tfjsonpath.New("content"), knownvalue.Null()),
+		plancheck.ExpectKnownValue("porkbun_dns_record.test", tfjsonpath.New("content_wo"), knownvalue.Null()),
+	}
 
-		expectRequest(func(w http.ResponseWriter, req *http.Request) {
-			r.Equal(http.MethodPost, req.Method)
-			r.Equal("/dns/delete/foobar.dev/987", req.URL.Path)
-			r.NoError(json.NewEncoder(w).Encode(&deleteResponse{
-				Status: "SUCCESS",
-			}))
-		})
-		// Run the terraform twice to ensure its idempotent
-		resource.UnitTest(t, resource.TestCase{
-			IsUnitTest: true,
-			Steps: []resource.TestStep{
-				{
-					ProtoV6ProviderFactories: protoV6ProviderFactories(testUrl),
-					Config:                   test.testResource,
-					Check: resource.ComposeTestCheckFunc(
-						resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", test.record.Content),
-					),
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: config("token-1", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: contentIsNull,
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("porkbun_dns_record.test", tfjsonpath.New("content"), knownvalue.Null()),
+					statecheck.ExpectKnownValue("porkbun_dns_record.test", tfjsonpath.New("content_wo"), knownvalue.Null()),
 				},
-				{
-					ProtoV6ProviderFactories: protoV6ProviderFactories(testUrl),
-					Config:                   test.testResource,
-					Check: resource.ComposeTestCheckFunc(
-						resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", test.record.Content),
-					),
+				Check: serverContent("token-1"),
+			},
+			{
+				// Write-only values aren't compared, a new token alone changes nothing
+				Config: config("token-2", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+				},
+				Check: serverContent("token-1"),
+			},
+			{
+				Config: config("token-2", 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: append([]plancheck.PlanCheck{plancheck.ExpectResourceAction("porkbun_dns_record.test", plancheck.ResourceActionUpdate)}, contentIsNull...),
 				},
+				Check: serverContent("token-2"),
 			},
+		},
+	})
+}
+
+func Test_CreateRecordFailure(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		IsUnitTest:               true,
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_dns_record" "test" {
+            name    = "test"
+            domain  = "notmine.dev"
+            content = "0.0.0.1"
+            type    = "A"
+          }
+				`,
+				ExpectError: regexp.MustCompile(`Error creating DNS Record`),
+			},
+		},
+	})
+}
+
+// checkServerRecord verifies the record stored by the fake API matches expected, the ID is taken from state
+func checkServerRecord(server *porkbuntest.Server, resourceName string, expected porkbuntest.Record) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found in state", resourceName)
+		}
+
+		for _, record := range server.Records(rs.Primary.Attributes["domain"]) {
+			if record.ID == rs.Primary.ID {
+				expected.ID = record.ID
+				if record != expected {
+					return fmt.Errorf("expected record %+v, got %+v", expected, record)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("record %s not found on the server", rs.Primary.ID)
+	}
+}
+
+func importStateIdFunc(resourceName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("resource %s not found in state", resourceName)
+		}
+		return fmt.Sprintf("%s/%s", rs.Primary.Attributes["domain"], rs.Primary.ID), nil
+	}
+}
+
+func Test_ResolveImportID(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev", "other.dev")
+	apex := client.addRecord("other.dev", porkbunapi.Record{Name: "other.dev", Type: "MX", Content: "mx1.other.dev", Prio: "10"})
+	client.addRecord("foobar.dev", porkbunapi.Record{Name: "foobar.dev", Type: "NS", Content: "curi.ns.porkbun.com"})
+	client.addRecord("foobar.dev", porkbunapi.Record{Name: "foobar.dev", Type: "NS", Content: "maceio.ns.porkbun.com"})
+	res := newUnitRecordResource(client)
+
+	domain, id, diags := res.resolveImportID(ctx, recordImportID{ID: apex})
+	r.False(diags.HasError(), "%v", diags)
+	r.Equal("other.dev", domain)
+	r.Equal(apex, id)
+
+	domain, id, diags = res.resolveImportID(ctx, recordImportID{Domain: "other.dev", Type: "MX", Name: ""})
+	r.False(diags.HasError(), "%v", diags)
+	r.Equal("other.dev", domain)
+	r.Equal(apex, id)
+
+	_, _, diags = res.resolveImportID(ctx, recordImportID{ID: "404"})
+	r.Equal("Record not found", diags.Errors()[0].Summary())
+
+	_, _, diags = res.resolveImportID(ctx, recordImportID{Domain: "foobar.dev", Type: "A", Name: "www"})
+	r.Equal("Record not found", diags.Errors()[0].Summary())
+	r.Contains(diags.Errors()[0].Detail(), "www.foobar.dev has no A record")
+
+	_, _, diags = res.resolveImportID(ctx, recordImportID{Domain: "foobar.dev", Type: "NS", Name: ""})
+	r.Equal("Ambiguous import ID", diags.Errors()[0].Summary())
+	r.Contains(diags.Errors()[0].Detail(), "foobar.dev has 2 NS records")
+}
+
+func Test_RelativeRecordName(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("", relativeRecordName("foobar.dev", "foobar.dev"))
+	r.Equal("www", relativeRecordName("www.foobar.dev", "foobar.dev"))
+	r.Equal("a.b", relativeRecordName("a.b.foobar.dev", "foobar.dev"))
+}
+
+// newUnitRecordResource returns a resource wired to client the way Configure would do it
+func newUnitRecordResource(client porkbunClient) *porkbunDnsRecordResource {
+	p := &porkbunProvider{}
+	p.setRuntime(&providerRuntime{
+		client:  client,
+		retries: retryPolicy{attempts: 1},
+		records: newRecordCache(),
+	})
+	return &porkbunDnsRecordResource{provider: p}
+}
+
+// recordState builds state, plan or config contents for the porkbun_dns_record schema
+func recordState(t testing.TB, r *porkbunDnsRecordResource, data porkbunDnsRecordResourceData) tfsdk.State {
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := state.Set(ctx, &data)
+	require.False(t, diags.HasError(), "%v", diags)
+	return state
+}
+
+func unitRecordData(id string, content string) porkbunDnsRecordResourceData {
+	return porkbunDnsRecordResourceData{
+		Id:               types.StringValue(id),
+		Name:             types.StringValue("test"),
+		Type:             types.StringValue("A"),
+		Content:          types.StringValue(content),
+		Ttl:              types.StringNull(),
+		Notes:            types.StringNull(),
+		Prio:             types.StringNull(),
+		Domain:           types.StringValue("foobar.dev"),
+		ContentFields:    types.MapNull(types.StringType),
+		ContentWo:        types.StringNull(),
+		ContentWoVersion: types.Int64Null(),
+		ContentFromFile:  types.StringNull(),
+		ContentBase64:    types.StringNull(),
+		SemanticCompare:  types.BoolNull(),
+		KeepOnDestroy:    types.BoolNull(),
+		Disabled:         types.BoolNull(),
+
+		AllowCriticalChanges: types.BoolNull(),
+
+		WaitForPropagation:   types.BoolNull(),
+		PropagationTimeout:   types.StringNull(),
+		PropagationInterval:  types.StringNull(),
+		PropagationResolvers: types.ListNull(types.StringType),
+
+		WaitForDelegation: types.BoolNull(),
+	}
+}
+
+func Test_ReadRecordPicksUpDrift(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "www.foobar.dev", Type: "CNAME", Content: "elsewhere.dev", TTL: "3600"})
+	res := newUnitRecordResource(client)
+
+	state := recordState(t, res, unitRecordData(id, "0.0.0.1"))
+	resp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	var data porkbunDnsRecordResourceData
+	r.False(resp.State.Get(ctx, &data).HasError())
+	r.Equal("www", data.Name.ValueString())
+	r.Equal("CNAME", data.Type.ValueString())
+	r.Equal("elsewhere.dev", data.Content.ValueString())
+	// Attributes left out of the configuration stay null rather than being filled from the API
+	r.True(data.Ttl.IsNull())
+}
+
+// Benchmark_ReadRecord refreshes a single record of a large zone, the zone is only retrieved by the first Read
+func Benchmark_ReadRecord(b *testing.B) {
+	ctx := context.Background()
+	for _, size := range []int{1000, 10000} {
+		client := newFakeClient("foobar.dev")
+		var id string
+		for _, record := range syntheticZone(size) {
+			id = client.addRecord("foobar.dev", record)
+		}
+		res := newUnitRecordResource(client)
+		data := unitRecordData(id, "0.0.0.1")
+		data.Name = types.StringValue(fmt.Sprintf("host%d", size-1))
+		state := recordState(b, res, data)
+
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				resp := fwresource.ReadResponse{State: state}
+				res.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+				if resp.Diagnostics.HasError() {
+					b.Fatal(resp.Diagnostics)
+				}
+			}
 		})
 	}
 }
 
-//func Test_CreateRecordFailure(t *testing.T) {
-//	testUrl, expectRequest, _ := MockPorkbun(t)
-//	os.Setenv("PORKBUN_API_KEY", "pk1_foobarbaz")
-//	os.Setenv("PORKBUN_SECRET_KEY", "sk1_foobarbaz")
-//	os.Setenv("PORKBUN_BASE_URL", testUrl)
-//
-//	tests := []struct {
-//		name         string
-//		testResource string
-//		record       porkbun.Record
-//	}{
-//		{
-//			name: "withSubdomain",
-//			testResource: `
-//          resource "porkbun_dns_record" "test" {
-//            name =   "test"
-//            domain = "foobar.dev"
-//            content = "0.0.0.1"
-//            type = "A"
-//          }
-//				`,
-//			record: porkbun.Record{},
-//		},
-//	}
-//	for _, test := range tests {
-//		r := require.New(t)
-//		expectRequest(func(w http.ResponseWriter, req *http.Request) {
-//			r.Equal(http.MethodPost, req.Method)
-//			r.Equal("/dns/delete/foobar.dev/0", req.URL.Path)
-//			r.Error(json.NewEncoder(w).Encode(&createResponse{
-//				Status: "FAILURE",
-//			}))
-//		})
-//		expectRequest(func(w http.ResponseWriter, req *http.Request) {
-//			r.Equal(http.MethodPost, req.Method)
-//			r.Equal("/dns/delete/foobar.dev/0", req.URL.Path)
-//			r.Error(json.NewEncoder(w).Encode(&createResponse{
-//				Status: "FAILURE",
-//			}))
-//		})
-//		// Run the terraform twice to ensure its idempotent
-//		resource.UnitTest(t, resource.TestCase{
-//			IsUnitTest: true,
-//			Steps: []resource.TestStep{
-//				{
-//					ProtoV6ProviderFactories: protoV6ProviderFactories(testUrl),
-//					Config:                   test.testResource,
-//					Check: resource.ComposeTestCheckFunc(
-//						resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", "0.0.0.1"),
-//					),
-//				},
-//			},
-//		})
-//	}
-//
-//}
-
-func MockPorkbun(t *testing.T) (string, func(http.HandlerFunc), func()) {
-	var (
-		receivedCalls   int
-		expectedCalls   []http.HandlerFunc
-		addExpectedCall = func(h http.HandlerFunc) {
-			expectedCalls = append(expectedCalls, h)
+func Test_DuplicateRecordWarnings(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	duplicate := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.2"})
+	res := newUnitRecordResource(client)
+
+	state := recordState(t, res, unitRecordData(id, "0.0.0.1"))
+	readResp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &readResp)
+	r.False(readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+	r.Len(readResp.Diagnostics.Warnings(), 1)
+	r.Contains(readResp.Diagnostics.Warnings()[0].Detail(), "test.foobar.dev also serves the same A content in record "+duplicate)
+
+	planned := unitRecordData("", "0.0.0.2")
+	planned.Id = types.StringUnknown()
+	plan := recordState(t, res, planned)
+	createResp := fwresource.CreateResponse{State: plan}
+	res.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+	}, &createResp)
+	r.False(createResp.Diagnostics.HasError(), "%v", createResp.Diagnostics)
+	r.Len(createResp.Diagnostics.Warnings(), 1)
+
+	// Records serving something else don't count
+	planned = unitRecordData("", "0.0.0.3")
+	planned.Id = types.StringUnknown()
+	plan = recordState(t, res, planned)
+	createResp = fwresource.CreateResponse{State: plan}
+	res.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+	}, &createResp)
+	r.Empty(createResp.Diagnostics)
+
+	// Records created in the same run are patched into the cache, so a second one is noticed without retrieving the zone again
+	createResp = fwresource.CreateResponse{State: plan}
+	res.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+	}, &createResp)
+	r.False(createResp.Diagnostics.HasError(), "%v", createResp.Diagnostics)
+	r.Len(createResp.Diagnostics.Warnings(), 1)
+	r.Equal(int64(1), res.provider.runtime().records.misses.Load())
+}
+
+// lostResponseClient carries creates out but fails them as if the response never arrived
+type lostResponseClient struct {
+	*fakeClient
+	create func(ctx context.Context, domain string, record porkbunapi.Record) (string, error)
+}
+
+func (c lostResponseClient) CreateRecord(ctx context.Context, domain string, record porkbunapi.Record) (string, error) {
+	return c.create(ctx, domain, record)
+}
+
+func Test_CreateRecordAdoptsAfterLostResponse(t *testing.T) {
+	ctx := context.Background()
+	lost := errors.New(`Post "https://api.porkbun.com/api/json/v3/dns/create/foobar.dev": context deadline exceeded`)
+
+	create := func(t *testing.T, client porkbunClient) (fwresource.CreateResponse, porkbunDnsRecordResourceData) {
+		res := newUnitRecordResource(client)
+		planned := unitRecordData("", "0.0.0.1")
+		planned.Id = types.StringUnknown()
+		plan := recordState(t, res, planned)
+		resp := fwresource.CreateResponse{State: plan}
+		res.Create(ctx, fwresource.CreateRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		}, &resp)
+
+		var data porkbunDnsRecordResourceData
+		if !resp.Diagnostics.HasError() {
+			require.False(t, resp.State.Get(ctx, &data).HasError())
 		}
-		r = require.New(t)
-	)
+		return resp, data
+	}
 
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		r.Less(
-			receivedCalls,
-			len(expectedCalls),
-			"did not expect another request",
-		)
+	t.Run("created", func(t *testing.T) {
+		r := require.New(t)
+		fake := newFakeClient("foobar.dev")
+		// An identical record from before isn't mistaken for the one just created
+		existing := fake.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
 
-		expectedCalls[receivedCalls](w, req)
+		resp, data := create(t, lostResponseClient{fake, func(ctx context.Context, domain string, record porkbunapi.Record) (string, error) {
+			_, err := fake.CreateRecord(ctx, domain, record)
+			r.NoError(err)
+			return "", lost
+		}})
+		r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+		r.Len(fake.domains["foobar.dev"], 2)
+		r.NotEqual(existing, data.Id.ValueString())
+		r.Equal(fake.domains["foobar.dev"][1].ID, data.Id.ValueString())
+	})
 
-		receivedCalls++
-	}))
+	t.Run("not created", func(t *testing.T) {
+		r := require.New(t)
+		fake := newFakeClient("foobar.dev")
+
+		resp, _ := create(t, lostResponseClient{fake, func(ctx context.Context, domain string, record porkbunapi.Record) (string, error) {
+			return "", lost
+		}})
+		r.True(resp.Diagnostics.HasError())
+		r.Empty(fake.domains["foobar.dev"])
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		r := require.New(t)
+		fake := newFakeClient("foobar.dev")
+
+		// Porkbun answered, so whatever is in the zone isn't the result of this request
+		resp, _ := create(t, lostResponseClient{fake, func(ctx context.Context, domain string, record porkbunapi.Record) (string, error) {
+			_, err := fake.CreateRecord(ctx, domain, record)
+			r.NoError(err)
+			return "", apiError("Could not add DNS record.")
+		}})
+		r.True(resp.Diagnostics.HasError())
+	})
+}
+
+func Test_MoveRecordAdoptsAfterLostResponse(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	fake := newFakeClient("foobar.dev", "porkbun.dev")
+	id := fake.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	res := newUnitRecordResource(lostResponseClient{fake, func(ctx context.Context, domain string, record porkbunapi.Record) (string, error) {
+		_, err := fake.CreateRecord(ctx, domain, record)
+		r.NoError(err)
+		return "", errors.New("context deadline exceeded")
+	}})
+
+	// The record made it into the new domain, so it is adopted rather than created again
+	movedId, diags := res.moveRecord(ctx, unitRecordData(id, "0.0.0.1"), "porkbun.dev", porkbunapi.Record{Name: "test", Type: "A", Content: "0.0.0.1"})
+	r.False(diags.HasError(), "%v", diags)
+	r.Len(fake.domains["porkbun.dev"], 1)
+	r.Equal(fake.domains["porkbun.dev"][0].ID, movedId)
+	r.Empty(fake.domains["foobar.dev"])
+}
+
+func Test_CreateRecordDefaultTTL(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	res := newUnitRecordResource(client)
+	rt := *res.provider.runtime()
+	rt.defaultTTLs = map[string]string{"A": "3600"}
+	res.provider.setRuntime(&rt)
+
+	planned := unitRecordData("", "0.0.0.1")
+	planned.Id = types.StringUnknown()
+	plan := recordState(t, res, planned)
+	resp := fwresource.CreateResponse{State: plan}
+	res.Create(ctx, fwresource.CreateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+	}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Equal("3600", client.domains["foobar.dev"][0].TTL)
+
+	// The state keeps ttl unset, so the default doesn't show up as drift
+	var data porkbunDnsRecordResourceData
+	r.False(resp.State.Get(ctx, &data).HasError())
+	r.True(data.Ttl.IsNull())
+}
+
+func Test_ReadRecordDeletedOutsideTerraform(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+
+	state := recordState(t, res, unitRecordData("123", "0.0.0.1"))
+	resp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.True(resp.State.Raw.IsNull())
+}
+
+func Test_ReadRecordUnknownDomain(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient())
+
+	state := recordState(t, res, unitRecordData("123", "0.0.0.1"))
+	resp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+	r.True(resp.Diagnostics.HasError())
+}
+
+func Test_UpdateRecordUsesStateId(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	res := newUnitRecordResource(client)
+
+	prior := recordState(t, res, unitRecordData(id, "0.0.0.1"))
+	planned := unitRecordData("", "0.0.0.2")
+	planned.Id = types.StringUnknown()
+	plan := recordState(t, res, planned)
+
+	resp := fwresource.UpdateResponse{State: prior}
+	res.Update(ctx, fwresource.UpdateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  prior,
+	}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	r.Equal([]porkbunapi.Record{{Name: "test", Type: "A", Content: "0.0.0.2"}}, client.edits)
+	records, err := client.RetrieveRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Equal("0.0.0.2", records[0].Content)
+
+	var data porkbunDnsRecordResourceData
+	r.False(resp.State.Get(ctx, &data).HasError())
+	r.Equal(id, data.Id.ValueString())
+}
+
+func Test_UpdateMovesRecordToAnotherDomain(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev", "porkbun.dev")
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	res := newUnitRecordResource(client)
+
+	prior := recordState(t, res, unitRecordData(id, "0.0.0.1"))
+	planned := unitRecordData("", "0.0.0.1")
+	planned.Id = types.StringUnknown()
+	planned.Domain = types.StringValue("porkbun.dev")
+	plan := recordState(t, res, planned)
+
+	resp := fwresource.UpdateResponse{State: prior}
+	res.Update(ctx, fwresource.UpdateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  prior,
+	}, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Empty(client.edits)
+
+	r.Empty(client.domains["foobar.dev"])
+	r.Len(client.domains["porkbun.dev"], 1)
+	var data porkbunDnsRecordResourceData
+	r.False(resp.State.Get(ctx, &data).HasError())
+	r.Equal(client.domains["porkbun.dev"][0].ID, data.Id.ValueString())
+	r.Equal("porkbun.dev", data.Domain.ValueString())
+
+	// Moving into a domain that isn't in the account leaves the old record alone
+	prior = resp.State
+	planned.Domain = types.StringValue("missing.dev")
+	plan = recordState(t, res, planned)
+	resp = fwresource.UpdateResponse{State: prior}
+	res.Update(ctx, fwresource.UpdateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  prior,
+	}, &resp)
+	r.True(resp.Diagnostics.HasError())
+	r.Len(client.domains["porkbun.dev"], 1)
+}
 
-	return ts.URL, addExpectedCall, func() {
-		ts.Close()
-		r.Equal(
-			len(expectedCalls),
-			receivedCalls,
-			"expected one more request",
-		)
+func Test_DeleteRecordAlreadyGone(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+
+	state := recordState(t, res, unitRecordData("123", "0.0.0.1"))
+	resp := fwresource.DeleteResponse{State: state}
+	res.Delete(ctx, fwresource.DeleteRequest{State: state}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+}
+
+func Test_DeleteKeepOnDestroy(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	res := newUnitRecordResource(client)
+
+	data := unitRecordData(id, "0.0.0.1")
+	data.KeepOnDestroy = types.BoolValue(true)
+	state := recordState(t, res, data)
+	resp := fwresource.DeleteResponse{State: state}
+	res.Delete(ctx, fwresource.DeleteRequest{State: state}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Len(resp.Diagnostics.Warnings(), 1)
+	r.Len(client.domains["foobar.dev"], 1)
+
+	state = recordState(t, res, unitRecordData(id, "0.0.0.1"))
+	resp = fwresource.DeleteResponse{State: state}
+	res.Delete(ctx, fwresource.DeleteRequest{State: state}, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Empty(client.domains["foobar.dev"])
+}
+
+func Test_ManagedNotesMarker(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	owned := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1", Notes: "web managed-by:terraform"})
+	foreign := client.addRecord("foobar.dev", porkbunapi.Record{Name: "app.foobar.dev", Type: "A", Content: "0.0.0.3", Notes: "heritage=external-dns"})
+	res := newUnitRecordResource(client)
+	rt := *res.provider.runtime()
+	rt.managedNotesMarker = "managed-by:terraform"
+	res.provider.setRuntime(&rt)
+
+	update := func(id string, content string) fwresource.UpdateResponse {
+		stateData := unitRecordData(id, "0.0.0.1")
+		stateData.Notes = types.StringValue("web")
+		prior := recordState(t, res, stateData)
+		planned := unitRecordData(id, content)
+		planned.Notes = types.StringValue("web")
+		plan := recordState(t, res, planned)
+
+		resp := fwresource.UpdateResponse{State: prior}
+		res.Update(ctx, fwresource.UpdateRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+			State:  prior,
+		}, &resp)
+		return resp
+	}
+
+	// Records carrying the marker are edited with it kept in their notes
+	resp := update(owned, "0.0.0.2")
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Equal("web managed-by:terraform", client.edits[0].Notes)
+
+	// and the marker doesn't show up as drift of the configured notes
+	readData := unitRecordData(owned, "0.0.0.2")
+	readData.Notes = types.StringValue("web")
+	state := recordState(t, res, readData)
+	readResp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &readResp)
+	r.False(readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+	var data porkbunDnsRecordResourceData
+	r.False(readResp.State.Get(ctx, &data).HasError())
+	r.Equal("web", data.Notes.ValueString())
+
+	// Records without it are neither edited nor deleted
+	resp = update(foreign, "0.0.0.4")
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("Record not managed by Terraform", resp.Diagnostics.Errors()[0].Summary())
+	r.Len(client.edits, 1)
+
+	state = recordState(t, res, unitRecordData(foreign, "0.0.0.3"))
+	deleteResp := fwresource.DeleteResponse{State: state}
+	res.Delete(ctx, fwresource.DeleteRequest{State: state}, &deleteResp)
+	r.True(deleteResp.Diagnostics.HasError())
+	r.Len(client.domains["foobar.dev"], 2)
+
+	// A disabled record isn't at Porkbun, changing it while it stays disabled doesn't call the API
+	disabled := unitRecordData("", "0.0.0.5")
+	disabled.Id = types.StringNull()
+	disabled.Domain = types.StringValue("missing.dev")
+	disabled.Disabled = types.BoolValue(true)
+	prior := recordState(t, res, disabled)
+	disabled.Content = types.StringValue("0.0.0.6")
+	plan := recordState(t, res, disabled)
+	resp = fwresource.UpdateResponse{State: prior}
+	res.Update(ctx, fwresource.UpdateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  prior,
+	}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+}
+
+func Test_NotesAuditTrailCarriedOver(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1", Notes: "web [tf 2026-10-01T09:30:00Z ws:staging]"})
+	res := newUnitRecordResource(client)
+	rt := *res.provider.runtime()
+	rt.notesAudit = &notesAuditTrail{workspace: "production", runID: "run-abc123"}
+	res.provider.setRuntime(&rt)
+
+	stateData := unitRecordData(id, "0.0.0.1")
+	stateData.Notes = types.StringValue("web")
+	prior := recordState(t, res, stateData)
+	planned := unitRecordData(id, "0.0.0.2")
+	planned.Notes = types.StringValue("web")
+	plan := recordState(t, res, planned)
+
+	resp := fwresource.UpdateResponse{State: prior}
+	res.Update(ctx, fwresource.UpdateRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  prior,
+	}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Regexp(`^web \[tf 2026-10-01T09:30:00Z ws:staging\] \[tf \S+Z ws:production run:run-abc123\]$`, client.edits[0].Notes)
+
+	// The entries don't show up as drift of the configured notes
+	state := recordState(t, res, planned)
+	readResp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &readResp)
+	r.False(readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+	var data porkbunDnsRecordResourceData
+	r.False(readResp.State.Get(ctx, &data).HasError())
+	r.Equal("web", data.Notes.ValueString())
+}
+
+func Test_RetryableError(t *testing.T) {
+	r := require.New(t)
+
+	r.True(retryableError(&porkbunapi.Error{StatusCode: 503, Message: "Rate limit exceeded"}))
+	r.True(retryableError(errors.New("i/o timeout")))
+	r.False(retryableError(apiError("Invalid API key. (002)")))
+	r.False(retryableError(apiError("Invalid record ID.")))
+	r.False(retryableError(fmt.Errorf("failed to call API: %w", context.Canceled)))
+	r.False(retryableError(fmt.Errorf("failed to unmarshal response: %w", json.Unmarshal([]byte("<html>"), &struct{}{}))))
+	r.False(retryableError(fmt.Errorf("failed to unmarshal response: %w", json.Unmarshal([]byte(`{"id":1}`), &struct{ ID string }{}))))
+
+	// The kind of the last failure survives retry
+	_, err := retry(context.Background(), retryPolicy{attempts: 1}, func(ctx context.Context) (string, error) {
+		return "", invalidDomain()
+	})
+	r.ErrorIs(err, porkbunapi.ErrNotFound)
+}
+
+func Test_RetryPolicyWaitDoubles(t *testing.T) {
+	r := require.New(t)
+	ctx, stats := withRetryStats(context.Background())
+
+	_, err := retry(ctx, retryPolicy{attempts: 3, wait: time.Millisecond}, func(ctx context.Context) (string, error) {
+		return "", &porkbunapi.Error{StatusCode: 503, Message: "Rate limit exceeded"}
+	})
+	r.ErrorContains(err, "after 3 attempts")
+	r.Equal(2, stats.retries)
+	r.Equal(3*time.Millisecond, stats.waited)
+}
+
+func Test_RetryStopsWithContext(t *testing.T) {
+	r := require.New(t)
+	rateLimited := &porkbunapi.Error{StatusCode: 503, Message: "Rate limit exceeded"}
+
+	// The wait before the next attempt ends with the deadline instead of sleeping it out
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	calls := 0
+	start := time.Now()
+	_, err := retry(ctx, retryPolicy{attempts: 3, wait: time.Minute}, func(ctx context.Context) (string, error) {
+		calls++
+		return "", rateLimited
+	})
+	r.ErrorIs(err, context.DeadlineExceeded)
+	r.Less(time.Since(start), 10*time.Second)
+	r.Equal(1, calls)
+
+	// A call ended by the cancellation of its context isn't attempted again
+	ctx, cancel = context.WithCancel(context.Background())
+	calls = 0
+	_, err = retry(ctx, retryPolicy{attempts: 3, wait: time.Millisecond}, func(ctx context.Context) (string, error) {
+		calls++
+		cancel()
+		return "", fmt.Errorf("failed to call API: %w", ctx.Err())
+	})
+	r.ErrorIs(err, context.Canceled)
+	r.Equal(1, calls)
+}
+
+func Test_AmbiguousError(t *testing.T) {
+	r := require.New(t)
+
+	r.True(ambiguousError(errors.New("i/o timeout")))
+	r.True(ambiguousError(&porkbunapi.Error{StatusCode: 502, Message: "Bad gateway"}))
+	r.False(ambiguousError(&porkbunapi.Error{StatusCode: 503, Message: "Rate limit exceeded"}))
+	r.False(ambiguousError(apiError("Invalid domain.")))
+}
+
+func Test_IgnoreAlreadyDeleted(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	invalid := apiError("Invalid forward ID.")
+	exists := func(found bool, err error) func(ctx context.Context) (bool, error) {
+		return func(ctx context.Context) (bool, error) { return found, err }
+	}
+
+	r.NoError(ignoreAlreadyDeleted(ctx, "Record", nil, nil))
+	r.NoError(ignoreAlreadyDeleted(ctx, "Record", porkbunapi.ErrNotFound, nil))
+	r.ErrorIs(ignoreAlreadyDeleted(ctx, "Record", invalid, nil), invalid)
+
+	// Rejected IDs are only ignored once the listing confirms nothing is left
+	r.NoError(ignoreAlreadyDeleted(ctx, "URL forward", invalid, exists(false, nil)))
+	r.ErrorIs(ignoreAlreadyDeleted(ctx, "URL forward", invalid, exists(true, nil)), invalid)
+	r.ErrorIs(ignoreAlreadyDeleted(ctx, "URL forward", invalid, exists(false, errors.New("i/o timeout"))), invalid)
+}
+
+func Test_ModifyPlanDefersUnknownDomain(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+
+	planned := unitRecordData("", "0.0.0.1")
+	planned.Id = types.StringUnknown()
+	planned.Domain = types.StringUnknown()
+	plan := recordState(t, res, planned)
+	req := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+	}
+
+	// Terraform versions without deferred actions get the unknown value planned as before
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Nil(resp.Deferred)
+
+	req.ClientCapabilities.DeferralAllowed = true
+	resp = fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Equal(&fwresource.Deferred{Reason: fwresource.DeferredReasonResourceConfigUnknown}, resp.Deferred)
+
+	// Known domains are planned right away
+	req = fwresource.ModifyPlanRequest{
+		Config:             tfsdk.Config{Schema: plan.Schema, Raw: recordState(t, res, unitRecordData("", "0.0.0.1")).Raw},
+		Plan:               tfsdk.Plan{Schema: plan.Schema, Raw: recordState(t, res, unitRecordData("", "0.0.0.1")).Raw},
+		State:              req.State,
+		ClientCapabilities: fwresource.ModifyPlanClientCapabilities{DeferralAllowed: true},
+	}
+	resp = fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.Nil(resp.Deferred)
+}
+
+func Test_ModifyPlanWaitsForDelegation(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	client.nameservers = map[string][]string{"foobar.dev": {"kim.ns.cloudflare.com", "bob.ns.cloudflare.com"}}
+	res := newUnitRecordResource(client)
+
+	planned := unitRecordData("", "0.0.0.1")
+	planned.Id = types.StringUnknown()
+	planned.WaitForDelegation = types.BoolValue(true)
+	plan := recordState(t, res, planned)
+	req := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+		State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+	}
+
+	// Without deferred actions the plan can't go ahead
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("Domain not delegated to Porkbun", resp.Diagnostics.Errors()[0].Summary())
+	r.Nil(resp.Deferred)
+
+	req.ClientCapabilities.DeferralAllowed = true
+	resp = fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Equal(&fwresource.Deferred{Reason: fwresource.DeferredReasonAbsentPrereq}, resp.Deferred)
+
+	// Once the nameservers are Porkbun's the record is planned
+	r.NoError(client.UpdateNameservers(ctx, "foobar.dev", []string{"curitiba.ns.porkbun.com", "fortaleza.ns.porkbun.com"}))
+	resp = fwresource.ModifyPlanResponse{Plan: req.Plan}
+	res.ModifyPlan(ctx, req, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Nil(resp.Deferred)
+}
+
+func Test_ModifyPlanUnknownIdOnDomainChange(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+	state := recordState(t, res, unitRecordData("1", "0.0.0.1"))
+
+	plannedId := func(domain types.String) types.String {
+		planned := unitRecordData("1", "0.0.0.1")
+		planned.Domain = domain
+		plan := recordState(t, res, planned)
+		req := fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+			State:  state,
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+		res.ModifyPlan(ctx, req, &resp)
+		r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+		var id types.String
+		r.False(resp.Plan.GetAttribute(ctx, path.Root("id"), &id).HasError())
+		return id
+	}
+
+	r.Equal(types.StringValue("1"), plannedId(types.StringValue("FooBar.dev")))
+	// The record is moved, derived domains included
+	r.True(plannedId(types.StringValue("other.dev")).IsUnknown())
+	r.True(plannedId(types.StringUnknown()).IsUnknown())
+}
+
+func Test_DKIMRecordChecks(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	rsaKey, _ := testDKIMKeys(t)
+
+	client := newFakeClient("foobar.dev")
+	client.addRecord("foobar.dev", porkbunapi.Record{Name: "s1._domainkey.foobar.dev", Type: "TXT", Content: "v=DKIM1; p=" + rsaKey})
+	res := newUnitRecordResource(client)
+
+	dkimRecord := func(name string, content string) tfsdk.State {
+		data := unitRecordData("", content)
+		data.Id = types.StringUnknown()
+		data.Name = types.StringValue(name)
+		data.Type = types.StringValue("TXT")
+		return recordState(t, res, data)
 	}
+	modifyPlan := func(plan tfsdk.State) fwresource.ModifyPlanResponse {
+		req := fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+			State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+		res.ModifyPlan(ctx, req, &resp)
+		return resp
+	}
+
+	config := dkimRecord("s2._domainkey", "v=DKIM1; p=not%base64")
+	var validateResp fwresource.ValidateConfigResponse
+	res.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw}}, &validateResp)
+	r.True(validateResp.Diagnostics.HasError())
+	r.Equal("Invalid DKIM key", validateResp.Diagnostics.Errors()[0].Summary())
+
+	resp := modifyPlan(dkimRecord("s1._domainkey", "v=DKIM1; p="))
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("DKIM selector already in use", resp.Diagnostics.Errors()[0].Summary())
+
+	// The key that is already published and other selectors are fine
+	r.Empty(modifyPlan(dkimRecord("s1._domainkey", "v=DKIM1; p="+rsaKey)).Diagnostics)
+	r.Empty(modifyPlan(dkimRecord("s2._domainkey", "v=DKIM1; p=")).Diagnostics)
+}
+
+func Test_RefreshContent(t *testing.T) {
+	r := require.New(t)
+
+	configured := types.StringValue(`1 . alpn="h2,h3" port=443`)
+
+	// Only the order differs, keep what was configured
+	r.Equal(configured, refreshContent("HTTPS", configured, "1 . port=443 alpn=h2,h3", false))
+	// A real change comes through
+	r.Equal(types.StringValue("1 . alpn=h2 port=443"), refreshContent("HTTPS", configured, "1 . alpn=h2 port=443", false))
+	// Other types compare as text
+	r.Equal(types.StringValue("1 . port=443 alpn=h2,h3"), refreshContent("TXT", configured, "1 . port=443 alpn=h2,h3", false))
+	// Unset content stays unset
+	r.True(refreshContent("SVCB", types.StringNull(), "1 . alpn=h2", false).IsNull())
+
+	chunked := types.StringValue(`"v=DKIM1; p=abc" "def"`)
+	r.Equal(types.StringValue("v=DKIM1; p=abcdef"), refreshContent("TXT", chunked, "v=DKIM1; p=abcdef", false))
+	r.Equal(chunked, refreshContent("TXT", chunked, "v=DKIM1; p=abcdef", true))
+	r.Equal(types.StringValue("v=DKIM1; p=abc"), refreshContent("TXT", chunked, "v=DKIM1; p=abc", true))
+	// semantic_compare is rejected for other types, nothing changes for them
+	r.Equal(types.StringValue(`"abc"`), refreshContent("CNAME", types.StringValue("abc"), `"abc"`, true))
 }
 
-type TestHttpMock struct {
-	server *httptest.Server
+func Test_IsCriticalRecord(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isCriticalRecord("MX", ""))
+	r.True(isCriticalRecord("ns", "sub"))
+	r.True(isCriticalRecord("A", ""))
+	r.True(isCriticalRecord("ALIAS", "@"))
+	r.False(isCriticalRecord("A", "www"))
+	r.False(isCriticalRecord("TXT", ""))
+}
+
+func Test_ModifyPlanProtectsCriticalRecords(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+	rt := *res.provider.runtime()
+	rt.protectCriticalRecords = true
+	res.provider.setRuntime(&rt)
+
+	apex := unitRecordData("1", "0.0.0.1")
+	apex.Name = types.StringValue("")
+	modifyPlan := func(prior porkbunDnsRecordResourceData, planned *porkbunDnsRecordResourceData) fwresource.ModifyPlanResponse {
+		state := recordState(t, res, prior)
+		plan := tfsdk.Plan{Schema: state.Schema, Raw: tftypes.NewValue(state.Schema.Type().TerraformType(ctx), nil)}
+		if planned != nil {
+			plan.Raw = recordState(t, res, *planned).Raw
+		}
+		req := fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   plan,
+			State:  state,
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+		res.ModifyPlan(ctx, req, &resp)
+		return resp
+	}
+
+	changed := apex
+	changed.Content = types.StringValue("0.0.0.2")
+	resp := modifyPlan(apex, &changed)
+	r.True(resp.Diagnostics.HasError())
+	r.Contains(resp.Diagnostics.Errors()[0].Detail(), "the A record 1 of foobar.dev won't be changed")
+
+	resp = modifyPlan(apex, nil)
+	r.True(resp.Diagnostics.HasError())
+	r.Contains(resp.Diagnostics.Errors()[0].Detail(), "won't be deleted")
+
+	// Setting the override is allowed by itself and then lets changes and deletes through
+	allowed := apex
+	allowed.AllowCriticalChanges = types.BoolValue(true)
+	r.Empty(modifyPlan(apex, &allowed).Diagnostics)
+	changed.AllowCriticalChanges = types.BoolValue(true)
+	r.Empty(modifyPlan(apex, &changed).Diagnostics)
+	r.Empty(modifyPlan(allowed, nil).Diagnostics)
+
+	// Other records aren't protected
+	www := unitRecordData("2", "0.0.0.1")
+	r.Empty(modifyPlan(www, nil).Diagnostics)
+
+	// Nor is anything without the provider setting
+	rt.protectCriticalRecords = false
+	res.provider.setRuntime(&rt)
+	r.Empty(modifyPlan(apex, nil).Diagnostics)
+}
+
+func Test_ModifyPlanSuggestsImports(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	existing := client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	res := newUnitRecordResource(client)
+
+	modifyPlan := func(content string) fwresource.ModifyPlanResponse {
+		planned := unitRecordData("", content)
+		planned.Id = types.StringUnknown()
+		plan := recordState(t, res, planned)
+		req := fwresource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+			Plan:   tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw},
+			State:  tfsdk.State{Schema: plan.Schema, Raw: tftypes.NewValue(plan.Schema.Type().TerraformType(ctx), nil)},
+		}
+		resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+		res.ModifyPlan(ctx, req, &resp)
+		return resp
+	}
+
+	// Off unless the provider asks for it
+	r.Empty(modifyPlan("0.0.0.2").Diagnostics)
+
+	rt := *res.provider.runtime()
+	rt.suggestImports = true
+	res.provider.setRuntime(&rt)
+
+	resp := modifyPlan("0.0.0.2")
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	r.Len(resp.Diagnostics.Warnings(), 1)
+	r.Equal("Record may already exist", resp.Diagnostics.Warnings()[0].Summary())
+	r.Contains(resp.Diagnostics.Warnings()[0].Detail(), "import it with the ID foobar.dev/"+existing)
+
+	// The same content is a duplicate, not a record to take over
+	r.Empty(modifyPlan("0.0.0.1").Diagnostics)
+}
+
+func Test_CreateRecordContentFromFile(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+	file := filepath.Join(t.TempDir(), "tlsa.txt")
+	config := fmt.Sprintf(`
+          resource "porkbun_dns_record" "test" {
+            name = "_25._tcp.mail"
+            domain = "foobar.dev"
+            type = "TLSA"
+            content_from_file = %q
+          }
+	`, file)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(file, []byte("3 1 1 0123456789abcdef\r\n0123456789abcdef\n"), 0o600))
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", "3 1 1 0123456789abcdef0123456789abcdef"),
+					checkServerRecord(server, "porkbun_dns_record.test", porkbuntest.Record{
+						Name:    "_25._tcp.mail.foobar.dev",
+						Type:    "TLSA",
+						Content: "3 1 1 0123456789abcdef0123456789abcdef",
+						TTL:     "600",
+						Prio:    "0",
+					}),
+				),
+			},
+			{
+				// A new key in the same file updates the record
+				PreConfig: func() {
+					require.NoError(t, os.WriteFile(file, []byte("3 1 1 fedcba9876543210\n"), 0o600))
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", "3 1 1 fedcba9876543210"),
+				),
+			},
+			{
+				Config: `
+          resource "porkbun_dns_record" "test" {
+            name = "_25._tcp.mail"
+            domain = "foobar.dev"
+            type = "TLSA"
+            content_base64 = "MyAxIDEgZmVkY2JhOTg3NjU0MzIxMAo="
+          }
+				`,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func Test_ValidateConfigContentSources(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	res := newUnitRecordResource(newFakeClient("foobar.dev"))
+	data := unitRecordData("1", "0.0.0.1")
+	data.ContentBase64 = types.StringValue("MC4wLjAuMQ==")
+	config := recordState(t, res, data)
+
+	var resp fwresource.ValidateConfigResponse
+	res.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw}}, &resp)
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("Conflicting content", resp.Diagnostics.Errors()[0].Summary())
+
+	data.Content = types.StringNull()
+	data.ContentBase64 = types.StringValue("not base64!")
+	config = recordState(t, res, data)
+
+	resp = fwresource.ValidateConfigResponse{}
+	res.ValidateConfig(ctx, fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: config.Schema, Raw: config.Raw}}, &resp)
+	r.True(resp.Diagnostics.HasError())
+	r.Equal("Invalid content", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func Test_DisabledRecord(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+	config := func(disabled bool) string {
+		return fmt.Sprintf(`
+          resource "porkbun_dns_record" "test" {
+            name = "maintenance"
+            domain = "foobar.dev"
+            content = "0.0.0.1"
+            type = "A"
+            disabled = %t
+          }
+		`, disabled)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: config(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("porkbun_dns_record.test", "id"),
+					func(*terraform.State) error {
+						require.Len(t, server.Records("foobar.dev"), 1)
+						return nil
+					},
+				),
+			},
+			{
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("porkbun_dns_record.test", "id"),
+					resource.TestCheckResourceAttr("porkbun_dns_record.test", "content", "0.0.0.1"),
+					func(*terraform.State) error {
+						require.Empty(t, server.Records("foobar.dev"))
+						return nil
+					},
+				),
+			},
+			{
+				Config: config(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("porkbun_dns_record.test", "id"),
+					checkServerRecord(server, "porkbun_dns_record.test", porkbuntest.Record{
+						Name:    "maintenance.foobar.dev",
+						Type:    "A",
+						Content: "0.0.0.1",
+						TTL:     "600",
+						Prio:    "0",
+					}),
+				),
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if records := server.Records("foobar.dev"); len(records) != 0 {
+				return fmt.Errorf("expected all records to be deleted, found %v", records)
+			}
+			return nil
+		},
+	})
+}
+
+func Test_DeleteDisabledRecord(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := newFakeClient("foobar.dev")
+	client.addRecord("foobar.dev", porkbunapi.Record{Name: "test.foobar.dev", Type: "A", Content: "0.0.0.1"})
+	res := newUnitRecordResource(client)
+
+	// Disabled records have no ID, a record serving the same content isn't theirs to delete
+	data := unitRecordData("", "0.0.0.1")
+	data.Id = types.StringNull()
+	data.Disabled = types.BoolValue(true)
+	state := recordState(t, res, data)
+	resp := fwresource.DeleteResponse{State: state}
+	res.Delete(ctx, fwresource.DeleteRequest{State: state}, &resp)
+	r.Empty(resp.Diagnostics)
+	r.Len(client.domains["foobar.dev"], 1)
 }
@@ -0,0 +1,400 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &porkbunDnssecRecordResource{}
+var _ resource.ResourceWithConfigure = &porkbunDnssecRecordResource{}
+var _ resource.ResourceWithImportState = &porkbunDnssecRecordResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunDnssecRecordResource{}
+
+// The length in hex digits of the digests of the digest types in use: SHA-1, SHA-256 and SHA-384
+var dsDigestLengths = map[string]int{
+	"1": 40,
+	"2": 64,
+	"4": 96,
+}
+
+func NewDnssecRecordResource() resource.Resource {
+	return &porkbunDnssecRecordResource{}
+}
+
+type porkbunDnssecRecordResource struct {
+	provider *porkbunProvider
+}
+
+type porkbunDnssecRecordResourceData struct {
+	Id         types.String `tfsdk:"id"`
+	Domain     types.String `tfsdk:"domain"`
+	KeyTag     types.String `tfsdk:"key_tag"`
+	Algorithm  types.String `tfsdk:"algorithm"`
+	DigestType types.String `tfsdk:"digest_type"`
+	Digest     types.String `tfsdk:"digest"`
+}
+
+func (r *porkbunDnssecRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dnssec_record"
+}
+
+func (r *porkbunDnssecRecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Publishes a DS record for a domain at the registry, for zones signed by the DNS hosting they are delegated to. " +
+			"The API can't edit DS records, so changing any attribute replaces the record. Importing takes `domain/key_tag/algorithm/digest_type`, " +
+			"or `domain/key_tag` when the domain has one record with the key tag",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				MarkdownDescription: "The key tag, algorithm and digest type of the record, like `2371/13/2`. A key rollover publishes " +
+					"records with the same key tag for other algorithms or digest types alongside",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain to publish the record for",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key_tag": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The key tag of the DNSKEY the record refers to, like `2371`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"algorithm": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The number of the algorithm of the DNSKEY, like `13` for ECDSA P-256 with SHA-256",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"digest_type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The number of the digest algorithm, `2` for SHA-256 or `4` for SHA-384",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"digest": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The digest of the DNSKEY in hex, case doesn't matter",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *porkbunDnssecRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunDnssecRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunDnssecRecordResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	numbers := []struct {
+		attribute string
+		value     types.String
+		max       int
+	}{
+		{"key_tag", data.KeyTag, 65535},
+		{"algorithm", data.Algorithm, 255},
+		{"digest_type", data.DigestType, 255},
+	}
+	for _, number := range numbers {
+		if number.value.IsNull() || number.value.IsUnknown() {
+			continue
+		}
+		if n, err := strconv.Atoi(number.value.ValueString()); err != nil || n < 0 || n > number.max {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(number.attribute),
+				"Invalid "+strings.ReplaceAll(number.attribute, "_", " "),
+				fmt.Sprintf("%q isn't a number between 0 and %d", number.value.ValueString(), number.max),
+			)
+		}
+	}
+
+	if !data.Digest.IsNull() && !data.Digest.IsUnknown() {
+		if err := validateDsDigest(data.Digest.ValueString(), data.DigestType); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("digest"), "Invalid digest", err.Error())
+		}
+	}
+}
+
+func (r *porkbunDnssecRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunDnssecRecordResourceData
+	rt := r.provider.runtime()
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = dnssecRecordLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domain := data.Domain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.CreateDNSSECRecord(ctx, domain, dnssecRecord(data))
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating DNSSEC record",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	data.Id = types.StringValue(dnssecRecordID(dnssecRecord(data)))
+	tflog.Debug(ctx, "Created DNSSEC record")
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunDnssecRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunDnssecRecordResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = dnssecRecordLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	records, err := r.records(ctx, data.Domain.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve DNSSEC records for %s.", data.Domain.ValueString()),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	record, ok := findDnssecRecord(records, data.Id.ValueString())
+	if !ok {
+		tflog.Warn(ctx, "DNSSEC record no longer exists, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data = refreshDnssecRecord(data, record)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update only copies the plan into state, every attribute Porkbun stores replaces the record when it changes
+func (r *porkbunDnssecRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data porkbunDnssecRecordResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunDnssecRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var state porkbunDnssecRecordResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = dnssecRecordLogFields(ctx, state)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domain := state.Domain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.DeleteDNSSECRecord(ctx, domain, state.KeyTag.ValueString())
+	})
+	err = ignoreAlreadyDeleted(ctx, "DNSSEC record", err, func(ctx context.Context) (bool, error) {
+		records, err := r.records(ctx, domain)
+		_, ok := findDnssecRecord(records, state.Id.ValueString())
+		return ok, err
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting DNSSEC record",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Deleted DNSSEC record")
+}
+
+// ImportState takes domain/key_tag/algorithm/digest_type, or domain/key_tag which is looked up when the
+// domain has a single record with the key tag
+func (r *porkbunDnssecRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	parts := strings.Split(req.ID, "/")
+	if (len(parts) != 2 && len(parts) != 4) || slices.Contains(parts, "") {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("%q isn't domain/key_tag/algorithm/digest_type or domain/key_tag, like example.com/2371/13/2", req.ID),
+		)
+		return
+	}
+	domain := parts[0]
+	if err := validateImportDomain(domain); err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("invalid import ID %q: %s", req.ID, err))
+		return
+	}
+	domain = strings.ToLower(domain)
+
+	id := strings.Join(parts[1:], "/")
+	if len(parts) == 2 {
+		records, err := r.records(ctx, domain)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Could not retrieve DNSSEC records for %s.", domain),
+				apiErrorDetail(err),
+			)
+			return
+		}
+		var matches []string
+		for _, record := range records {
+			if record.KeyTag == parts[1] {
+				matches = append(matches, dnssecRecordID(record))
+			}
+		}
+		if len(matches) != 1 {
+			resp.Diagnostics.AddError(
+				"DNSSEC record not found",
+				fmt.Sprintf("invalid import ID %q: the domain has %d records with the key tag %s, import one by domain/key_tag/algorithm/digest_type", req.ID, len(matches), parts[1]),
+			)
+			return
+		}
+		id = matches[0]
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key_tag"), strings.Split(id, "/")[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+}
+
+func (r *porkbunDnssecRecordResource) records(ctx context.Context, domain string) ([]porkbunapi.DNSSECRecord, error) {
+	rt := r.provider.runtime()
+	return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.DNSSECRecord, error) {
+		return rt.client.GetDNSSECRecords(ctx, domain)
+	})
+}
+
+func dnssecRecordLogFields(ctx context.Context, data porkbunDnssecRecordResourceData) context.Context {
+	ctx = tflog.SetField(ctx, "domain", data.Domain.ValueString())
+	return tflog.SetField(ctx, "key_tag", data.KeyTag.ValueString())
+}
+
+// dnssecRecord is the record data describes in the form of the API
+func dnssecRecord(data porkbunDnssecRecordResourceData) porkbunapi.DNSSECRecord {
+	return porkbunapi.DNSSECRecord{
+		KeyTag:     data.KeyTag.ValueString(),
+		Alg:        data.Algorithm.ValueString(),
+		DigestType: data.DigestType.ValueString(),
+		Digest:     data.Digest.ValueString(),
+	}
+}
+
+// dnssecRecordID identifies record by its key tag, algorithm and digest type, a key tag alone is shared by
+// the records of a key for several digest types and can collide between keys
+func dnssecRecordID(record porkbunapi.DNSSECRecord) string {
+	return record.KeyTag + "/" + record.Alg + "/" + record.DigestType
+}
+
+func findDnssecRecord(records []porkbunapi.DNSSECRecord, id string) (porkbunapi.DNSSECRecord, bool) {
+	for _, record := range records {
+		if dnssecRecordID(record) == id {
+			return record, true
+		}
+	}
+	return porkbunapi.DNSSECRecord{}, false
+}
+
+// refreshDnssecRecord copies what the registry publishes into data, keeping the digest as configured while
+// it only differs in case
+func refreshDnssecRecord(data porkbunDnssecRecordResourceData, record porkbunapi.DNSSECRecord) porkbunDnssecRecordResourceData {
+	data.KeyTag = types.StringValue(record.KeyTag)
+	data.Algorithm = types.StringValue(record.Alg)
+	data.DigestType = types.StringValue(record.DigestType)
+	if !strings.EqualFold(data.Digest.ValueString(), record.Digest) {
+		data.Digest = types.StringValue(record.Digest)
+	}
+	return data
+}
+
+// validateDsDigest checks that digest is hex, and as long as the digests of digestType when that is known
+func validateDsDigest(digest string, digestType types.String) error {
+	if _, err := hex.DecodeString(digest); err != nil {
+		return fmt.Errorf("%q isn't hex encoded", digest)
+	}
+	if digestType.IsNull() || digestType.IsUnknown() {
+		return nil
+	}
+	if length, ok := dsDigestLengths[digestType.ValueString()]; ok && len(digest) != length {
+		return fmt.Errorf("digests of digest type %s are %d hex digits long, not %d", digestType.ValueString(), length, len(digest))
+	}
+	return nil
+}
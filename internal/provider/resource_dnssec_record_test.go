@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+const testDsDigest = "e2d3c916f6deeac73294e8268fb5885044a833fc5459588f4a9184cfc41a5766"
+
+func Test_DnssecRecord(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+          resource "porkbun_dnssec_record" "test" {
+            domain      = "foobar.dev"
+            key_tag     = "2371"
+            algorithm   = "13"
+            digest_type = "2"
+            digest      = %q
+          }
+				`, testDsDigest),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_dnssec_record.test", "id", "2371/13/2"),
+					func(*terraform.State) error {
+						records := server.DNSSECRecords("foobar.dev")
+						require.Equal(t, []porkbuntest.DNSSECRecord{{KeyTag: "2371", Alg: "13", DigestType: "2", Digest: testDsDigest}}, records)
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "porkbun_dnssec_record.test",
+				ImportState:       true,
+				ImportStateId:     "foobar.dev/2371/13/2",
+				ImportStateVerify: true,
+			},
+			{
+				// The key tag alone finds the only record with it
+				ResourceName:      "porkbun_dnssec_record.test",
+				ImportState:       true,
+				ImportStateId:     "foobar.dev/2371",
+				ImportStateVerify: true,
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if records := server.DNSSECRecords("foobar.dev"); len(records) != 0 {
+				return fmt.Errorf("expected all DNSSEC records to be deleted, found %v", records)
+			}
+			return nil
+		},
+	})
+}
+
+func Test_DnssecRecordInvalidDigest(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_dnssec_record" "test" {
+            domain      = "foobar.dev"
+            key_tag     = "2371"
+            algorithm   = "13"
+            digest_type = "2"
+            digest      = "e2d3c916f6deeac7"
+          }
+				`,
+				ExpectError: regexp.MustCompile(`digests of digest type 2 are 64 hex digits long`),
+			},
+		},
+	})
+}
+
+func Test_RefreshDnssecRecord(t *testing.T) {
+	r := require.New(t)
+
+	data := porkbunDnssecRecordResourceData{
+		Id:         types.StringValue("2371/13/2"),
+		Domain:     types.StringValue("foobar.dev"),
+		KeyTag:     types.StringValue("2371"),
+		Algorithm:  types.StringValue("13"),
+		DigestType: types.StringValue("2"),
+		Digest:     types.StringValue(testDsDigest),
+	}
+
+	// Registries reporting the digest in upper case isn't drift
+	refreshed := refreshDnssecRecord(data, porkbunapi.DNSSECRecord{KeyTag: "2371", Alg: "13", DigestType: "2", Digest: "E2D3C916F6DEEAC73294E8268FB5885044A833FC5459588F4A9184CFC41A5766"})
+	r.Equal(data, refreshed)
+
+	refreshed = refreshDnssecRecord(data, porkbunapi.DNSSECRecord{KeyTag: "2371", Alg: "8", DigestType: "2", Digest: "00"})
+	r.Equal(types.StringValue("8"), refreshed.Algorithm)
+	r.Equal(types.StringValue("00"), refreshed.Digest)
+}
+
+func Test_ValidateDsDigest(t *testing.T) {
+	r := require.New(t)
+
+	r.NoError(validateDsDigest(testDsDigest, types.StringValue("2")))
+	r.NoError(validateDsDigest("ab", types.StringValue("99")))
+	r.NoError(validateDsDigest("ab", types.StringUnknown()))
+	r.ErrorContains(validateDsDigest("xyz", types.StringValue("2")), "isn't hex encoded")
+	r.ErrorContains(validateDsDigest(testDsDigest, types.StringValue("4")), "96 hex digits long")
+}
+
+func Test_FindDnssecRecord(t *testing.T) {
+	r := require.New(t)
+
+	// A key published with two digest types shares the key tag
+	records := []porkbunapi.DNSSECRecord{
+		{KeyTag: "2371", Alg: "13", DigestType: "2", Digest: testDsDigest},
+		{KeyTag: "2371", Alg: "13", DigestType: "4", Digest: "00"},
+	}
+
+	record, ok := findDnssecRecord(records, "2371/13/4")
+	r.True(ok)
+	r.Equal("00", record.Digest)
+
+	_, ok = findDnssecRecord(records, "2371/8/2")
+	r.False(ok)
+}
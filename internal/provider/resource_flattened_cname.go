@@ -0,0 +1,514 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/reconcile"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/slices"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &porkbunFlattenedCnameResource{}
+var _ resource.ResourceWithConfigure = &porkbunFlattenedCnameResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunFlattenedCnameResource{}
+var _ resource.ResourceWithModifyPlan = &porkbunFlattenedCnameResource{}
+
+func NewFlattenedCnameResource() resource.Resource {
+	return &porkbunFlattenedCnameResource{lookup: lookupRecord}
+}
+
+type porkbunFlattenedCnameResource struct {
+	provider *porkbunProvider
+	lookup   dnsLookupFunc
+}
+
+type porkbunFlattenedCnameResourceData struct {
+	Id       types.String `tfsdk:"id"`
+	Domain   types.String `tfsdk:"domain"`
+	Name     types.String `tfsdk:"name"`
+	Target   types.String `tfsdk:"target"`
+	Ttl      types.String `tfsdk:"ttl"`
+	Resolver types.String `tfsdk:"resolver"`
+
+	Addresses         types.List `tfsdk:"addresses"`
+	ResolvedAddresses types.List `tfsdk:"resolved_addresses"`
+	RecordIds         types.Map  `tfsdk:"record_ids"`
+}
+
+func (r *porkbunFlattenedCnameResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_flattened_cname"
+}
+
+func (r *porkbunFlattenedCnameResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Serves the addresses of another hostname at a name that can't hold a CNAME record, like the apex of a domain. " +
+			"The target is resolved when the resource is created and on every refresh, and A and AAAA records are kept with its addresses. " +
+			"When the target moves to other addresses the refresh reports it in `resolved_addresses` and the plan updates the records to match",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The name the records are served at, including the domain",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The base domain to create the records on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The subdomain to create the records at without the base domain, defaults to the apex",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"target": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The hostname whose addresses are served, like `example.herokudns.com`. CNAME records on the way are followed",
+			},
+			"ttl": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The ttl of the records in seconds or as a duration like `1h`, between 600 and 86400 seconds. " +
+					"Defaults to the provider's `default_ttls` for A and AAAA records, or Porkbun's default of 600",
+			},
+			"resolver": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The resolver to look the target up on as an IP address or `host:port`, defaults to `" + defaultPropagationResolvers[0] + "`",
+			},
+			"addresses": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The addresses the records serve, sorted",
+			},
+			"resolved_addresses": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "The addresses the target resolved to at the last refresh, sorted. " +
+					"Kept from the refresh before when the target can't be resolved, so a failed lookup doesn't remove the records",
+			},
+			"record_ids": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The Porkbun IDs of the records, keyed by the address they serve",
+			},
+		},
+	}
+}
+
+func (r *porkbunFlattenedCnameResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunFlattenedCnameResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunFlattenedCnameResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Ttl.IsNull() && !data.Ttl.IsUnknown() {
+		warning, err := validateTTL(data.Ttl.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid TTL", err.Error())
+		} else if warning != "" {
+			resp.Diagnostics.AddAttributeWarning(path.Root("ttl"), "Unusual TTL", warning)
+		}
+	}
+
+	if !data.Resolver.IsNull() && !data.Resolver.IsUnknown() {
+		if _, err := resolverAddress(data.Resolver.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("resolver"), "Invalid resolver", err.Error())
+		}
+	}
+}
+
+// ModifyPlan plans the records to follow the addresses the last refresh resolved the target to. A new target
+// or resolver is only resolved while applying, the addresses are unknown until then.
+func (r *porkbunFlattenedCnameResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state porkbunFlattenedCnameResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Target.Equal(state.Target) || !plan.Resolver.Equal(state.Resolver) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("addresses"), types.ListUnknown(types.StringType))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_addresses"), types.ListUnknown(types.StringType))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("record_ids"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	recordIds := state.RecordIds
+	if !state.Addresses.Equal(state.ResolvedAddresses) {
+		tflog.Info(ctx, "Target of the flattened CNAME resolves to other addresses, planning to update the records", map[string]any{
+			"addresses":          state.Addresses.String(),
+			"resolved_addresses": state.ResolvedAddresses.String(),
+		})
+		recordIds = types.MapUnknown(types.StringType)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("addresses"), state.ResolvedAddresses)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("resolved_addresses"), state.ResolvedAddresses)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("record_ids"), recordIds)...)
+}
+
+func (r *porkbunFlattenedCnameResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunFlattenedCnameResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = flattenedCnameLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	addresses, err := r.resolveTarget(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not resolve %s", data.Target.ValueString()),
+			err.Error(),
+		)
+		return
+	}
+
+	recordIds, diags := r.syncRecords(ctx, data, map[string]string{}, addresses, false)
+	resp.Diagnostics.Append(diags...)
+	// Records created before a failure are kept in state, so they are cleaned up instead of left behind
+	data = flattenedCnameState(data, addresses, recordIds)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunFlattenedCnameResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunFlattenedCnameResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = flattenedCnameLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	var recordIds map[string]string
+	resp.Diagnostics.Append(data.RecordIds.ElementsAs(ctx, &recordIds, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	records, err := rt.records.get(ctx, data.Domain.ValueString(), func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, data.Domain.ValueString())
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf(`Could not retrieve records for %s.`, data.Domain.ValueString()),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	// Records deleted or changed outside of Terraform are dropped, the plan creates them again
+	served := map[string]string{}
+	for _, id := range recordIds {
+		if record, ok := records[id]; ok && net.ParseIP(record.Content) != nil {
+			served[record.Content] = id
+		}
+	}
+
+	resolved, err := r.resolveTarget(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Could not resolve %s", data.Target.ValueString()),
+			fmt.Sprintf("The records keep serving the addresses from the last refresh: %s", err),
+		)
+		resp.Diagnostics.Append(data.ResolvedAddresses.ElementsAs(ctx, &resolved, false)...)
+	} else if !data.ResolvedAddresses.Equal(addressList(resolved)) {
+		tflog.Info(ctx, "Target of the flattened CNAME resolves to other addresses", map[string]any{"resolved_addresses": resolved})
+	}
+
+	data = flattenedCnameState(data, resolved, served)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunFlattenedCnameResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunFlattenedCnameResourceData
+	var state porkbunFlattenedCnameResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = flattenedCnameLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	// Addresses known while planning are the ones the plan showed, a new target is resolved now
+	var addresses []string
+	if data.Addresses.IsUnknown() {
+		var err error
+		addresses, err = r.resolveTarget(ctx, data)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Could not resolve %s", data.Target.ValueString()),
+				err.Error(),
+			)
+			return
+		}
+	} else {
+		resp.Diagnostics.Append(data.Addresses.ElementsAs(ctx, &addresses, false)...)
+	}
+
+	var current map[string]string
+	resp.Diagnostics.Append(state.RecordIds.ElementsAs(ctx, &current, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Records that keep their address are only edited for what is written into them
+	edit := !data.Ttl.Equal(state.Ttl) || !data.Target.Equal(state.Target)
+	recordIds, diags := r.syncRecords(ctx, data, current, addresses, edit)
+	resp.Diagnostics.Append(diags...)
+	// Only a new target was resolved now, otherwise resolved_addresses stays as planned
+	resolved := []string(nil)
+	if data.ResolvedAddresses.IsUnknown() {
+		resolved = addresses
+	}
+	data = flattenedCnameState(data, resolved, recordIds)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunFlattenedCnameResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var state porkbunFlattenedCnameResourceData
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = flattenedCnameLogFields(ctx, state)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	var current map[string]string
+	resp.Diagnostics.Append(state.RecordIds.ElementsAs(ctx, &current, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags = r.syncRecords(ctx, state, current, nil, false)
+	resp.Diagnostics.Append(diags...)
+	if !resp.Diagnostics.HasError() {
+		tflog.Debug(ctx, "Deleted flattened CNAME records")
+	}
+}
+
+// resolveTarget looks up the A and AAAA addresses of the target, failing when it has none so a target that is
+// briefly unresolvable doesn't take the name down with it
+func (r *porkbunFlattenedCnameResource) resolveTarget(ctx context.Context, data porkbunFlattenedCnameResourceData) ([]string, error) {
+	resolver := defaultPropagationResolvers[0]
+	if !data.Resolver.IsNull() {
+		resolver = data.Resolver.ValueString()
+	}
+
+	var addresses []string
+	for _, recordType := range []string{"A", "AAAA"} {
+		values, err := r.lookup(ctx, resolver, data.Target.ValueString(), recordType)
+		if err != nil {
+			return nil, fmt.Errorf("looking up %s records on %s: %w", recordType, resolver, err)
+		}
+		for _, value := range values {
+			if net.ParseIP(value) != nil && !slices.Contains(addresses, value) {
+				addresses = append(addresses, value)
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%s has no A or AAAA records on %s", data.Target.ValueString(), resolver)
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// syncRecords makes the records in current, keyed by address, serve addresses instead with the changes
+// reconcile.Diff plans. When edit is set the records that are kept are written again with the TTL and notes
+// of data. The records that exist afterwards are returned, also when some changes failed.
+func (r *porkbunFlattenedCnameResource) syncRecords(ctx context.Context, data porkbunFlattenedCnameResourceData, current map[string]string, addresses []string, edit bool) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+	domain := data.Domain.ValueString()
+
+	result := make(map[string]string, len(addresses))
+	for address, id := range current {
+		result[address] = id
+	}
+
+	plan := reconcile.Diff(current, addresses, edit)
+	// New records have no ID yet
+	writes := append([]reconcile.Record{}, plan.Edit...)
+	for _, address := range plan.Create {
+		writes = append(writes, reconcile.Record{Key: address})
+	}
+	for _, write := range writes {
+		address, id := write.Key, write.ID
+		record := flattenedCnameRecord(data, address, rt)
+
+		var err error
+		if id != "" {
+			err = retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+				return rt.client.EditRecord(ctx, domain, id, record)
+			})
+		} else {
+			id, err = createRecord(ctx, rt, domain, record)
+		}
+		if err != nil {
+			diags.AddError(
+				fmt.Sprintf("Error writing the %s record for %s", record.Type, address),
+				apiErrorDetail(err),
+			)
+			continue
+		}
+		tflog.Debug(ctx, "Wrote flattened CNAME record", map[string]any{"address": address, "record_id": id})
+		rt.records.put(ctx, domain, writtenRecord(domain, id, record))
+		result[address] = id
+	}
+	if diags.HasError() {
+		// Keep serving the old addresses until the new ones are all in place
+		return result, diags
+	}
+
+	for _, stale := range plan.Delete {
+		address, id := stale.Key, stale.ID
+		err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+			return rt.client.DeleteRecord(ctx, domain, id)
+		})
+		if err != nil && !errors.Is(err, porkbunapi.ErrNotFound) {
+			diags.AddError(
+				fmt.Sprintf("Error deleting the record for %s", address),
+				apiErrorDetail(err),
+			)
+			continue
+		}
+		tflog.Debug(ctx, "Deleted flattened CNAME record", map[string]any{"address": address, "record_id": id})
+		rt.records.remove(ctx, domain, id)
+		delete(result, address)
+	}
+	return result, diags
+}
+
+// flattenedCnameRecord is the record serving address for data, A or AAAA depending on the address
+func flattenedCnameRecord(data porkbunFlattenedCnameResourceData, address string, rt *providerRuntime) porkbunapi.Record {
+	recordType := "AAAA"
+	if ip := net.ParseIP(address); ip != nil && ip.To4() != nil {
+		recordType = "A"
+	}
+	return porkbunapi.Record{
+		Name:    data.Name.ValueString(),
+		Type:    recordType,
+		Content: address,
+		TTL:     recordTTL(data.Ttl, recordType, rt.defaultTTLs),
+		Notes:   stampNotes("Flattened from "+data.Target.ValueString(), rt.managedNotesMarker),
+	}
+}
+
+// flattenedCnameState is data with the records in recordIds, keyed by the address they serve.
+// resolved_addresses is set to resolved unless it is nil.
+func flattenedCnameState(data porkbunFlattenedCnameResourceData, resolved []string, recordIds map[string]string) porkbunFlattenedCnameResourceData {
+	served := make([]string, 0, len(recordIds))
+	ids := make(map[string]attr.Value, len(recordIds))
+	for address, id := range recordIds {
+		served = append(served, address)
+		ids[address] = types.StringValue(id)
+	}
+	sort.Strings(served)
+
+	data.Id = types.StringValue(recordFQDN(data.Name.ValueString(), data.Domain.ValueString()))
+	data.Addresses = addressList(served)
+	if resolved != nil {
+		data.ResolvedAddresses = addressList(resolved)
+	}
+	data.RecordIds = types.MapValueMust(types.StringType, ids)
+	return data
+}
+
+func addressList(addresses []string) types.List {
+	elements := make([]attr.Value, 0, len(addresses))
+	for _, address := range addresses {
+		elements = append(elements, types.StringValue(address))
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+func flattenedCnameLogFields(ctx context.Context, data porkbunFlattenedCnameResourceData) context.Context {
+	ctx = tflog.SetField(ctx, "domain", data.Domain.ValueString())
+	ctx = tflog.SetField(ctx, "name", data.Name.ValueString())
+	return tflog.SetField(ctx, "target", data.Target.ValueString())
+}
@@ -0,0 +1,231 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/require"
+)
+
+// addressZone answers lookups from a map of "name type" to values
+type addressZone map[string][]string
+
+func (z addressZone) lookup(ctx context.Context, resolver string, name string, recordType string) ([]string, error) {
+	if values, ok := z[name+" down"]; ok {
+		return nil, fmt.Errorf("lookup %s: %s", name, values[0])
+	}
+	return append([]string{}, z[name+" "+recordType]...), nil
+}
+
+func newUnitFlattenedCnameResource(client porkbunClient, zone addressZone) *porkbunFlattenedCnameResource {
+	p := &porkbunProvider{}
+	p.setRuntime(&providerRuntime{
+		client:  client,
+		retries: retryPolicy{attempts: 1},
+		records: newRecordCache(),
+	})
+	return &porkbunFlattenedCnameResource{provider: p, lookup: zone.lookup}
+}
+
+// flattenedCnameTestState builds state, plan or config contents for the porkbun_flattened_cname schema
+func flattenedCnameTestState(t *testing.T, r *porkbunFlattenedCnameResource, data porkbunFlattenedCnameResourceData) tfsdk.State {
+	ctx := context.Background()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError())
+
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	diags := state.Set(ctx, &data)
+	require.False(t, diags.HasError(), "%v", diags)
+	return state
+}
+
+func unitFlattenedCnameData(target string) porkbunFlattenedCnameResourceData {
+	return porkbunFlattenedCnameResourceData{
+		Id:       types.StringUnknown(),
+		Domain:   types.StringValue("foobar.dev"),
+		Name:     types.StringNull(),
+		Target:   types.StringValue(target),
+		Ttl:      types.StringNull(),
+		Resolver: types.StringNull(),
+
+		Addresses:         types.ListUnknown(types.StringType),
+		ResolvedAddresses: types.ListUnknown(types.StringType),
+		RecordIds:         types.MapUnknown(types.StringType),
+	}
+}
+
+// servedAddresses returns the content of the records of client at name, by record type
+func servedAddresses(t *testing.T, client *fakeClient, name string) []string {
+	records, err := client.RetrieveRecords(context.Background(), "foobar.dev")
+	require.NoError(t, err)
+
+	var served []string
+	for _, record := range records {
+		if record.Name == name {
+			served = append(served, record.Type+" "+record.Content)
+		}
+	}
+	sort.Strings(served)
+	return served
+}
+
+func Test_FlattenedCnameLifecycle(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	zone := addressZone{
+		"app.herokudns.com A":    {"192.0.2.2", "192.0.2.1"},
+		"app.herokudns.com AAAA": {"2001:db8::1"},
+	}
+	client := newFakeClient("foobar.dev")
+	res := newUnitFlattenedCnameResource(client, zone)
+
+	// Create serves every address of the target at the apex
+	plan := flattenedCnameTestState(t, res, unitFlattenedCnameData("app.herokudns.com"))
+	createResp := fwresource.CreateResponse{State: plan}
+	res.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, &createResp)
+	r.False(createResp.Diagnostics.HasError(), "%v", createResp.Diagnostics)
+	r.Equal([]string{"A 192.0.2.1", "A 192.0.2.2", "AAAA 2001:db8::1"}, servedAddresses(t, client, "foobar.dev"))
+
+	var created porkbunFlattenedCnameResourceData
+	r.False(createResp.State.Get(ctx, &created).HasError())
+	r.Equal("foobar.dev", created.Id.ValueString())
+	r.Equal(addressList([]string{"192.0.2.1", "192.0.2.2", "2001:db8::1"}), created.Addresses)
+	r.Equal(created.Addresses, created.ResolvedAddresses)
+
+	// Refreshing picks up the target moving
+	zone["app.herokudns.com A"] = []string{"192.0.2.2", "192.0.2.3"}
+	readResp := fwresource.ReadResponse{State: createResp.State}
+	res.Read(ctx, fwresource.ReadRequest{State: createResp.State}, &readResp)
+	r.False(readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+
+	var refreshed porkbunFlattenedCnameResourceData
+	r.False(readResp.State.Get(ctx, &refreshed).HasError())
+	r.Equal(created.Addresses, refreshed.Addresses)
+	r.Equal(addressList([]string{"192.0.2.2", "192.0.2.3", "2001:db8::1"}), refreshed.ResolvedAddresses)
+
+	// The plan follows the resolved addresses
+	modifyReq := fwresource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: readResp.State.Schema, Raw: readResp.State.Raw},
+		Plan:   tfsdk.Plan{Schema: readResp.State.Schema, Raw: readResp.State.Raw},
+		State:  readResp.State,
+	}
+	modifyResp := fwresource.ModifyPlanResponse{Plan: modifyReq.Plan}
+	res.ModifyPlan(ctx, modifyReq, &modifyResp)
+	r.False(modifyResp.Diagnostics.HasError(), "%v", modifyResp.Diagnostics)
+
+	var planned porkbunFlattenedCnameResourceData
+	r.False(modifyResp.Plan.Get(ctx, &planned).HasError())
+	r.Equal(refreshed.ResolvedAddresses, planned.Addresses)
+	r.True(planned.RecordIds.IsUnknown())
+
+	// Updating only replaces the record of the address that went away
+	updateResp := fwresource.UpdateResponse{State: readResp.State}
+	res.Update(ctx, fwresource.UpdateRequest{Plan: modifyResp.Plan, State: readResp.State}, &updateResp)
+	r.False(updateResp.Diagnostics.HasError(), "%v", updateResp.Diagnostics)
+	r.Equal([]string{"A 192.0.2.2", "A 192.0.2.3", "AAAA 2001:db8::1"}, servedAddresses(t, client, "foobar.dev"))
+	r.Empty(client.edits)
+
+	var updated porkbunFlattenedCnameResourceData
+	r.False(updateResp.State.Get(ctx, &updated).HasError())
+	r.Equal(planned.Addresses, updated.Addresses)
+	r.Equal(planned.ResolvedAddresses, updated.ResolvedAddresses)
+	var ids map[string]string
+	r.False(updated.RecordIds.ElementsAs(ctx, &ids, false).HasError())
+	r.Len(ids, 3)
+
+	deleteResp := fwresource.DeleteResponse{State: updateResp.State}
+	res.Delete(ctx, fwresource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	r.False(deleteResp.Diagnostics.HasError(), "%v", deleteResp.Diagnostics)
+	r.Empty(servedAddresses(t, client, "foobar.dev"))
+}
+
+func Test_FlattenedCnameUnresolvableTarget(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	zone := addressZone{"app.herokudns.com A": {"192.0.2.1"}}
+	client := newFakeClient("foobar.dev")
+	res := newUnitFlattenedCnameResource(client, zone)
+
+	data := unitFlattenedCnameData("missing.herokudns.com")
+	data.Name = types.StringValue("www")
+	plan := flattenedCnameTestState(t, res, data)
+	createResp := fwresource.CreateResponse{State: plan}
+	res.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, &createResp)
+	r.True(createResp.Diagnostics.HasError())
+	r.Contains(createResp.Diagnostics.Errors()[0].Detail(), "has no A or AAAA records")
+	r.Empty(servedAddresses(t, client, "www.foobar.dev"))
+
+	// A target that stops resolving keeps the addresses of the last refresh
+	id := client.addRecord("foobar.dev", porkbunapi.Record{Name: "www.foobar.dev", Type: "A", Content: "192.0.2.1"})
+	data = unitFlattenedCnameData("app.herokudns.com")
+	data.Name = types.StringValue("www")
+	data = flattenedCnameState(data, []string{"192.0.2.1"}, map[string]string{"192.0.2.1": id})
+	zone["app.herokudns.com down"] = []string{"i/o timeout"}
+
+	state := flattenedCnameTestState(t, res, data)
+	readResp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &readResp)
+	r.False(readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+	r.Len(readResp.Diagnostics.Warnings(), 1)
+
+	var refreshed porkbunFlattenedCnameResourceData
+	r.False(readResp.State.Get(ctx, &refreshed).HasError())
+	r.Equal(addressList([]string{"192.0.2.1"}), refreshed.Addresses)
+	r.Equal(refreshed.Addresses, refreshed.ResolvedAddresses)
+}
+
+func Test_FlattenedCnameRecordDeletedOutsideTerraform(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	zone := addressZone{"app.herokudns.com A": {"192.0.2.1"}}
+	client := newFakeClient("foobar.dev")
+	res := newUnitFlattenedCnameResource(client, zone)
+
+	// The record of the address is gone, so the plan creates it again
+	data := flattenedCnameState(unitFlattenedCnameData("app.herokudns.com"), []string{"192.0.2.1"}, map[string]string{"192.0.2.1": "404"})
+	state := flattenedCnameTestState(t, res, data)
+	readResp := fwresource.ReadResponse{State: state}
+	res.Read(ctx, fwresource.ReadRequest{State: state}, &readResp)
+	r.False(readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+
+	var refreshed porkbunFlattenedCnameResourceData
+	r.False(readResp.State.Get(ctx, &refreshed).HasError())
+	r.Equal(addressList([]string{}), refreshed.Addresses)
+	r.Equal(addressList([]string{"192.0.2.1"}), refreshed.ResolvedAddresses)
+}
+
+func Test_FlattenedCnameAdoptsAfterLostResponse(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	zone := addressZone{"app.herokudns.com A": {"192.0.2.1"}}
+	fake := newFakeClient("foobar.dev")
+	res := newUnitFlattenedCnameResource(lostResponseClient{fake, func(ctx context.Context, domain string, record porkbunapi.Record) (string, error) {
+		_, err := fake.CreateRecord(ctx, domain, record)
+		r.NoError(err)
+		return "", errors.New("context deadline exceeded")
+	}}, zone)
+
+	// The record was created with only the response lost, it is adopted instead of being created twice
+	plan := flattenedCnameTestState(t, res, unitFlattenedCnameData("app.herokudns.com"))
+	createResp := fwresource.CreateResponse{State: plan}
+	res.Create(ctx, fwresource.CreateRequest{Plan: tfsdk.Plan{Schema: plan.Schema, Raw: plan.Raw}}, &createResp)
+	r.False(createResp.Diagnostics.HasError(), "%v", createResp.Diagnostics)
+	r.Equal([]string{"A 192.0.2.1"}, servedAddresses(t, fake, "foobar.dev"))
+}
@@ -0,0 +1,439 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &porkbunGlueRecordResource{}
+var _ resource.ResourceWithConfigure = &porkbunGlueRecordResource{}
+var _ resource.ResourceWithImportState = &porkbunGlueRecordResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunGlueRecordResource{}
+
+func NewGlueRecordResource() resource.Resource {
+	return &porkbunGlueRecordResource{}
+}
+
+type porkbunGlueRecordResource struct {
+	provider *porkbunProvider
+}
+
+type porkbunGlueRecordResourceData struct {
+	Id        types.String `tfsdk:"id"`
+	Domain    types.String `tfsdk:"domain"`
+	Subdomain types.String `tfsdk:"subdomain"`
+	Ipv4      types.Set    `tfsdk:"ipv4"`
+	Ipv6      types.Set    `tfsdk:"ipv6"`
+
+	RequireDualStack types.Bool `tfsdk:"require_dual_stack"`
+}
+
+func (r *porkbunGlueRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_glue_record"
+}
+
+func (r *porkbunGlueRecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Registers the addresses of a nameserver host under a domain at the registry, for vanity nameservers like " +
+			"`ns1.example.com` serving `example.com` itself. Importing takes `domain/subdomain`",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The fully qualified host, like `ns1.example.com`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain the host is under",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subdomain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The host without the domain, like `ns1`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ipv4": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The IPv4 addresses of the host. At least one address of either family is required",
+			},
+			"ipv6": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "The IPv6 addresses of the host, IPv4-mapped addresses like `::ffff:192.0.2.1` included. " +
+					"At least one address of either family is required",
+			},
+			"require_dual_stack": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Fail the plan unless both `ipv4` and `ipv6` list an address, for registries and policies that " +
+					"require nameservers to be reachable over both",
+			},
+		},
+	}
+}
+
+func (r *porkbunGlueRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunGlueRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunGlueRecordResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Ipv4.IsUnknown() && !data.Ipv6.IsUnknown() && len(data.Ipv4.Elements())+len(data.Ipv6.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ipv4"),
+			"Missing addresses",
+			"At least one of ipv4 and ipv6 has to list an address, Porkbun doesn't register hosts without one",
+		)
+	}
+
+	if data.RequireDualStack.ValueBool() {
+		for _, family := range []struct {
+			attribute string
+			value     types.Set
+		}{{"ipv4", data.Ipv4}, {"ipv6", data.Ipv6}} {
+			if !family.value.IsUnknown() && len(family.value.Elements()) == 0 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(family.attribute),
+					"Missing addresses",
+					fmt.Sprintf("require_dual_stack is set, %s has to list an address", family.attribute),
+				)
+			}
+		}
+	}
+
+	families := []struct {
+		attribute string
+		name      string
+		value     types.Set
+		v6        bool
+	}{
+		{"ipv4", "IPv4", data.Ipv4, false},
+		{"ipv6", "IPv6", data.Ipv6, true},
+	}
+	for _, family := range families {
+		for _, element := range family.value.Elements() {
+			address, ok := element.(types.String)
+			if !ok || address.IsNull() || address.IsUnknown() {
+				continue
+			}
+			// IPv4-mapped IPv6 addresses parse to IPv4 ones, the colons tell the families apart
+			if ip := net.ParseIP(address.ValueString()); ip == nil || strings.Contains(address.ValueString(), ":") != family.v6 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(family.attribute),
+					"Invalid address",
+					fmt.Sprintf("%q isn't an %s address", address.ValueString(), family.name),
+				)
+			}
+		}
+	}
+}
+
+func (r *porkbunGlueRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunGlueRecordResourceData
+	rt := r.provider.runtime()
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = glueRecordLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	ips, diags := glueAddresses(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, subdomain := data.Domain.ValueString(), data.Subdomain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.CreateGlueRecord(ctx, domain, subdomain, ips)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating glue record",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	data.Id = types.StringValue(glueHost(data))
+	tflog.Debug(ctx, "Created glue record", map[string]any{"ips": ips})
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunGlueRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunGlueRecordResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = glueRecordLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	records, err := r.records(ctx, data.Domain.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve glue records for %s.", data.Domain.ValueString()),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	record, ok := findGlueRecord(records, glueHost(data))
+	if !ok {
+		tflog.Warn(ctx, "Glue record no longer exists, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data, diags = refreshGlueRecord(ctx, data, record)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunGlueRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunGlueRecordResourceData
+	rt := r.provider.runtime()
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = glueRecordLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	ips, diags := glueAddresses(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	domain, subdomain := data.Domain.ValueString(), data.Subdomain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.UpdateGlueRecord(ctx, domain, subdomain, ips)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating glue record",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Updated glue record", map[string]any{"ips": ips})
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunGlueRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var state porkbunGlueRecordResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = glueRecordLogFields(ctx, state)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domain := state.Domain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.DeleteGlueRecord(ctx, domain, state.Subdomain.ValueString())
+	})
+	err = ignoreAlreadyDeleted(ctx, "Glue record", err, func(ctx context.Context) (bool, error) {
+		records, err := r.records(ctx, domain)
+		_, ok := findGlueRecord(records, glueHost(state))
+		return ok, err
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting glue record",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Deleted glue record")
+}
+
+func (r *porkbunGlueRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	domain, subdomain, ok := strings.Cut(req.ID, "/")
+	if !ok || domain == "" || subdomain == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("%q isn't domain/subdomain, like example.com/ns1", req.ID),
+		)
+		return
+	}
+	if err := validateImportDomain(domain); err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("invalid import ID %q: %s", req.ID, err))
+		return
+	}
+
+	domain, subdomain = strings.ToLower(domain), strings.ToLower(subdomain)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), subdomain+"."+domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("subdomain"), subdomain)...)
+}
+
+func (r *porkbunGlueRecordResource) records(ctx context.Context, domain string) ([]porkbunapi.GlueRecord, error) {
+	rt := r.provider.runtime()
+	return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.GlueRecord, error) {
+		return rt.client.GetGlueRecords(ctx, domain)
+	})
+}
+
+func glueRecordLogFields(ctx context.Context, data porkbunGlueRecordResourceData) context.Context {
+	return tflog.SetField(ctx, "host", glueHost(data))
+}
+
+// glueHost is the fully qualified host data registers
+func glueHost(data porkbunGlueRecordResourceData) string {
+	return recordFQDN(data.Subdomain.ValueString(), data.Domain.ValueString())
+}
+
+// glueAddresses are the addresses of data in the single list the API takes, IPv4 first
+func glueAddresses(ctx context.Context, data porkbunGlueRecordResourceData) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var ips []string
+	for _, family := range []types.Set{data.Ipv4, data.Ipv6} {
+		if family.IsNull() {
+			continue
+		}
+		var addresses []string
+		diags.Append(family.ElementsAs(ctx, &addresses, false)...)
+		ips = append(ips, addresses...)
+	}
+	return ips, diags
+}
+
+func findGlueRecord(records []porkbunapi.GlueRecord, host string) (porkbunapi.GlueRecord, bool) {
+	for _, record := range records {
+		if strings.EqualFold(normalizeDnsValue(record.Host), host) {
+			return record, true
+		}
+	}
+	return porkbunapi.GlueRecord{}, false
+}
+
+// refreshGlueRecord copies the addresses the registry has for the host into data
+func refreshGlueRecord(ctx context.Context, data porkbunGlueRecordResourceData, record porkbunapi.GlueRecord) (porkbunGlueRecordResourceData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	data.Ipv4, diags = refreshAddresses(ctx, data.Ipv4, record.IPv4)
+	if diags.HasError() {
+		return data, diags
+	}
+	ipv6, ipv6Diags := refreshAddresses(ctx, data.Ipv6, record.IPv6)
+	diags.Append(ipv6Diags...)
+	data.Ipv6 = ipv6
+	return data, diags
+}
+
+// refreshAddresses keeps the addresses in current while live holds the same ones, however they are written.
+// A family left out of the config stays null while the host has no address of it.
+func refreshAddresses(ctx context.Context, current types.Set, live []string) (types.Set, diag.Diagnostics) {
+	if current.IsNull() && len(live) == 0 {
+		return current, nil
+	}
+
+	var diags diag.Diagnostics
+	var configured []string
+	if !current.IsNull() {
+		diags.Append(current.ElementsAs(ctx, &configured, false)...)
+		if diags.HasError() {
+			return current, diags
+		}
+	}
+	if strings.Join(canonicalAddresses(configured), ",") == strings.Join(canonicalAddresses(live), ",") {
+		return current, diags
+	}
+
+	elements := make([]attr.Value, 0, len(live))
+	for _, address := range live {
+		elements = append(elements, types.StringValue(address))
+	}
+	return types.SetValueMust(types.StringType, elements), diags
+}
+
+// canonicalAddresses returns addresses in their shortest form, sorted
+func canonicalAddresses(addresses []string) []string {
+	canonical := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil {
+			address = ip.String()
+		}
+		canonical = append(canonical, address)
+	}
+	sort.Strings(canonical)
+	return canonical
+}
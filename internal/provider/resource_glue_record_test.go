@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GlueRecord(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_glue_record" "test" {
+            domain    = "foobar.dev"
+            subdomain = "ns1"
+            ipv4      = ["192.0.2.1"]
+          }
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_glue_record.test", "id", "ns1.foobar.dev"),
+					func(*terraform.State) error {
+						require.Equal(t, []porkbuntest.GlueHost{{Host: "ns1.foobar.dev", IPv4: []string{"192.0.2.1"}}}, server.Glue("foobar.dev"))
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "porkbun_glue_record.test",
+				ImportState:       true,
+				ImportStateId:     "foobar.dev/ns1",
+				ImportStateVerify: true,
+			},
+			{
+				// Addresses are updated in place
+				Config: `
+          resource "porkbun_glue_record" "test" {
+            domain    = "foobar.dev"
+            subdomain = "ns1"
+            ipv4      = ["192.0.2.1", "192.0.2.2"]
+            ipv6      = ["2001:0db8::0001"]
+          }
+				`,
+				Check: func(*terraform.State) error {
+					glue := server.Glue("foobar.dev")
+					require.Len(t, glue, 1)
+					require.ElementsMatch(t, []string{"192.0.2.1", "192.0.2.2"}, glue[0].IPv4)
+					require.Equal(t, []string{"2001:0db8::0001"}, glue[0].IPv6)
+					return nil
+				},
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if glue := server.Glue("foobar.dev"); len(glue) != 0 {
+				return fmt.Errorf("expected all glue records to be deleted, found %v", glue)
+			}
+			return nil
+		},
+	})
+}
+
+func Test_GlueRecordInvalidAddress(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_glue_record" "test" {
+            domain    = "foobar.dev"
+            subdomain = "ns1"
+            ipv4      = ["2001:db8::1"]
+          }
+				`,
+				ExpectError: regexp.MustCompile(`isn't an IPv4 address`),
+			},
+			{
+				Config: `
+          resource "porkbun_glue_record" "test" {
+            domain    = "foobar.dev"
+            subdomain = "ns1"
+            ipv4      = ["::ffff:192.0.2.1"]
+          }
+				`,
+				ExpectError: regexp.MustCompile(`isn't an IPv4 address`),
+			},
+			{
+				Config: `
+          resource "porkbun_glue_record" "test" {
+            domain             = "foobar.dev"
+            subdomain          = "ns1"
+            ipv4               = ["192.0.2.1"]
+            require_dual_stack = true
+          }
+				`,
+				ExpectError: regexp.MustCompile(`require_dual_stack is set, ipv6 has to list an address`),
+			},
+		},
+	})
+}
+
+func Test_GlueRecordMappedAddress(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				// IPv4-mapped addresses are IPv6 addresses, dual stack is satisfied by them
+				Config: `
+          resource "porkbun_glue_record" "test" {
+            domain             = "foobar.dev"
+            subdomain          = "ns1"
+            ipv4               = ["192.0.2.1"]
+            ipv6               = ["::ffff:192.0.2.1"]
+            require_dual_stack = true
+          }
+				`,
+				Check: func(*terraform.State) error {
+					glue := server.Glue("foobar.dev")
+					require.Len(t, glue, 1)
+					require.Equal(t, []string{"::ffff:192.0.2.1"}, glue[0].IPv6)
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func Test_RefreshAddresses(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	current := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("2001:0db8::0001")})
+
+	// The registry writing addresses in their short form isn't drift
+	refreshed, diags := refreshAddresses(ctx, current, []string{"2001:db8::1"})
+	r.False(diags.HasError())
+	r.Equal(current, refreshed)
+
+	refreshed, diags = refreshAddresses(ctx, current, []string{"2001:db8::2"})
+	r.False(diags.HasError())
+	r.Equal(types.SetValueMust(types.StringType, []attr.Value{types.StringValue("2001:db8::2")}), refreshed)
+
+	// A family left out of the config stays null until the host gets an address of it
+	refreshed, diags = refreshAddresses(ctx, types.SetNull(types.StringType), nil)
+	r.False(diags.HasError())
+	r.True(refreshed.IsNull())
+
+	refreshed, diags = refreshAddresses(ctx, types.SetNull(types.StringType), []string{"192.0.2.1"})
+	r.False(diags.HasError())
+	r.Equal(types.SetValueMust(types.StringType, []attr.Value{types.StringValue("192.0.2.1")}), refreshed)
+}
@@ -0,0 +1,500 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &porkbunNameserversResource{}
+var _ resource.ResourceWithConfigure = &porkbunNameserversResource{}
+var _ resource.ResourceWithImportState = &porkbunNameserversResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunNameserversResource{}
+var _ resource.ResourceWithModifyPlan = &porkbunNameserversResource{}
+
+// The nameservers Porkbun sets on the domains it registers, destroying the resource goes back to them
+var porkbunDefaultNameservers = []string{
+	"curitiba.ns.porkbun.com",
+	"fortaleza.ns.porkbun.com",
+	"maceio.ns.porkbun.com",
+	"salvador.ns.porkbun.com",
+}
+
+// The nameservers of DNS hostings that use the same ones for every zone, by the name of the preset. Hostings
+// like Cloudflare or Route 53 assign nameservers to each account or zone and have none.
+var nameserverPresets = map[string][]string{
+	"porkbun":      porkbunDefaultNameservers,
+	"digitalocean": {"ns1.digitalocean.com", "ns2.digitalocean.com", "ns3.digitalocean.com"},
+	"hetzner":      {"helium.ns.hetzner.de", "hydrogen.ns.hetzner.com", "oxygen.ns.hetzner.com"},
+	"linode":       {"ns1.linode.com", "ns2.linode.com", "ns3.linode.com", "ns4.linode.com", "ns5.linode.com"},
+	"vultr":        {"ns1.vultr.com", "ns2.vultr.com"},
+}
+
+func NewNameserversResource() resource.Resource {
+	return &porkbunNameserversResource{lookup: lookupRecord, delegation: lookupDelegation}
+}
+
+type porkbunNameserversResource struct {
+	provider   *porkbunProvider
+	lookup     dnsLookupFunc
+	delegation delegationLookupFunc
+}
+
+type porkbunNameserversResourceData struct {
+	Id                types.String `tfsdk:"id"`
+	Domain            types.String `tfsdk:"domain"`
+	Nameservers       types.List   `tfsdk:"nameservers"`
+	Preset            types.String `tfsdk:"preset"`
+	KeepOnDestroy     types.Bool   `tfsdk:"keep_on_destroy"`
+	CheckDelegation   types.Bool   `tfsdk:"check_delegation"`
+	DelegationTimeout types.String `tfsdk:"delegation_timeout"`
+}
+
+func (r *porkbunNameserversResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nameservers"
+}
+
+func (r *porkbunNameserversResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sets the nameservers of a domain registered at Porkbun, for hosting its DNS elsewhere like Route 53 or Cloudflare. " +
+			"A refresh finding other nameservers plans to set them again. Destroying the resource restores Porkbun's default nameservers " +
+			"unless `keep_on_destroy` is set. Importing takes the domain",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The domain",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The domain to set the nameservers of",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"nameservers": schema.ListAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "The nameservers to set, like `[\"kim.ns.cloudflare.com\", \"bob.ns.cloudflare.com\"]`. " +
+					"Their order and case don't matter. Nameservers that don't resolve fail the plan, except the ones under the domain " +
+					"itself, which are resolved through its glue records. Exactly one of `nameservers` and `preset` is required",
+			},
+			"preset": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Sets the nameservers of a DNS hosting using the same ones for every zone, one of `" +
+					strings.Join(sortedKeys(nameserverPresets), "`, `") + "`. Hostings like Cloudflare or Route 53 assign " +
+					"nameservers to each zone, set those in `nameservers`",
+			},
+			"keep_on_destroy": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Leave the nameservers as they are when the resource is destroyed, instead of restoring Porkbun's defaults. " +
+					"The value in state is used, so it has to be applied before the destroy",
+			},
+			"check_delegation": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "After setting the nameservers, ask the nameservers of the parent zone of the domain which nameservers " +
+					"the registry delegates it to, and warn when that isn't the new ones within `delegation_timeout`",
+			},
+			"delegation_timeout": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "How long `check_delegation` waits for the registry in seconds or as a duration like `10m`, defaults to `" +
+					defaultPropagationTimeout.String() + "`",
+			},
+		},
+	}
+}
+
+func (r *porkbunNameserversResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunNameserversResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunNameserversResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case !data.Nameservers.IsNull() && !data.Preset.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("preset"),
+			"Conflicting nameservers",
+			"Only one of nameservers and preset can be set",
+		)
+	case data.Nameservers.IsNull() && data.Preset.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			path.Root("nameservers"),
+			"Missing nameservers",
+			"One of nameservers and preset is required",
+		)
+	case !data.Nameservers.IsNull() && !data.Nameservers.IsUnknown() && len(data.Nameservers.Elements()) == 0:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("nameservers"),
+			"Missing nameservers",
+			"At least one nameserver is required, Porkbun doesn't accept removing all of them",
+		)
+	}
+
+	if !data.Preset.IsNull() && !data.Preset.IsUnknown() {
+		if _, ok := nameserverPresets[strings.ToLower(data.Preset.ValueString())]; !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("preset"),
+				"Unknown nameserver preset",
+				fmt.Sprintf("%q isn't a preset, expected one of %s", data.Preset.ValueString(), strings.Join(sortedKeys(nameserverPresets), ", ")),
+			)
+		}
+	}
+
+	if !data.DelegationTimeout.IsNull() && !data.DelegationTimeout.IsUnknown() {
+		if _, err := parsePositiveDuration(data.DelegationTimeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("delegation_timeout"), "Invalid delegation timeout", err.Error())
+		}
+	}
+}
+
+// ModifyPlan expands preset into the nameservers it stands for and fails the plan when nameservers about to be
+// set don't resolve, as Porkbun accepts them and the domain stops resolving instead
+func (r *porkbunNameserversResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan porkbunNameserversResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Preset.IsNull() && !plan.Preset.IsUnknown() {
+		if preset, ok := nameserverPresets[strings.ToLower(plan.Preset.ValueString())]; ok {
+			plan.Nameservers = addressList(preset)
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("nameservers"), plan.Nameservers)...)
+		}
+	}
+	if plan.Nameservers.IsUnknown() || plan.Domain.IsUnknown() {
+		return
+	}
+
+	// Nameservers known only after the apply, like the host of a glue record created along, are left out
+	var nameservers []string
+	for _, element := range plan.Nameservers.Elements() {
+		if nameserver, ok := element.(types.String); ok && !nameserver.IsUnknown() && !nameserver.IsNull() {
+			nameservers = append(nameservers, nameserver.ValueString())
+		}
+	}
+
+	// Nameservers already set were looked up when they were planned
+	if !req.State.Raw.IsNull() {
+		var state porkbunNameserversResourceData
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		var current []string
+		resp.Diagnostics.Append(state.Nameservers.ElementsAs(ctx, &current, false)...)
+		if resp.Diagnostics.HasError() || sameNameservers(current, nameservers) {
+			return
+		}
+	}
+
+	for _, nameserver := range unresolvableNameservers(ctx, r.lookup, plan.Domain.ValueString(), nameservers) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("nameservers"),
+			"Unresolvable nameserver",
+			fmt.Sprintf("%s doesn't resolve to an address, check it for typos. The domain would stop resolving with it.", nameserver),
+		)
+	}
+}
+
+func (r *porkbunNameserversResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunNameserversResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "domain", data.Domain.ValueString())
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	resp.Diagnostics.Append(r.apply(ctx, data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Id = data.Domain
+	resp.Diagnostics.Append(r.checkDelegation(ctx, data)...)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunNameserversResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunNameserversResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "domain", data.Domain.ValueString())
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domain := data.Domain.ValueString()
+	live, err := retry(ctx, rt.retries, func(ctx context.Context) ([]string, error) {
+		return rt.client.GetNameservers(ctx, domain)
+	})
+	if errors.Is(err, porkbunapi.ErrNotFound) {
+		tflog.Warn(ctx, "Domain is no longer in the account, removing its nameservers from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve the nameservers of %s.", domain),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	var current []string
+	resp.Diagnostics.Append(data.Nameservers.ElementsAs(ctx, &current, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Nameservers = refreshNameservers(data.Nameservers, current, live)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunNameserversResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunNameserversResourceData
+	var state porkbunNameserversResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "domain", data.Domain.ValueString())
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	// Only keep_on_destroy may have changed, there's nothing to send to Porkbun
+	if !data.Nameservers.Equal(state.Nameservers) {
+		resp.Diagnostics.Append(r.apply(ctx, data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(r.checkDelegation(ctx, data)...)
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunNameserversResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var state porkbunNameserversResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "domain", state.Domain.ValueString())
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	if state.KeepOnDestroy.ValueBool() {
+		tflog.Info(ctx, "Keeping nameservers on destroy")
+		return
+	}
+
+	domain := state.Domain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.UpdateNameservers(ctx, domain, porkbunDefaultNameservers)
+	})
+	if errors.Is(err, porkbunapi.ErrNotFound) {
+		// The domain left the account, its nameservers aren't Porkbun's to restore anymore
+		tflog.Warn(ctx, "Domain is no longer in the account, nameservers weren't restored", map[string]any{"error": err.Error()})
+		err = nil
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Error restoring the default nameservers of %s", domain),
+			apiErrorDetail(err),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Restored Porkbun's default nameservers")
+}
+
+func (r *porkbunNameserversResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if err := validateImportDomain(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("invalid import ID %q: %s", req.ID, err))
+		return
+	}
+
+	domain := strings.ToLower(req.ID)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), domain)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+	// Read fills in what the domain uses, an empty list never matches it
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("nameservers"), types.ListValueMust(types.StringType, []attr.Value{}))...)
+}
+
+// apply sets the nameservers in data on its domain
+func (r *porkbunNameserversResource) apply(ctx context.Context, data porkbunNameserversResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	var nameservers []string
+	diags.Append(data.Nameservers.ElementsAs(ctx, &nameservers, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	domain := data.Domain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.UpdateNameservers(ctx, domain, nameservers)
+	})
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf("Error setting the nameservers of %s", domain),
+			apiErrorDetail(err),
+		)
+		return diags
+	}
+	tflog.Debug(ctx, "Set nameservers", map[string]any{"nameservers": nameservers})
+	return diags
+}
+
+// checkDelegation waits for the registry to delegate the domain to the nameservers in data when
+// check_delegation is set. The nameservers are set either way, so a registry that is slow only warns.
+func (r *porkbunNameserversResource) checkDelegation(ctx context.Context, data porkbunNameserversResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !data.CheckDelegation.ValueBool() {
+		return diags
+	}
+
+	var nameservers []string
+	diags.Append(data.Nameservers.ElementsAs(ctx, &nameservers, false)...)
+	timeout := defaultPropagationTimeout
+	if !data.DelegationTimeout.IsNull() {
+		parsed, err := parsePositiveDuration(data.DelegationTimeout.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("delegation_timeout"), "Invalid delegation timeout", err.Error())
+		}
+		timeout = parsed
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	domain := data.Domain.ValueString()
+	if err := waitForDelegation(ctx, r.delegation, domain, nameservers, defaultPropagationInterval, timeout); err != nil {
+		diags.AddAttributeWarning(
+			path.Root("check_delegation"),
+			"Registry hasn't picked up the nameservers",
+			fmt.Sprintf("Porkbun has the new nameservers of %s, but %s. Registries usually publish them within minutes, "+
+				"check the domain in the Porkbun dashboard if it takes longer.", domain, err),
+		)
+		return diags
+	}
+	tflog.Debug(ctx, "Registry delegates to the nameservers")
+	return diags
+}
+
+// unresolvableNameservers looks up the addresses of nameservers and returns the ones having none. Nameservers
+// under domain are resolved through its glue records and nameservers that couldn't be looked up aren't
+// reported.
+func unresolvableNameservers(ctx context.Context, lookup dnsLookupFunc, domain string, nameservers []string) []string {
+	var unresolvable []string
+	for _, nameserver := range nameservers {
+		host := strings.ToLower(normalizeDnsValue(nameserver))
+		if host == strings.ToLower(domain) || strings.HasSuffix(host, "."+strings.ToLower(domain)) {
+			continue
+		}
+
+		resolved := false
+		for _, recordType := range []string{"A", "AAAA"} {
+			addresses, err := lookup(ctx, defaultPropagationResolvers[0], host, recordType)
+			if err != nil {
+				tflog.Debug(ctx, "Unable to look up nameserver", map[string]any{"nameserver": host, "error": err.Error()})
+				resolved = true
+				break
+			}
+			if len(addresses) > 0 {
+				resolved = true
+				break
+			}
+		}
+		if !resolved {
+			unresolvable = append(unresolvable, nameserver)
+		}
+	}
+	sort.Strings(unresolvable)
+	return unresolvable
+}
+
+// refreshNameservers keeps the nameservers in state while the domain uses them, written however the config
+// writes them, and otherwise the nameservers the domain uses so the difference shows up as drift
+func refreshNameservers(state types.List, current []string, live []string) types.List {
+	if sameNameservers(live, current) {
+		return state
+	}
+	return addressList(live)
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Nameservers(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+	cloudflare := []string{"kim.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+
+	config := `
+          resource "porkbun_nameservers" "test" {
+            domain      = "foobar.dev"
+            nameservers = ["kim.ns.cloudflare.com", "bob.ns.cloudflare.com"]
+          }
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_nameservers.test", "id", "foobar.dev"),
+					func(*terraform.State) error {
+						require.Equal(t, cloudflare, server.Nameservers("foobar.dev"))
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "porkbun_nameservers.test",
+				ImportState:       true,
+				ImportStateId:     "foobar.dev",
+				ImportStateVerify: true,
+			},
+			{
+				// Changed in the dashboard, the refresh notices and the apply sets them again
+				PreConfig: func() {
+					client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+					client.BaseURL, _ = url.Parse(server.URL)
+					require.NoError(t, client.UpdateNameservers(context.Background(), "foobar.dev", porkbunDefaultNameservers))
+				},
+				Config: config,
+				Check: func(*terraform.State) error {
+					require.Equal(t, cloudflare, server.Nameservers("foobar.dev"))
+					return nil
+				},
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if nameservers := server.Nameservers("foobar.dev"); !sameNameservers(nameservers, porkbunDefaultNameservers) {
+				return fmt.Errorf("expected Porkbun's nameservers to be restored, found %v", nameservers)
+			}
+			return nil
+		},
+	})
+}
+
+func Test_NameserversKeepOnDestroy(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_nameservers" "test" {
+            domain          = "foobar.dev"
+            nameservers     = ["ns1.digitalocean.com"]
+            keep_on_destroy = true
+          }
+				`,
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if nameservers := server.Nameservers("foobar.dev"); !sameNameservers(nameservers, []string{"ns1.digitalocean.com"}) {
+				return fmt.Errorf("expected the nameservers to be kept, found %v", nameservers)
+			}
+			return nil
+		},
+	})
+}
+
+func Test_RefreshNameservers(t *testing.T) {
+	r := require.New(t)
+
+	state := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Kim.NS.Cloudflare.com"), types.StringValue("bob.ns.cloudflare.com")})
+	current := []string{"Kim.NS.Cloudflare.com", "bob.ns.cloudflare.com"}
+
+	// Porkbun reporting them in another order or case isn't drift
+	r.Equal(state, refreshNameservers(state, current, []string{"bob.ns.cloudflare.com.", "kim.ns.cloudflare.com"}))
+
+	refreshed := refreshNameservers(state, current, porkbunDefaultNameservers)
+	r.Equal(addressList(porkbunDefaultNameservers), refreshed)
+}
+
+func Test_NameserversPreset(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_nameservers" "test" {
+            domain      = "foobar.dev"
+            nameservers = ["ns1.digitalocean.com"]
+            preset      = "digitalocean"
+          }
+				`,
+				ExpectError: regexp.MustCompile(`Only one of nameservers and preset can be set`),
+			},
+			{
+				Config: `
+          resource "porkbun_nameservers" "test" {
+            domain = "foobar.dev"
+            preset = "cloudflare"
+          }
+				`,
+				ExpectError: regexp.MustCompile(`"cloudflare" isn't a preset`),
+			},
+			{
+				Config: `
+          resource "porkbun_nameservers" "test" {
+            domain = "foobar.dev"
+            preset = "DigitalOcean"
+          }
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_nameservers.test", "nameservers.#", "3"),
+					func(*terraform.State) error {
+						require.Equal(t, nameserverPresets["digitalocean"], server.Nameservers("foobar.dev"))
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func Test_UnresolvableNameservers(t *testing.T) {
+	r := require.New(t)
+
+	answers := map[string][]string{
+		"kim.ns.cloudflare.com A":   {"192.0.2.1"},
+		"ns1.ipv6.example.net AAAA": {"2001:db8::1"},
+	}
+	lookup := func(ctx context.Context, resolver string, name string, recordType string) ([]string, error) {
+		if name == "broken.example.net" {
+			return nil, errors.New("i/o timeout")
+		}
+		return answers[name+" "+recordType], nil
+	}
+
+	unresolvable := unresolvableNameservers(context.Background(), lookup, "foobar.dev", []string{
+		"Kim.NS.Cloudflare.com.",
+		"ns1.ipv6.example.net",
+		"kim.ns.cloudfare.com",
+		"broken.example.net",
+		"ns1.foobar.dev",
+	})
+	// Typos are caught, while names under the domain are served by its glue records and failed lookups can't tell
+	r.Equal([]string{"kim.ns.cloudfare.com"}, unresolvable)
+}
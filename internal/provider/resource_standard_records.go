@@ -0,0 +1,655 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/reconcile"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &porkbunStandardRecordsResource{}
+var _ resource.ResourceWithConfigure = &porkbunStandardRecordsResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunStandardRecordsResource{}
+var _ resource.ResourceWithModifyPlan = &porkbunStandardRecordsResource{}
+
+// The placeholder in the content of standard records that is replaced with each domain
+const standardRecordDomainPlaceholder = "{domain}"
+
+// The state of the records of each domain, reported in status
+const (
+	standardRecordsStatusApplied = "applied"
+	standardRecordsStatusFailed  = "failed"
+	standardRecordsStatusDrifted = "drifted"
+	standardRecordsStatusRemoved = "removed"
+)
+
+func NewStandardRecordsResource() resource.Resource {
+	return &porkbunStandardRecordsResource{}
+}
+
+type porkbunStandardRecordsResource struct {
+	provider *porkbunProvider
+}
+
+type porkbunStandardRecordsResourceData struct {
+	Id        types.String            `tfsdk:"id"`
+	Label     types.String            `tfsdk:"label"`
+	Records   []porkbunStandardRecord `tfsdk:"records"`
+	Status    types.Map               `tfsdk:"status"`
+	RecordIds types.Map               `tfsdk:"record_ids"`
+}
+
+type porkbunStandardRecord struct {
+	Name    types.String `tfsdk:"name"`
+	Type    types.String `tfsdk:"type"`
+	Content types.String `tfsdk:"content"`
+	Ttl     types.String `tfsdk:"ttl"`
+	Prio    types.String `tfsdk:"prio"`
+}
+
+func (r *porkbunStandardRecordsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_standard_records"
+}
+
+func (r *porkbunStandardRecordsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Creates the same records, like SPF, DMARC or verification TXT records, on every domain of the account or every " +
+			"domain with a label. A refresh finding records changed or deleted outside of Terraform, domains added to the account or label, " +
+			"or domains that left it, plans to create the missing records and delete the ones no longer wanted. Destroying the resource " +
+			"deletes the records",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The domains the records are created on, `label:` followed by the label or `all`",
+			},
+			"label": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The title of a label in the Porkbun dashboard, the records are created on every domain carrying it. " +
+					"Defaults to every domain of the account",
+			},
+			"records": schema.ListNestedAttribute{
+				Required:            true,
+				MarkdownDescription: "The records to create on each domain",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "The subdomain of the record without the domain, like `_dmarc`, defaults to the apex",
+						},
+						"type": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "The type of the record",
+						},
+						"content": schema.StringAttribute{
+							Required: true,
+							MarkdownDescription: "The content of the record, `" + standardRecordDomainPlaceholder + "` is replaced with each domain, " +
+								"like `v=DMARC1; p=reject; rua=mailto:dmarc@" + standardRecordDomainPlaceholder + "`",
+						},
+						"ttl": schema.StringAttribute{
+							Optional: true,
+							MarkdownDescription: "The ttl of the record in seconds or as a duration like `1h`, between 600 and 86400 seconds. " +
+								"Defaults to the provider's `default_ttls` for the type, or Porkbun's default of 600",
+						},
+						"prio": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "The priority of the record, for MX and SRV records",
+						},
+					},
+				},
+			},
+			"status": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "The state of the records of each domain, keyed by domain. " +
+					"`applied` when the domain has all of them, `failed` when the last apply couldn't write them, " +
+					"`drifted` when a refresh found records missing or changed and `removed` when the domain no longer has the label",
+			},
+			"record_ids": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+				MarkdownDescription: "The Porkbun IDs of the records, keyed by domain and then by the type and name of the record, " +
+					"like `TXT _dmarc` or `TXT @` for the apex. Records with the same type and name get the position among them added " +
+					"from the second one on, like `TXT @ #2`",
+			},
+		},
+	}
+}
+
+func (r *porkbunStandardRecordsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunStandardRecordsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunStandardRecordsResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Records != nil && len(data.Records) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("records"),
+			"Missing records",
+			"At least one record is required",
+		)
+	}
+
+	for i, record := range data.Records {
+		if record.Ttl.IsNull() || record.Ttl.IsUnknown() {
+			continue
+		}
+		warning, err := validateTTL(record.Ttl.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("records").AtListIndex(i).AtName("ttl"), "Invalid TTL", err.Error())
+		} else if warning != "" {
+			resp.Diagnostics.AddAttributeWarning(path.Root("records").AtListIndex(i).AtName("ttl"), "Unusual TTL", warning)
+		}
+	}
+}
+
+// ModifyPlan plans the records to be written again when the last refresh found domains without them
+func (r *porkbunStandardRecordsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan porkbunStandardRecordsResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), standardRecordsID(plan))...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state porkbunStandardRecordsResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	var status map[string]string
+	resp.Diagnostics.Append(state.Status.ElementsAs(ctx, &status, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var pending []string
+	for domain, s := range status {
+		if s != standardRecordsStatusApplied {
+			pending = append(pending, domain)
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		tflog.Info(ctx, "Domains don't have the standard records, planning to write them again", map[string]any{"domains": pending})
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.MapUnknown(types.StringType))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("record_ids"), types.MapUnknown(types.MapType{ElemType: types.StringType}))...)
+	}
+}
+
+func (r *porkbunStandardRecordsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunStandardRecordsResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	data, diags = r.apply(ctx, data, map[string]map[string]string{})
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunStandardRecordsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunStandardRecordsResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	var previous map[string]string
+	var previousIds map[string]map[string]string
+	resp.Diagnostics.Append(data.Status.ElementsAs(ctx, &previous, false)...)
+	resp.Diagnostics.Append(data.RecordIds.ElementsAs(ctx, &previousIds, false)...)
+	domains, inAccount, diags := r.targetDomains(ctx, data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status := make(map[string]string, len(domains))
+	ids := make(map[string]map[string]string, len(domains))
+	zones, errs := retrieveZones(ctx, rt, domains, defaultAllRecordsConcurrency)
+	for i, domain := range domains {
+		if errs[i] != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Could not retrieve records for %s.", domain),
+				apiErrorDetail(errs[i]),
+			)
+			return
+		}
+		desired := standardRecords(data.Records, domain, rt)
+		ids[domain], status[domain] = refreshStandardRecords(domain, previous[domain], previousIds[domain], desired, zones[i])
+	}
+
+	// Records stay on domains that lost the label until the next apply deletes them, the ones of domains that
+	// left the account went with them
+	for domain, domainIds := range previousIds {
+		if _, ok := status[domain]; ok {
+			continue
+		}
+		if !inAccount[domain] {
+			tflog.Info(ctx, "Domain is no longer in the account, forgetting its standard records", map[string]any{"domain": domain})
+			continue
+		}
+		ids[domain] = domainIds
+		status[domain] = standardRecordsStatusRemoved
+	}
+
+	data.Status = stringMapValue(status)
+	data.RecordIds = standardRecordIdsMap(ids)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunStandardRecordsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunStandardRecordsResourceData
+	var state porkbunStandardRecordsResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	var previousIds map[string]map[string]string
+	resp.Diagnostics.Append(state.RecordIds.ElementsAs(ctx, &previousIds, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	data, diags = r.apply(ctx, data, previousIds)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunStandardRecordsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var state porkbunStandardRecordsResourceData
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	var previousIds map[string]map[string]string
+	resp.Diagnostics.Append(state.RecordIds.ElementsAs(ctx, &previousIds, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	// Retrieving the zones is what takes long on large accounts, the deletes are few and stay one at a time
+	domains := sortedKeys(previousIds)
+	retrieveZones(ctx, r.provider.runtime(), domains, defaultAllRecordsConcurrency)
+	for _, domain := range domains {
+		ctx := tflog.SetField(ctx, "domain", domain)
+		_, diags := r.syncDomain(ctx, domain, previousIds[domain], nil)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// apply writes the records to every domain data selects and deletes them from the domains in previousIds
+// that it no longer selects. Domains failing are reported as errors and in status while the others are still
+// written.
+func (r *porkbunStandardRecordsResource) apply(ctx context.Context, data porkbunStandardRecordsResourceData, previousIds map[string]map[string]string) (porkbunStandardRecordsResourceData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	data.Id = standardRecordsID(data)
+	domains, inAccount, targetDiags := r.targetDomains(ctx, data)
+	diags.Append(targetDiags...)
+	if diags.HasError() {
+		data.Status = stringMapValue(map[string]string{})
+		data.RecordIds = standardRecordIdsMap(previousIds)
+		return data, diags
+	}
+
+	// The zones are retrieved up front through the record cache, syncDomain reports the ones that failed
+	retrieveZones(ctx, rt, append(append([]string{}, domains...), sortedKeys(previousIds)...), defaultAllRecordsConcurrency)
+
+	status := make(map[string]string, len(domains))
+	ids := make(map[string]map[string]string, len(domains))
+	for _, domain := range domains {
+		ctx := tflog.SetField(ctx, "domain", domain)
+
+		written, domainDiags := r.syncDomain(ctx, domain, previousIds[domain], standardRecords(data.Records, domain, rt))
+		diags.Append(domainDiags...)
+		ids[domain] = written
+		status[domain] = standardRecordsStatusApplied
+		if domainDiags.HasError() {
+			status[domain] = standardRecordsStatusFailed
+		}
+	}
+
+	for _, domain := range sortedKeys(previousIds) {
+		if _, ok := status[domain]; ok {
+			continue
+		}
+		ctx := tflog.SetField(ctx, "domain", domain)
+		if !inAccount[domain] {
+			tflog.Info(ctx, "Domain is no longer in the account, forgetting its standard records")
+			continue
+		}
+
+		left, domainDiags := r.syncDomain(ctx, domain, previousIds[domain], nil)
+		diags.Append(domainDiags...)
+		if len(left) > 0 {
+			ids[domain] = left
+			status[domain] = standardRecordsStatusFailed
+		}
+	}
+
+	if len(domains) == 0 {
+		diags.AddWarning(
+			"No domains to create records on",
+			"No domain of the account is selected, the records are created once domains are added and the resource is refreshed",
+		)
+	}
+
+	data.Status = stringMapValue(status)
+	data.RecordIds = standardRecordIdsMap(ids)
+	return data, diags
+}
+
+// syncDomain turns the records of domain in ids, keyed by standardRecordKeys, into desired.
+// Records are created and edited before the ones no longer desired are deleted, and the IDs of the records
+// domain has afterwards are returned.
+func (r *porkbunStandardRecordsResource) syncDomain(ctx context.Context, domain string, ids map[string]string, desired []porkbunapi.Record) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	live, err := rt.records.get(ctx, domain, func() ([]porkbunapi.Record, error) {
+		return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Record, error) {
+			return rt.client.RetrieveRecords(ctx, domain)
+		})
+	})
+	if errors.Is(err, porkbunapi.ErrNotFound) && len(desired) == 0 {
+		// The domain left the account and took its records with it
+		return map[string]string{}, diags
+	}
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf("Could not retrieve records for %s.", domain),
+			apiErrorDetail(err),
+		)
+		return ids, diags
+	}
+
+	// Records deleted outside of Terraform are created again
+	current := make(map[string]string, len(ids))
+	for key, id := range ids {
+		if _, ok := live[id]; ok {
+			current[key] = id
+		}
+	}
+	result := make(map[string]string, len(current))
+	for key, id := range current {
+		result[key] = id
+	}
+
+	keys := standardRecordKeys(desired)
+	byKey := make(map[string]porkbunapi.Record, len(desired))
+	for i, key := range keys {
+		byKey[key] = desired[i]
+	}
+	plan := reconcile.Diff(current, keys, false)
+
+	// New records have no ID yet, and the kept ones are only written again when they changed
+	var writes []reconcile.Record
+	for _, key := range plan.Create {
+		writes = append(writes, reconcile.Record{Key: key})
+	}
+	for _, kept := range plan.Keep {
+		if !standardRecordMatches(live[kept.ID], domain, byKey[kept.Key]) {
+			writes = append(writes, kept)
+		}
+	}
+	for _, write := range writes {
+		record, id := byKey[write.Key], write.ID
+
+		if id != "" {
+			err = retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+				return rt.client.EditRecord(ctx, domain, id, record)
+			})
+		} else {
+			id, err = createRecord(ctx, rt, domain, record)
+		}
+		if err != nil {
+			diags.AddError(
+				fmt.Sprintf("Error writing the %s record %s of %s", record.Type, recordFQDN(record.Name, domain), domain),
+				apiErrorDetail(err),
+			)
+			continue
+		}
+		tflog.Debug(ctx, "Wrote standard record", map[string]any{"record": write.Key, "record_id": id})
+		rt.records.put(ctx, domain, writtenRecord(domain, id, record))
+		result[write.Key] = id
+	}
+	if diags.HasError() {
+		// Keep the records that are no longer wanted until the new ones are all in place
+		return result, diags
+	}
+
+	for _, stale := range plan.Delete {
+		err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+			return rt.client.DeleteRecord(ctx, domain, stale.ID)
+		})
+		err = ignoreAlreadyDeleted(ctx, "Standard record", err, nil)
+		if err != nil {
+			diags.AddError(
+				fmt.Sprintf("Error deleting standard record %s of %s", stale.ID, domain),
+				apiErrorDetail(err),
+			)
+			continue
+		}
+		tflog.Debug(ctx, "Deleted standard record", map[string]any{"record_id": stale.ID})
+		rt.records.remove(ctx, domain, stale.ID)
+		delete(result, stale.Key)
+	}
+	return result, diags
+}
+
+// targetDomains returns the domains data selects, lower case and sorted, and whether each domain of the
+// account is in it
+func (r *porkbunStandardRecordsResource) targetDomains(ctx context.Context, data porkbunStandardRecordsResourceData) ([]string, map[string]bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	rt := r.provider.runtime()
+
+	all, err := retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.Domain, error) {
+		return rt.client.ListDomains(ctx)
+	})
+	if err != nil {
+		diags.AddError(
+			"Could not list domains.",
+			apiErrorDetail(err),
+		)
+		return nil, nil, diags
+	}
+
+	inAccount := make(map[string]bool, len(all))
+	var domains []string
+	for _, domain := range all {
+		name := strings.ToLower(domain.Domain)
+		inAccount[name] = true
+		domains = append(domains, name)
+	}
+	sort.Strings(domains)
+	if !data.Label.IsNull() {
+		domains = labeledDomains(all, data.Label.ValueString())
+		tflog.Debug(ctx, "Found domains with the label", map[string]any{"label": data.Label.ValueString(), "domain_count": len(domains)})
+	}
+	return domains, inAccount, diags
+}
+
+// standardRecords are the records configured for domain, in the form of the API
+func standardRecords(configured []porkbunStandardRecord, domain string, rt *providerRuntime) []porkbunapi.Record {
+	records := make([]porkbunapi.Record, 0, len(configured))
+	for _, record := range configured {
+		records = append(records, porkbunapi.Record{
+			Name:    record.Name.ValueString(),
+			Type:    strings.ToUpper(record.Type.ValueString()),
+			Content: strings.ReplaceAll(record.Content.ValueString(), standardRecordDomainPlaceholder, domain),
+			TTL:     recordTTL(record.Ttl, record.Type.ValueString(), rt.defaultTTLs),
+			Prio:    record.Prio.ValueString(),
+			Notes:   stampNotes("", rt.managedNotesMarker),
+		})
+	}
+	return records
+}
+
+// standardRecordKeys identifies records by their type and name, so that adding or removing one in the
+// configuration doesn't move the others. The records sharing both, like several verification TXT records at
+// the apex, are told apart by their position among them.
+func standardRecordKeys(records []porkbunapi.Record) []string {
+	keys := make([]string, len(records))
+	seen := make(map[string]int, len(records))
+	for i, record := range records {
+		name := strings.ToLower(record.Name)
+		if name == "" {
+			name = "@"
+		}
+		key := strings.ToUpper(record.Type) + " " + name
+		seen[key]++
+		if seen[key] > 1 {
+			key += " #" + strconv.Itoa(seen[key])
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// standardRecordMatches reports whether live serves record as it is written to domain. A TTL or priority
+// record leaves to Porkbun's default isn't compared.
+func standardRecordMatches(live porkbunapi.Record, domain string, record porkbunapi.Record) bool {
+	if recordValueKey(live) != recordValueKey(writtenRecord(domain, live.ID, record)) {
+		return false
+	}
+	return (record.TTL == "" || live.TTL == record.TTL) && (record.Prio == "" || live.Prio == record.Prio)
+}
+
+// refreshStandardRecords compares the records domain serves with desired. It returns the IDs in ids of the
+// records still there and the status of the domain, which keeps failing until an apply succeeds.
+func refreshStandardRecords(domain string, previous string, ids map[string]string, desired []porkbunapi.Record, live map[string]porkbunapi.Record) (map[string]string, string) {
+	byKey := make(map[string]porkbunapi.Record, len(desired))
+	for i, key := range standardRecordKeys(desired) {
+		byKey[key] = desired[i]
+	}
+
+	kept := make(map[string]string, len(ids))
+	drifted := false
+	for key, id := range ids {
+		record, ok := live[id]
+		if !ok {
+			drifted = true
+			continue
+		}
+		kept[key] = id
+		if wanted, ok := byKey[key]; !ok || !standardRecordMatches(record, domain, wanted) {
+			drifted = true
+		}
+	}
+	if len(kept) < len(desired) {
+		drifted = true
+	}
+
+	switch {
+	case !drifted:
+		return kept, standardRecordsStatusApplied
+	case previous == standardRecordsStatusFailed:
+		return kept, standardRecordsStatusFailed
+	default:
+		return kept, standardRecordsStatusDrifted
+	}
+}
+
+func standardRecordsID(data porkbunStandardRecordsResourceData) types.String {
+	if data.Label.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if !data.Label.IsNull() {
+		return types.StringValue("label:" + data.Label.ValueString())
+	}
+	return types.StringValue("all")
+}
+
+func standardRecordIdsMap(ids map[string]map[string]string) types.Map {
+	elements := make(map[string]attr.Value, len(ids))
+	for domain, domainIds := range ids {
+		values := make(map[string]attr.Value, len(domainIds))
+		for key, id := range domainIds {
+			values[key] = types.StringValue(id)
+		}
+		elements[domain] = types.MapValueMust(types.StringType, values)
+	}
+	return types.MapValueMust(types.MapType{ElemType: types.StringType}, elements)
+}
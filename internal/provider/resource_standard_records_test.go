@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StandardRecords(t *testing.T) {
+	server := newTestServer(t,
+		porkbuntest.WithLabels("foobar.dev", "Mail"),
+		porkbuntest.WithLabels("other.dev", "mail"),
+		porkbuntest.WithDomain("stays.dev"),
+	)
+
+	config := func(label string) string {
+		return fmt.Sprintf(`
+          resource "porkbun_standard_records" "test" {
+            %s
+            records = [
+              {
+                type    = "TXT"
+                content = "v=spf1 include:_spf.{domain} ~all"
+              },
+              {
+                name    = "_dmarc"
+                type    = "TXT"
+                content = "v=DMARC1; p=reject; rua=mailto:dmarc@{domain}"
+              },
+            ]
+          }
+		`, label)
+	}
+	hasRecords := func(domain string) error {
+		records := server.Records(domain)
+		if len(records) != 2 {
+			return fmt.Errorf("expected 2 records on %s, found %v", domain, records)
+		}
+		require.Equal(t, "v=spf1 include:_spf."+domain+" ~all", records[0].Content)
+		require.Equal(t, "_dmarc."+domain, records[1].Name)
+		require.Equal(t, "v=DMARC1; p=reject; rua=mailto:dmarc@"+domain, records[1].Content)
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: config(`label = "mail"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "id", "label:mail"),
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "status.%", "2"),
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "status.foobar.dev", "applied"),
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "status.other.dev", "applied"),
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "record_ids.foobar.dev.%", "2"),
+					resource.TestCheckResourceAttrSet("porkbun_standard_records.test", "record_ids.foobar.dev.TXT _dmarc"),
+					func(*terraform.State) error {
+						require.Empty(t, server.Records("stays.dev"))
+						if err := hasRecords("foobar.dev"); err != nil {
+							return err
+						}
+						return hasRecords("other.dev")
+					},
+				),
+			},
+			{
+				// Deleted in the dashboard, the refresh notices and the apply creates it again
+				PreConfig: func() {
+					client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+					client.BaseURL, _ = url.Parse(server.URL)
+					require.NoError(t, client.DeleteRecord(context.Background(), "other.dev", server.Records("other.dev")[0].ID))
+				},
+				Config: config(`label = "mail"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "status.other.dev", "applied"),
+					func(*terraform.State) error {
+						records := server.Records("other.dev")
+						require.Len(t, records, 2)
+						require.Equal(t, "_dmarc.other.dev", records[0].Name)
+						require.Equal(t, "other.dev", records[1].Name)
+						return nil
+					},
+				),
+			},
+			{
+				// Without a label every domain of the account gets the records
+				Config: config(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "id", "all"),
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "status.%", "3"),
+					func(*terraform.State) error {
+						return hasRecords("stays.dev")
+					},
+				),
+			},
+			{
+				// Domains no longer selected lose the records
+				Config: config(`label = "mail"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("porkbun_standard_records.test", "status.%", "2"),
+					resource.TestCheckNoResourceAttr("porkbun_standard_records.test", "record_ids.stays.dev.%"),
+					func(*terraform.State) error {
+						require.Empty(t, server.Records("stays.dev"))
+						return nil
+					},
+				),
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			for _, domain := range []string{"foobar.dev", "other.dev", "stays.dev"} {
+				if records := server.Records(domain); len(records) != 0 {
+					return fmt.Errorf("expected the records of %s to be deleted, found %v", domain, records)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+func Test_RefreshStandardRecords(t *testing.T) {
+	r := require.New(t)
+
+	desired := []porkbunapi.Record{
+		{Type: "TXT", Content: "v=spf1 -all"},
+		{Name: "_dmarc", Type: "TXT", Content: "v=DMARC1; p=reject", TTL: "3600"},
+	}
+	live := map[string]porkbunapi.Record{
+		"1": {ID: "1", Name: "foobar.dev", Type: "TXT", Content: "v=spf1 -all", TTL: "600"},
+		"2": {ID: "2", Name: "_dmarc.foobar.dev", Type: "TXT", Content: "v=DMARC1; p=reject", TTL: "3600"},
+	}
+	ids := map[string]string{"TXT @": "1", "TXT _dmarc": "2"}
+
+	kept, status := refreshStandardRecords("foobar.dev", "applied", ids, desired, live)
+	r.Equal(ids, kept)
+	r.Equal("applied", status)
+
+	// A new domain has none of the records
+	kept, status = refreshStandardRecords("foobar.dev", "", nil, desired, live)
+	r.Empty(kept)
+	r.Equal("drifted", status)
+
+	// Records deleted outside of Terraform are forgotten, changed ones kept to be written again
+	changed := map[string]porkbunapi.Record{
+		"2": {ID: "2", Name: "_dmarc.foobar.dev", Type: "TXT", Content: "v=DMARC1; p=reject", TTL: "600"},
+	}
+	kept, status = refreshStandardRecords("foobar.dev", "applied", ids, desired, changed)
+	r.Equal(map[string]string{"TXT _dmarc": "2"}, kept)
+	r.Equal("drifted", status)
+
+	_, status = refreshStandardRecords("foobar.dev", "failed", ids, desired, changed)
+	r.Equal("failed", status)
+}
+
+func Test_StandardRecordKeys(t *testing.T) {
+	r := require.New(t)
+
+	keys := standardRecordKeys([]porkbunapi.Record{
+		{Type: "TXT", Content: "v=spf1 -all"},
+		{Name: "_DMARC", Type: "txt", Content: "v=DMARC1; p=reject"},
+		{Type: "TXT", Content: "google-site-verification=abc"},
+		{Type: "MX", Content: "mail.{domain}", Prio: "10"},
+	})
+	r.Equal([]string{"TXT @", "TXT _dmarc", "TXT @ #2", "MX @"}, keys)
+}
@@ -0,0 +1,472 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &porkbunUrlForwardResource{}
+var _ resource.ResourceWithConfigure = &porkbunUrlForwardResource{}
+var _ resource.ResourceWithImportState = &porkbunUrlForwardResource{}
+var _ resource.ResourceWithValidateConfig = &porkbunUrlForwardResource{}
+
+// The redirects Porkbun serves, temporary ones answer with a 302 and permanent ones with a 301
+var urlForwardTypes = []string{"temporary", "permanent"}
+
+func NewUrlForwardResource() resource.Resource {
+	return &porkbunUrlForwardResource{}
+}
+
+type porkbunUrlForwardResource struct {
+	provider *porkbunProvider
+}
+
+type porkbunUrlForwardResourceData struct {
+	Id          types.String `tfsdk:"id"`
+	Domain      types.String `tfsdk:"domain"`
+	Subdomain   types.String `tfsdk:"subdomain"`
+	Location    types.String `tfsdk:"location"`
+	Type        types.String `tfsdk:"type"`
+	IncludePath types.Bool   `tfsdk:"include_path"`
+	Wildcard    types.Bool   `tfsdk:"wildcard"`
+}
+
+func (r *porkbunUrlForwardResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_url_forward"
+}
+
+func (r *porkbunUrlForwardResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Redirects a domain or subdomain to another URL with Porkbun's URL forwarding. " +
+			"The API can't edit forwards, so changing any attribute replaces the forward. Importing takes `domain/id` or `domain/subdomain`, with `@` for the domain itself",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The Porkbun ID of the forward",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The base domain to forward from",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subdomain": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The subdomain to forward without the base domain, defaults to the apex",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"location": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The URL to redirect to, like `https://example.com/blog`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "`temporary` for a 302 redirect or `permanent` for a 301",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"include_path": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Append the path of the request to `location`, so `/about` redirects to `location/about`",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"wildcard": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Also forward every subdomain below `subdomain`",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *porkbunUrlForwardResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// ProviderData is nil until the provider has been configured
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, diags := convertProviderType(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+
+	r.provider = provider
+}
+
+func (r *porkbunUrlForwardResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data porkbunUrlForwardResourceData
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Type.IsNull() && !data.Type.IsUnknown() && !isUrlForwardType(data.Type.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid forward type",
+			fmt.Sprintf("%q isn't one of %s", data.Type.ValueString(), strings.Join(urlForwardTypes, ", ")),
+		)
+	}
+
+	if !data.Location.IsNull() && !data.Location.IsUnknown() && !strings.Contains(data.Location.ValueString(), "://") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("location"),
+			"Invalid location",
+			fmt.Sprintf("%q isn't a URL, locations start with a scheme like https://", data.Location.ValueString()),
+		)
+	}
+}
+
+func (r *porkbunUrlForwardResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunUrlForwardResourceData
+	rt := r.provider.runtime()
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = urlForwardLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domain := data.Domain.ValueString()
+	forward := urlForward(data)
+
+	// The API doesn't return the ID of a new forward, it is the one that wasn't there before
+	before, err := r.forwards(ctx, domain)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve URL forwards for %s.", domain),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	err = retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		err := rt.client.AddURLForward(ctx, domain, forward)
+		if err == nil || !ambiguousError(err) {
+			return err
+		}
+
+		// Adding isn't idempotent, a forward added by an attempt whose response got lost isn't added again
+		forwards, listErr := rt.client.GetURLForwards(ctx, domain)
+		if listErr != nil {
+			tflog.Debug(ctx, "Unable to check whether the forward was added", map[string]any{"error": listErr.Error()})
+			return err
+		}
+		if added, ok := newUrlForward(before, forwards, forward); ok {
+			tflog.Warn(ctx, "Adding the forward failed but it exists, adopting it", map[string]any{"id": added.ID, "error": err.Error()})
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating URL forward",
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	after, err := r.forwards(ctx, domain)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve URL forwards for %s.", domain),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	created, ok := newUrlForward(before, after, forward)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"URL forward not found",
+			fmt.Sprintf("Porkbun accepted the forward of %s but doesn't list it, import it once it shows up in the dashboard.", urlForwardHost(forward, domain)),
+		)
+		return
+	}
+	data.Id = types.StringValue(created.ID)
+	tflog.Debug(ctx, "Created URL forward", map[string]any{"id": created.ID})
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunUrlForwardResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var data porkbunUrlForwardResourceData
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = urlForwardLogFields(ctx, data)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	forwards, err := r.forwards(ctx, data.Domain.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve URL forwards for %s.", data.Domain.ValueString()),
+			apiErrorDetail(err),
+		)
+		return
+	}
+
+	forward, ok := findUrlForward(forwards, data.Id.ValueString())
+	if !ok {
+		tflog.Warn(ctx, "URL forward no longer exists, removing it from state")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data = refreshUrlForward(data, forward)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update only copies the plan into state, every attribute Porkbun stores replaces the forward when it changes
+func (r *porkbunUrlForwardResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data porkbunUrlForwardResourceData
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *porkbunUrlForwardResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	var state porkbunUrlForwardResourceData
+	rt := r.provider.runtime()
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = urlForwardLogFields(ctx, state)
+	ctx, diags = withProviderMeta(ctx, req.ProviderMeta)
+	resp.Diagnostics.Append(diags...)
+
+	domain := state.Domain.ValueString()
+	err := retrySingleReturn(ctx, rt.retries, func(ctx context.Context) error {
+		return rt.client.DeleteURLForward(ctx, domain, state.Id.ValueString())
+	})
+	err = ignoreAlreadyDeleted(ctx, "URL forward", err, func(ctx context.Context) (bool, error) {
+		forwards, err := r.forwards(ctx, domain)
+		_, ok := findUrlForward(forwards, state.Id.ValueString())
+		return ok, err
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting URL forward",
+			apiErrorDetail(err),
+		)
+		return
+	}
+	tflog.Debug(ctx, "Deleted URL forward")
+}
+
+// ImportState takes domain/id or domain/subdomain, as the dashboard doesn't show the IDs of forwards. The
+// forward of the domain itself is imported with @ as the subdomain.
+func (r *porkbunUrlForwardResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx, stats := withRetryStats(ctx)
+	defer func() { resp.Diagnostics.Append(throttlingDiagnostics(stats)...) }()
+
+	domain, ref, ok := strings.Cut(req.ID, "/")
+	if !ok || domain == "" || ref == "" {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			fmt.Sprintf("%q isn't domain/id or domain/subdomain, like example.com/12345 or example.com/blog", req.ID),
+		)
+		return
+	}
+	if err := validateImportDomain(domain); err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("invalid import ID %q: %s", req.ID, err))
+		return
+	}
+	domain = strings.ToLower(domain)
+
+	forwards, err := r.forwards(ctx, domain)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Could not retrieve URL forwards for %s.", domain),
+			apiErrorDetail(err),
+		)
+		return
+	}
+	forward, err := importedUrlForward(forwards, ref)
+	if err != nil {
+		resp.Diagnostics.AddError("URL forward not found", fmt.Sprintf("invalid import ID %q: %s", req.ID, err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), forward.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("domain"), domain)...)
+}
+
+func (r *porkbunUrlForwardResource) forwards(ctx context.Context, domain string) ([]porkbunapi.URLForward, error) {
+	rt := r.provider.runtime()
+	return retry(ctx, rt.retries, func(ctx context.Context) ([]porkbunapi.URLForward, error) {
+		return rt.client.GetURLForwards(ctx, domain)
+	})
+}
+
+func urlForwardLogFields(ctx context.Context, data porkbunUrlForwardResourceData) context.Context {
+	return tflog.SetField(ctx, "forward", urlForwardHost(urlForward(data), data.Domain.ValueString()))
+}
+
+func isUrlForwardType(forwardType string) bool {
+	for _, t := range urlForwardTypes {
+		if t == forwardType {
+			return true
+		}
+	}
+	return false
+}
+
+// urlForward is the forward data describes in the form of the API
+func urlForward(data porkbunUrlForwardResourceData) porkbunapi.URLForward {
+	return porkbunapi.URLForward{
+		ID:          data.Id.ValueString(),
+		Subdomain:   data.Subdomain.ValueString(),
+		Location:    data.Location.ValueString(),
+		Type:        data.Type.ValueString(),
+		IncludePath: yesNo(data.IncludePath.ValueBool()),
+		Wildcard:    yesNo(data.Wildcard.ValueBool()),
+	}
+}
+
+// urlForwardHost is the name forward redirects from
+func urlForwardHost(forward porkbunapi.URLForward, domain string) string {
+	if forward.Subdomain == "" {
+		return domain
+	}
+	return forward.Subdomain + "." + domain
+}
+
+func findUrlForward(forwards []porkbunapi.URLForward, id string) (porkbunapi.URLForward, bool) {
+	for _, forward := range forwards {
+		if forward.ID == id {
+			return forward, true
+		}
+	}
+	return porkbunapi.URLForward{}, false
+}
+
+// importedUrlForward finds the forward ref of an import ID names, by its ID or else by its subdomain
+func importedUrlForward(forwards []porkbunapi.URLForward, ref string) (porkbunapi.URLForward, error) {
+	if forward, ok := findUrlForward(forwards, ref); ok {
+		return forward, nil
+	}
+
+	subdomain := ref
+	if subdomain == "@" {
+		subdomain = ""
+	}
+	var matches []porkbunapi.URLForward
+	for _, forward := range forwards {
+		if strings.EqualFold(forward.Subdomain, subdomain) {
+			matches = append(matches, forward)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return porkbunapi.URLForward{}, fmt.Errorf("the domain has no forward with the ID or subdomain %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return porkbunapi.URLForward{}, fmt.Errorf("the domain has %d forwards of the subdomain %q, import one of them by its ID", len(matches), ref)
+	}
+}
+
+// newUrlForward finds the forward listed in after but not in before that matches forward
+func newUrlForward(before []porkbunapi.URLForward, after []porkbunapi.URLForward, forward porkbunapi.URLForward) (porkbunapi.URLForward, bool) {
+	for _, candidate := range after {
+		if _, existed := findUrlForward(before, candidate.ID); existed {
+			continue
+		}
+		if strings.EqualFold(candidate.Subdomain, forward.Subdomain) && candidate.Location == forward.Location {
+			return candidate, true
+		}
+	}
+	return porkbunapi.URLForward{}, false
+}
+
+// refreshUrlForward copies what Porkbun serves into data. Attributes left out of the config stay null while
+// Porkbun reports their default, so imported forwards match configs that don't set them.
+func refreshUrlForward(data porkbunUrlForwardResourceData, forward porkbunapi.URLForward) porkbunUrlForwardResourceData {
+	data.Location = types.StringValue(forward.Location)
+	data.Type = types.StringValue(forward.Type)
+	if forward.Subdomain != "" || !data.Subdomain.IsNull() {
+		data.Subdomain = types.StringValue(forward.Subdomain)
+	}
+	data.IncludePath = refreshYesNo(data.IncludePath, forward.IncludePath)
+	data.Wildcard = refreshYesNo(data.Wildcard, forward.Wildcard)
+	return data
+}
+
+func refreshYesNo(current types.Bool, live string) types.Bool {
+	enabled := strings.EqualFold(live, "yes")
+	if current.IsNull() && !enabled {
+		return current
+	}
+	return types.BoolValue(enabled)
+}
+
+func yesNo(enabled bool) string {
+	if enabled {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UrlForward(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_url_forward" "test" {
+            domain       = "foobar.dev"
+            subdomain    = "blog"
+            location     = "https://example.com/blog"
+            type         = "permanent"
+            include_path = true
+          }
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("porkbun_url_forward.test", "id"),
+					func(*terraform.State) error {
+						forwards := server.URLForwards("foobar.dev")
+						require.Len(t, forwards, 1)
+						require.Equal(t, "blog", forwards[0].Subdomain)
+						require.Equal(t, "https://example.com/blog", forwards[0].Location)
+						require.Equal(t, "permanent", forwards[0].Type)
+						require.Equal(t, "yes", forwards[0].IncludePath)
+						require.Equal(t, "no", forwards[0].Wildcard)
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "porkbun_url_forward.test",
+				ImportState:       true,
+				ImportStateIdFunc: importStateIdFunc("porkbun_url_forward.test"),
+				ImportStateVerify: true,
+			},
+			{
+				// The dashboard doesn't show forward IDs, the subdomain finds the forward as well
+				ResourceName:      "porkbun_url_forward.test",
+				ImportState:       true,
+				ImportStateId:     "foobar.dev/blog",
+				ImportStateVerify: true,
+			},
+			{
+				// Forwards can't be edited, a new location replaces the forward
+				Config: `
+          resource "porkbun_url_forward" "test" {
+            domain       = "foobar.dev"
+            subdomain    = "blog"
+            location     = "https://example.net/blog"
+            type         = "permanent"
+            include_path = true
+          }
+				`,
+				Check: func(*terraform.State) error {
+					forwards := server.URLForwards("foobar.dev")
+					require.Len(t, forwards, 1)
+					require.Equal(t, "https://example.net/blog", forwards[0].Location)
+					return nil
+				},
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if forwards := server.URLForwards("foobar.dev"); len(forwards) != 0 {
+				return fmt.Errorf("expected all forwards to be deleted, found %v", forwards)
+			}
+			return nil
+		},
+	})
+}
+
+func Test_UrlForwardInvalidType(t *testing.T) {
+	server := newTestServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(server.URL),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+          resource "porkbun_url_forward" "test" {
+            domain   = "foobar.dev"
+            location = "https://example.com"
+            type     = "301"
+          }
+				`,
+				ExpectError: regexp.MustCompile(`Invalid forward type`),
+			},
+		},
+	})
+}
+
+func Test_RefreshUrlForward(t *testing.T) {
+	r := require.New(t)
+
+	data := porkbunUrlForwardResourceData{
+		Id:          types.StringValue("1"),
+		Domain:      types.StringValue("foobar.dev"),
+		Subdomain:   types.StringNull(),
+		Location:    types.StringValue("https://example.com"),
+		Type:        types.StringValue("temporary"),
+		IncludePath: types.BoolNull(),
+		Wildcard:    types.BoolValue(false),
+	}
+
+	// Defaults Porkbun reports for attributes left out of the config don't show up as drift
+	refreshed := refreshUrlForward(data, porkbunapi.URLForward{ID: "1", Location: "https://example.com", Type: "temporary", IncludePath: "no", Wildcard: "no"})
+	r.Equal(data, refreshed)
+
+	refreshed = refreshUrlForward(data, porkbunapi.URLForward{ID: "1", Subdomain: "www", Location: "https://example.net", Type: "permanent", IncludePath: "yes", Wildcard: "yes"})
+	r.Equal(types.StringValue("www"), refreshed.Subdomain)
+	r.Equal(types.StringValue("https://example.net"), refreshed.Location)
+	r.Equal(types.StringValue("permanent"), refreshed.Type)
+	r.Equal(types.BoolValue(true), refreshed.IncludePath)
+	r.Equal(types.BoolValue(true), refreshed.Wildcard)
+}
+
+func Test_NewUrlForward(t *testing.T) {
+	r := require.New(t)
+
+	forward := porkbunapi.URLForward{Subdomain: "blog", Location: "https://example.com"}
+	before := []porkbunapi.URLForward{{ID: "1", Subdomain: "blog", Location: "https://example.com"}}
+	after := append(before, porkbunapi.URLForward{ID: "2", Subdomain: "shop", Location: "https://example.com"}, porkbunapi.URLForward{ID: "3", Subdomain: "Blog", Location: "https://example.com"})
+
+	created, ok := newUrlForward(before, after, forward)
+	r.True(ok)
+	r.Equal("3", created.ID)
+
+	_, ok = newUrlForward(after, after, forward)
+	r.False(ok)
+}
+
+func Test_ImportedUrlForward(t *testing.T) {
+	r := require.New(t)
+
+	forwards := []porkbunapi.URLForward{
+		{ID: "1", Location: "https://example.com"},
+		{ID: "2", Subdomain: "Blog", Location: "https://example.com/blog"},
+		{ID: "3", Subdomain: "shop", Location: "https://example.com/shop"},
+		{ID: "4", Subdomain: "shop", Location: "https://example.net/shop"},
+	}
+
+	forward, err := importedUrlForward(forwards, "2")
+	r.NoError(err)
+	r.Equal("2", forward.ID)
+
+	forward, err = importedUrlForward(forwards, "blog")
+	r.NoError(err)
+	r.Equal("2", forward.ID)
+
+	forward, err = importedUrlForward(forwards, "@")
+	r.NoError(err)
+	r.Equal("1", forward.ID)
+
+	_, err = importedUrlForward(forwards, "www")
+	r.ErrorContains(err, "no forward")
+
+	_, err = importedUrlForward(forwards, "shop")
+	r.ErrorContains(err, "import one of them by its ID")
+}
+
+// lostForwardClient adds forwards but fails as if the response never arrived
+type lostForwardClient struct {
+	*fakeClient
+}
+
+func (c lostForwardClient) AddURLForward(ctx context.Context, domain string, forward porkbunapi.URLForward) error {
+	if err := c.fakeClient.AddURLForward(ctx, domain, forward); err != nil {
+		return err
+	}
+	return errors.New("context deadline exceeded")
+}
+
+func Test_UrlForwardAdoptsAfterLostResponse(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	fake := newFakeClient("foobar.dev")
+	res := &porkbunUrlForwardResource{provider: newUnitRecordResource(lostForwardClient{fake}).provider}
+
+	var schemaResp fwresource.SchemaResponse
+	res.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	plan := tfsdk.Plan{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	r.False(plan.Set(ctx, &porkbunUrlForwardResourceData{
+		Id:          types.StringUnknown(),
+		Domain:      types.StringValue("foobar.dev"),
+		Subdomain:   types.StringValue("blog"),
+		Location:    types.StringValue("https://example.com/blog"),
+		Type:        types.StringValue("temporary"),
+		IncludePath: types.BoolNull(),
+		Wildcard:    types.BoolNull(),
+	}).HasError())
+
+	// The forward was added with only the response lost, it is adopted instead of being added twice
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: plan.Schema, Raw: plan.Raw}}
+	res.Create(ctx, fwresource.CreateRequest{Plan: plan}, &resp)
+	r.False(resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	forwards, err := fake.GetURLForwards(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Len(forwards, 1)
+
+	var data porkbunUrlForwardResourceData
+	r.False(resp.State.Get(ctx, &data).HasError())
+	r.Equal(forwards[0].ID, data.Id.ValueString())
+}
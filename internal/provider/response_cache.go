@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// How long a cached response is served, long enough for the apply following a plan and short enough that a
+// handle reused by accident doesn't serve stale data for long
+const responseCacheTTL = 15 * time.Minute
+
+// Endpoints whose responses are cached, they only read data that no resource of the provider changes
+var cachedEndpoints = []string{"pricing/get", "domain/listAll"}
+
+var responseCacheHandlePattern = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type cachedResponse struct {
+	StoredAt    time.Time `json:"stored_at"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        string    `json:"body"`
+}
+
+// responseCacheTransport keeps successful responses of cachedEndpoints on disk, so the provider process
+// running the apply reuses what the process running the plan retrieved. Entries are keyed by the request
+// body, which carries the API keys, so other credentials never see them.
+type responseCacheTransport struct {
+	dir  string
+	ttl  time.Duration
+	now  func() time.Time
+	next http.RoundTripper
+}
+
+// useResponseCache puts a response cache in front of the logging of client, which must come from
+// newHTTPClient, so cached responses aren't counted as API calls. handle names the Terraform run the cache
+// belongs to, runs with another handle get a cache of their own.
+func useResponseCache(client *http.Client, handle string) error {
+	limited, ok := client.Transport.(*limitedTransport)
+	if !ok {
+		return fmt.Errorf("unexpected transport %T", client.Transport)
+	}
+	traced, ok := limited.next.(*tracingTransport)
+	if !ok {
+		return fmt.Errorf("unexpected transport %T", limited.next)
+	}
+
+	cache, err := newResponseCacheTransport(responseCacheDir(handle), traced.next)
+	if err != nil {
+		return err
+	}
+	traced.next = cache
+	return nil
+}
+
+// responseCacheDir is where the cache of handle lives, under the temporary directory so it is cleaned up
+// with it
+func responseCacheDir(handle string) string {
+	name := responseCacheHandlePattern.ReplaceAllString(handle, "_")
+	return filepath.Join(os.TempDir(), "terraform-provider-porkbun-cache", name)
+}
+
+func newResponseCacheTransport(dir string, next http.RoundTripper) (*responseCacheTransport, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating response cache: %w", err)
+	}
+	return &responseCacheTransport{
+		dir:  dir,
+		ttl:  responseCacheTTL,
+		now:  time.Now,
+		next: next,
+	}, nil
+}
+
+func (t *responseCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isCachedEndpoint(req.URL.Path) || req.Body == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	sum := sha256.Sum256(append([]byte(req.URL.Path+"\n"), body...))
+	path := filepath.Join(t.dir, hex.EncodeToString(sum[:])+".json")
+
+	if cached, ok := t.load(path); ok {
+		header := http.Header{}
+		if cached.ContentType != "" {
+			header.Set("Content-Type", cached.ContentType)
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(cached.Body)),
+			ContentLength: int64(len(cached.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	// Errors reported with a 200 aren't worth keeping
+	var status struct {
+		Status string `json:"status"`
+	}
+	if json.Unmarshal(respBody, &status) == nil && status.Status == "SUCCESS" {
+		t.store(path, cachedResponse{
+			StoredAt:    t.now(),
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        string(respBody),
+		})
+	}
+	return resp, nil
+}
+
+// load returns the entry at path unless it is missing, unreadable or expired
+func (t *responseCacheTransport) load(path string) (cachedResponse, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return cachedResponse{}, false
+	}
+	if t.now().Sub(cached.StoredAt) > t.ttl {
+		return cachedResponse{}, false
+	}
+	return cached, true
+}
+
+// store writes the entry to path, a cache that can't be written only costs the calls it would have saved
+func (t *responseCacheTransport) store(path string, cached cachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	// Written next to the entry and renamed, so a provider process reading it at the same time never sees half of it
+	tmp, err := os.CreateTemp(t.dir, "entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// isCachedEndpoint reports whether path ends in one of cachedEndpoints, whatever it is served under
+func isCachedEndpoint(path string) bool {
+	for _, endpoint := range cachedEndpoints {
+		if strings.HasSuffix(strings.TrimSuffix(path, "/"), "/"+endpoint) {
+			return true
+		}
+	}
+	return false
+}
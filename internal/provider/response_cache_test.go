@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachingClient(t *testing.T, baseUrl string, handle string) *porkbunapi.Client {
+	client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	client.BaseURL, _ = url.Parse(baseUrl)
+	client.HTTPClient = newHTTPClient(defaultMaxResponseBytes)
+	require.NoError(t, useResponseCache(client.HTTPClient, handle))
+	return client
+}
+
+func Test_ResponseCacheSharedWithinRun(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	t.Setenv("TMPDIR", t.TempDir())
+
+	server := porkbuntest.NewServer(porkbuntest.WithDomain("foobar.dev"))
+	t.Cleanup(server.Close)
+
+	// Plan and apply use clients of their own, like the provider processes Terraform starts for them
+	plan := newCachingClient(t, server.URL, "run-1")
+	apply := newCachingClient(t, server.URL, "run-1")
+
+	planned, err := plan.GetPricing(ctx)
+	r.NoError(err)
+	applied, err := apply.GetPricing(ctx)
+	r.NoError(err)
+	r.Equal(planned, applied)
+	r.Equal(1, server.Calls("pricing/get"))
+
+	_, err = plan.ListDomains(ctx)
+	r.NoError(err)
+	domains, err := apply.ListDomains(ctx)
+	r.NoError(err)
+	r.Len(domains, 1)
+	r.Equal(1, server.Calls("domain/listAll"))
+
+	// Records are changed by the apply, they are never cached
+	_, err = plan.RetrieveRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	_, err = apply.RetrieveRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Equal(2, server.Calls("dns/retrieve"))
+
+	// Another run doesn't see the responses of this one
+	_, err = newCachingClient(t, server.URL, "run-2").GetPricing(ctx)
+	r.NoError(err)
+	r.Equal(2, server.Calls("pricing/get"))
+}
+
+func Test_ResponseCacheExpires(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	server := porkbuntest.NewServer()
+	t.Cleanup(server.Close)
+
+	cache, err := newResponseCacheTransport(t.TempDir(), nil)
+	r.NoError(err)
+	client := porkbunapi.New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	client.BaseURL, _ = url.Parse(server.URL)
+	client.HTTPClient = newHTTPClient(defaultMaxResponseBytes)
+	traced := client.HTTPClient.Transport.(*limitedTransport).next.(*tracingTransport)
+	cache.next = traced.next
+	traced.next = cache
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_, err = client.GetPricing(ctx)
+	r.NoError(err)
+	now = now.Add(responseCacheTTL - time.Second)
+	_, err = client.GetPricing(ctx)
+	r.NoError(err)
+	r.Equal(1, server.Calls("pricing/get"))
+
+	now = now.Add(2 * time.Second)
+	_, err = client.GetPricing(ctx)
+	r.NoError(err)
+	r.Equal(2, server.Calls("pricing/get"))
+}
+
+func Test_IsCachedEndpoint(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isCachedEndpoint("/api/json/v3/pricing/get"))
+	r.True(isCachedEndpoint("/domain/listAll/"))
+	r.False(isCachedEndpoint("/api/json/v3/dns/retrieve/foobar.dev"))
+	r.False(isCachedEndpoint("/api/json/v3/domain/listAllTheThings"))
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Unless configured, API calls are tried this often and wait this long before the first retry
+const (
+	defaultRetryAttempts = 10
+	defaultRetryWait     = 10 * time.Second
+)
+
+// The credentials check while configuring gives up after this long
+const credentialsValidationTimeout = 10 * time.Second
+
+// retryPolicy is how retry handles failed API calls: they are tried attempts times in total, waiting wait
+// before the first retry and twice as long before each one after it
+type retryPolicy struct {
+	attempts int
+	wait     time.Duration
+}
+
+// providerRuntime is what Configure sets up for resources, data sources and list resources to share.
+// Terraform runs their operations in parallel and may configure the same provider instance again while
+// they hold on to it, like the acceptance tests do between steps. A runtime is therefore never modified
+// once it has been published: Configure builds a new one and swaps it in, and the parts that do change
+// during a run, like the record cache, synchronize themselves.
+type providerRuntime struct {
+	client  porkbunClient
+	retries retryPolicy
+
+	// checkLiveDNS makes planned records get looked up in public DNS, see porkbunDnsRecordResource.ModifyPlan
+	checkLiveDNS bool
+	// protectCriticalRecords makes plans changing MX, NS and apex address records fail, see criticalRecordDiagnostics
+	protectCriticalRecords bool
+	// suggestImports makes new records warn about live records they likely were meant to take over, see adoptableRecords
+	suggestImports bool
+	// managedNotesMarker is stamped into the notes of written records and required on the records that are
+	// updated or deleted, see managedRecordDiagnostics
+	managedNotesMarker string
+	// notesAudit makes written records carry an entry of when and by which run they were written in their
+	// notes, nil unless notes_audit_trail is set. See appendNotesAudit.
+	notesAudit *notesAuditTrail
+	// defaultTTLs holds the TTL in seconds of records that don't set one, keyed by upper case record type
+	defaultTTLs map[string]string
+
+	// records is shared by every resource instance created from this provider
+	records *recordCache
+	// usage counts the requests of client, see porkbun_api_usage
+	usage *apiUsage
+}
+
+// runtimeHolder publishes the current runtime of a provider
+type runtimeHolder struct {
+	current atomic.Pointer[providerRuntime]
+}
+
+// runtime returns the runtime of the last Configure. Operations should load it once and keep using the
+// same one, so a concurrent Configure can't mix two configurations in a single operation.
+func (h *runtimeHolder) runtime() *providerRuntime {
+	if rt := h.current.Load(); rt != nil {
+		return rt
+	}
+	// Not configured yet, Terraform still validates and plans with an unconfigured provider
+	return &providerRuntime{records: newRecordCache()}
+}
+
+func (h *runtimeHolder) setRuntime(rt *providerRuntime) {
+	h.current.Store(rt)
+}
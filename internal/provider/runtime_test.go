@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with -race, operations keep going while the provider is configured again underneath them
+func Test_RuntimeSwapDuringOperations(t *testing.T) {
+	var holder runtimeHolder
+	holder.setRuntime(&providerRuntime{records: newRecordCache()})
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 200; i++ {
+			holder.setRuntime(&providerRuntime{retries: retryPolicy{attempts: i}, records: newRecordCache()})
+		}
+		close(stop)
+	}()
+
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				rt := holder.runtime()
+				records, err := rt.records.get(context.Background(), "foobar.dev", func() ([]porkbunapi.Record, error) {
+					return []porkbunapi.Record{{ID: strconv.Itoa(rt.retries.attempts)}}, nil
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				// Every runtime has its own cache, so an operation never sees records of another configuration
+				if _, ok := records[strconv.Itoa(rt.retries.attempts)]; !ok || len(records) != 1 {
+					t.Errorf("runtime with %d retries got records %v", rt.retries.attempts, records)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 200, holder.runtime().retries.attempts)
+}
+
+func Test_RuntimeBeforeConfigure(t *testing.T) {
+	r := require.New(t)
+
+	p := New("test")().(*porkbunProvider)
+	rt := p.runtime()
+	r.Nil(rt.client)
+	r.NotNil(rt.records)
+	r.False(rt.checkLiveDNS)
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var spfDomainPattern = regexp.MustCompile(`^([a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?\.)+[a-zA-Z]{2,63}\.?$`)
+
+var spfAllQualifiers = map[string]bool{
+	"+all": true,
+	"-all": true,
+	"~all": true,
+	"?all": true,
+}
+
+// Receivers give up on SPF records that take more DNS lookups than this, RFC 7208 section 4.6.4
+const spfLookupLimit = 10
+
+// spfTermLookups is how many DNS lookups a term of an SPF record takes, counting the records it includes
+type spfTermLookups struct {
+	Term    string
+	Lookups int
+}
+
+// isSPF reports whether content is an SPF policy
+func isSPF(content string) bool {
+	fields := strings.Fields(strings.ToLower(content))
+	return len(fields) > 0 && fields[0] == "v=spf1"
+}
+
+// countSPFLookups counts the DNS lookups evaluating an SPF record takes, resolving include and redirect
+// targets through lookup to count theirs as well. The terms costing lookups are returned in the order
+// they appear in the record.
+func countSPFLookups(ctx context.Context, lookup dnsLookupFunc, resolver string, record string) ([]spfTermLookups, error) {
+	return spfRecordLookups(ctx, lookup, resolver, record, nil)
+}
+
+func spfRecordLookups(ctx context.Context, lookup dnsLookupFunc, resolver string, record string, including []string) ([]spfTermLookups, error) {
+	var terms []spfTermLookups
+	for _, term := range strings.Fields(record)[1:] {
+		name, target, _ := strings.Cut(strings.TrimLeft(strings.ToLower(term), "+-~?"), ":")
+		if name == "redirect" || strings.HasPrefix(name, "redirect=") {
+			name, target, _ = strings.Cut(name, "=")
+		}
+		// a and mx take a CIDR length like a/24 or mx:mail.foobar.dev/24//64, they are looked up all the same
+		name, _, _ = strings.Cut(name, "/")
+
+		switch name {
+		case "a", "mx", "ptr", "exists":
+			terms = append(terms, spfTermLookups{Term: term, Lookups: 1})
+		case "include", "redirect":
+			lookups := 1
+			// Targets built from macros depend on the sender and can't be followed ahead of time
+			if !strings.Contains(target, "%") {
+				included, err := includedSPFLookups(ctx, lookup, resolver, target, including)
+				if err != nil {
+					return nil, err
+				}
+				lookups += included
+			}
+			terms = append(terms, spfTermLookups{Term: term, Lookups: lookups})
+		}
+	}
+	return terms, nil
+}
+
+func includedSPFLookups(ctx context.Context, lookup dnsLookupFunc, resolver string, domain string, including []string) (int, error) {
+	domain = normalizeDnsValue(domain)
+	for _, parent := range including {
+		if parent == domain {
+			return 0, fmt.Errorf("%s includes itself", domain)
+		}
+	}
+
+	values, err := lookup(ctx, resolver, domain, "TXT")
+	if err != nil {
+		return 0, fmt.Errorf("looking up the SPF record of %s: %w", domain, err)
+	}
+	var records []string
+	for _, value := range values {
+		if isSPF(value) {
+			records = append(records, value)
+		}
+	}
+	if len(records) != 1 {
+		return 0, fmt.Errorf("%s has %d SPF records, expected exactly one", domain, len(records))
+	}
+
+	terms, err := spfRecordLookups(ctx, lookup, resolver, records[0], append(including[:len(including):len(including)], domain))
+	if err != nil {
+		return 0, err
+	}
+	lookups := 0
+	for _, term := range terms {
+		lookups += term.Lookups
+	}
+	return lookups, nil
+}
+
+// buildSPF assembles a v=spf1 record from its mechanisms. all may be empty to leave the
+// record without a default result.
+func buildSPF(includes []string, ip4 []string, ip6 []string, all string) (string, error) {
+	mechanisms := []string{"v=spf1"}
+
+	for _, address := range ip4 {
+		if !isSPFAddress(address, false) {
+			return "", fmt.Errorf("%q is not an IPv4 address or network", address)
+		}
+		mechanisms = append(mechanisms, "ip4:"+address)
+	}
+
+	for _, address := range ip6 {
+		if !isSPFAddress(address, true) {
+			return "", fmt.Errorf("%q is not an IPv6 address or network", address)
+		}
+		mechanisms = append(mechanisms, "ip6:"+address)
+	}
+
+	for _, include := range includes {
+		if !spfDomainPattern.MatchString(include) {
+			return "", fmt.Errorf("%q is not a valid domain for an include", include)
+		}
+		mechanisms = append(mechanisms, "include:"+include)
+	}
+
+	if all != "" {
+		if !spfAllQualifiers[all] {
+			return "", fmt.Errorf("all must be one of +all, -all, ~all or ?all, got %q", all)
+		}
+		mechanisms = append(mechanisms, all)
+	}
+
+	return strings.Join(mechanisms, " "), nil
+}
+
+func isSPFAddress(address string, v6 bool) bool {
+	ip := net.ParseIP(address)
+	if strings.Contains(address, "/") {
+		var err error
+		ip, _, err = net.ParseCIDR(address)
+		if err != nil {
+			return false
+		}
+	}
+	if ip == nil {
+		return false
+	}
+	return (ip.To4() == nil) == v6
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildSPF(t *testing.T) {
+	r := require.New(t)
+
+	spf, err := buildSPF(
+		[]string{"_spf.google.com", "spf.protection.outlook.com"},
+		[]string{"192.0.2.1", "198.51.100.0/24"},
+		[]string{"2001:db8::/32"},
+		"~all",
+	)
+	r.NoError(err)
+	r.Equal("v=spf1 ip4:192.0.2.1 ip4:198.51.100.0/24 ip6:2001:db8::/32 include:_spf.google.com include:spf.protection.outlook.com ~all", spf)
+
+	spf, err = buildSPF(nil, nil, nil, "")
+	r.NoError(err)
+	r.Equal("v=spf1", spf)
+}
+
+func Test_BuildSPFErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		includes []string
+		ip4      []string
+		ip6      []string
+		all      string
+	}{
+		{name: "ip6InIp4", ip4: []string{"2001:db8::1"}},
+		{name: "ip4InIp6", ip6: []string{"192.0.2.1"}},
+		{name: "badCidr", ip4: []string{"192.0.2.0/33"}},
+		{name: "notAnAddress", ip4: []string{"mail.example.com"}},
+		{name: "badInclude", includes: []string{"include:example.com"}},
+		{name: "badAll", all: "all"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := buildSPF(test.includes, test.ip4, test.ip6, test.all)
+			require.Error(t, err)
+		})
+	}
+}
+
+// spfZone answers TXT lookups from a map of names to their records
+type spfZone map[string][]string
+
+func (z spfZone) lookup(ctx context.Context, resolver string, name string, recordType string) ([]string, error) {
+	if name == "down.foobar.dev" {
+		return nil, fmt.Errorf("i/o timeout")
+	}
+	return z[name], nil
+}
+
+func Test_CountSPFLookups(t *testing.T) {
+	r := require.New(t)
+	zone := spfZone{
+		"_spf.mail.dev":        {"v=spf1 include:_netblocks.mail.dev include:_netblocks2.mail.dev ~all"},
+		"_netblocks.mail.dev":  {"v=spf1 ip4:192.0.2.0/24 ~all"},
+		"_netblocks2.mail.dev": {"v=spf1 ip6:2001:db8::/32 ~all", "google-site-verification=abc"},
+		"spf.other.dev":        {"v=spf1 a mx ptr ~all"},
+		"loop.foobar.dev":      {"v=spf1 include:loop.foobar.dev -all"},
+		"two.foobar.dev":       {"v=spf1 -all", "v=spf1 ~all"},
+	}
+
+	terms, err := countSPFLookups(context.Background(), zone.lookup, "1.1.1.1",
+		"v=spf1 ip4:192.0.2.1 a:mail.foobar.dev -mx include:_spf.mail.dev ?include:SPF.Other.dev. exists:%{i}.foobar.dev redirect=_spf.mail.dev")
+	r.NoError(err)
+	r.Equal([]spfTermLookups{
+		{Term: "a:mail.foobar.dev", Lookups: 1},
+		{Term: "-mx", Lookups: 1},
+		{Term: "include:_spf.mail.dev", Lookups: 3},
+		{Term: "?include:SPF.Other.dev.", Lookups: 4},
+		{Term: "exists:%{i}.foobar.dev", Lookups: 1},
+		{Term: "redirect=_spf.mail.dev", Lookups: 3},
+	}, terms)
+
+	terms, err = countSPFLookups(context.Background(), zone.lookup, "1.1.1.1", "v=spf1 ip4:192.0.2.1 -all")
+	r.NoError(err)
+	r.Empty(terms)
+
+	_, err = countSPFLookups(context.Background(), zone.lookup, "1.1.1.1", "v=spf1 include:loop.foobar.dev -all")
+	r.ErrorContains(err, "includes itself")
+	_, err = countSPFLookups(context.Background(), zone.lookup, "1.1.1.1", "v=spf1 include:two.foobar.dev -all")
+	r.ErrorContains(err, "has 2 SPF records")
+	_, err = countSPFLookups(context.Background(), zone.lookup, "1.1.1.1", "v=spf1 include:missing.foobar.dev -all")
+	r.ErrorContains(err, "has 0 SPF records")
+	_, err = countSPFLookups(context.Background(), zone.lookup, "1.1.1.1", "v=spf1 include:down.foobar.dev -all")
+	r.ErrorContains(err, "i/o timeout")
+}
+
+func Test_CountSPFLookupsCIDRLength(t *testing.T) {
+	tests := []struct {
+		term    string
+		lookups int
+	}{
+		{term: "a/24", lookups: 1},
+		{term: "mx/24", lookups: 1},
+		{term: "a:mail.foobar.dev/24", lookups: 1},
+		{term: "mx:foobar.dev/24", lookups: 1},
+		{term: "-a//64", lookups: 1},
+		{term: "mx/24//64", lookups: 1},
+		{term: "ip4:192.0.2.0/24", lookups: 0},
+		{term: "ip6:2001:db8::/32", lookups: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.term, func(t *testing.T) {
+			terms, err := countSPFLookups(context.Background(), spfZone{}.lookup, "1.1.1.1", "v=spf1 "+test.term+" -all")
+			require.NoError(t, err)
+			lookups := 0
+			for _, term := range terms {
+				lookups += term.Lookups
+			}
+			require.Equal(t, test.lookups, lookups)
+		})
+	}
+}
+
+func Test_IsSPF(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isSPF("v=spf1 -all"))
+	r.True(isSPF("V=SPF1"))
+	r.False(isSPF("v=spf10 -all"))
+	r.False(isSPF("v=DMARC1; p=none"))
+	r.False(isSPF(""))
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Numbers of the SvcParamKeys registered by RFC 9460 and RFC 9461, unknown keys are written as keyNNNNN
+var svcParamKeys = map[string]int{
+	"mandatory":       0,
+	"alpn":            1,
+	"no-default-alpn": 2,
+	"port":            3,
+	"ipv4hint":        4,
+	"ech":             5,
+	"ipv6hint":        6,
+	"dohpath":         7,
+	"ohttp":           8,
+}
+
+// Keys that are flags, everything else needs a value
+var svcParamFlags = []string{"no-default-alpn", "ohttp"}
+
+type svcParam struct {
+	Key    string
+	Number int
+	Value  string
+	// HasValue tells a flag apart from a key given an empty value
+	HasValue bool
+}
+
+// svcbContent is the content of an HTTPS or SVCB record: an optional priority, the target name and the
+// service parameters
+type svcbContent struct {
+	Priority string
+	Target   string
+	Params   []svcParam
+}
+
+func isSvcbType(recordType string) bool {
+	return strings.EqualFold(recordType, "HTTPS") || strings.EqualFold(recordType, "SVCB")
+}
+
+// parseSvcbContent parses and validates record content like `1 . alpn="h2,h3" port=8443`. The priority is
+// optional as Porkbun may keep it separately.
+func parseSvcbContent(content string) (svcbContent, error) {
+	fields, err := splitSvcbFields(content)
+	if err != nil {
+		return svcbContent{}, err
+	}
+
+	var parsed svcbContent
+	if len(fields) > 0 {
+		if _, err := strconv.ParseUint(fields[0], 10, 16); err == nil {
+			parsed.Priority = fields[0]
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 0 {
+		return svcbContent{}, fmt.Errorf("a target name is required, use . for the owner name")
+	}
+	parsed.Target = fields[0]
+
+	seen := map[int]bool{}
+	for _, field := range fields[1:] {
+		param, err := parseSvcParam(field)
+		if err != nil {
+			return svcbContent{}, err
+		}
+		if seen[param.Number] {
+			return svcbContent{}, fmt.Errorf("%s is given more than once", param.Key)
+		}
+		seen[param.Number] = true
+		parsed.Params = append(parsed.Params, param)
+	}
+
+	if parsed.Priority == "0" && len(parsed.Params) > 0 {
+		return svcbContent{}, fmt.Errorf("records with priority 0 are in alias mode and can't have parameters")
+	}
+
+	for _, param := range parsed.Params {
+		if param.Key != "mandatory" {
+			continue
+		}
+		for _, key := range strings.Split(param.Value, ",") {
+			number, err := svcParamNumber(key)
+			if err != nil {
+				return svcbContent{}, fmt.Errorf("mandatory: %w", err)
+			}
+			if number == 0 {
+				return svcbContent{}, fmt.Errorf("mandatory can't list itself")
+			}
+			if !seen[number] {
+				return svcbContent{}, fmt.Errorf("mandatory lists %s, which isn't set", key)
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+// splitSvcbFields splits on whitespace outside of double quotes and drops the quotes
+func splitSvcbFields(content string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField, quoted := false, false
+
+	for _, c := range content {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			inField = true
+		case (c == ' ' || c == '\t') && !quoted:
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(c)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
+
+func svcParamNumber(key string) (int, error) {
+	if number, ok := svcParamKeys[key]; ok {
+		return number, nil
+	}
+	if digits, ok := strings.CutPrefix(key, "key"); ok {
+		if number, err := strconv.ParseUint(digits, 10, 16); err == nil && number != 65535 {
+			return int(number), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown parameter %q", key)
+}
+
+func parseSvcParam(field string) (svcParam, error) {
+	key, value, hasValue := strings.Cut(field, "=")
+	key = strings.ToLower(key)
+
+	number, err := svcParamNumber(key)
+	if err != nil {
+		return svcParam{}, err
+	}
+	param := svcParam{Key: key, Number: number, Value: value, HasValue: hasValue}
+
+	isFlag := false
+	for _, flag := range svcParamFlags {
+		isFlag = isFlag || flag == key
+	}
+	switch {
+	case isFlag && (hasValue && value != ""):
+		return svcParam{}, fmt.Errorf("%s doesn't take a value", key)
+	case isFlag:
+		return param, nil
+	case !hasValue || value == "":
+		return svcParam{}, fmt.Errorf("%s needs a value", key)
+	}
+
+	switch key {
+	case "mandatory":
+		// Checked once all parameters are known
+	case "alpn":
+		for _, id := range strings.Split(value, ",") {
+			if id == "" || len(id) > 255 {
+				return svcParam{}, fmt.Errorf("alpn: %q isn't a protocol ID", id)
+			}
+		}
+	case "port":
+		if _, err := strconv.ParseUint(value, 10, 16); err != nil {
+			return svcParam{}, fmt.Errorf("port: %q isn't a port between 0 and 65535", value)
+		}
+	case "ipv4hint", "ipv6hint":
+		for _, address := range strings.Split(value, ",") {
+			ip := net.ParseIP(address)
+			if ip == nil || (ip.To4() != nil) != (key == "ipv4hint") {
+				return svcParam{}, fmt.Errorf("%s: %q isn't an IPv%s address", key, address, key[3:4])
+			}
+		}
+	case "ech":
+		if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+			return svcParam{}, fmt.Errorf("ech: the value isn't base64")
+		}
+	case "dohpath":
+		if !strings.HasPrefix(value, "/") || !strings.Contains(value, "{?dns}") {
+			return svcParam{}, fmt.Errorf("dohpath: %q should be a relative URI template containing {?dns}", value)
+		}
+	}
+	return param, nil
+}
+
+// String writes the content back with the parameters in key order, the order they take on the wire
+func (c svcbContent) String() string {
+	params := append([]svcParam{}, c.Params...)
+	sort.SliceStable(params, func(i, j int) bool { return params[i].Number < params[j].Number })
+
+	fields := []string{c.Target}
+	if c.Priority != "" {
+		fields = append([]string{c.Priority}, fields...)
+	}
+	for _, param := range params {
+		switch {
+		case !param.HasValue:
+			fields = append(fields, param.Key)
+		case strings.ContainsAny(param.Value, " \t"):
+			fields = append(fields, param.Key+`="`+param.Value+`"`)
+		default:
+			fields = append(fields, param.Key+"="+param.Value)
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseSvcbContent(t *testing.T) {
+	r := require.New(t)
+
+	parsed, err := parseSvcbContent(`1 . port=8443 alpn="h2,h3" ipv6hint=2001:db8::1 ipv4hint=192.0.2.1,192.0.2.2 no-default-alpn`)
+	r.NoError(err)
+	r.Equal("1", parsed.Priority)
+	r.Equal(".", parsed.Target)
+	r.Len(parsed.Params, 5)
+	r.Equal("1 . alpn=h2,h3 no-default-alpn port=8443 ipv4hint=192.0.2.1,192.0.2.2 ipv6hint=2001:db8::1", parsed.String())
+
+	// Porkbun may keep the priority out of the content
+	parsed, err = parseSvcbContent("svc.foobar.dev. alpn=h3 key65000=abc")
+	r.NoError(err)
+	r.Equal("", parsed.Priority)
+	r.Equal("svc.foobar.dev. alpn=h3 key65000=abc", parsed.String())
+
+	parsed, err = parseSvcbContent("0 foobar.dev.")
+	r.NoError(err)
+	r.Empty(parsed.Params)
+
+	_, err = parseSvcbContent(`1 . mandatory=alpn,port alpn=h2 port=443 dohpath="/dns-query{?dns}"`)
+	r.NoError(err)
+}
+
+func Test_ParseSvcbContentErrors(t *testing.T) {
+	tests := map[string]string{
+		"":                               "target name is required",
+		"1 . alpn":                       "alpn needs a value",
+		"1 . alpn=h2,,h3":                `alpn: "" isn't a protocol ID`,
+		"1 . port=70000":                 "port between 0 and 65535",
+		"1 . ipv4hint=2001:db8::1":       "isn't an IPv4 address",
+		"1 . ipv6hint=192.0.2.1":         "isn't an IPv6 address",
+		"1 . ech=not%base64":             "isn't base64",
+		"1 . no-default-alpn=yes":        "doesn't take a value",
+		"1 . alpn=h2 alpn=h3":            "more than once",
+		"1 . color=blue":                 `unknown parameter "color"`,
+		"1 . key65535=x":                 `unknown parameter "key65535"`,
+		"1 . mandatory=port alpn=h2":     "mandatory lists port, which isn't set",
+		"1 . mandatory=mandatory":        "can't list itself",
+		"0 . alpn=h2":                    "alias mode",
+		`1 . alpn="h2`:                   "unterminated quote",
+		`1 . dohpath=https://doh/{?dns}`: "relative URI template",
+	}
+	for content, expected := range tests {
+		t.Run(content, func(t *testing.T) {
+			_, err := parseSvcbContent(content)
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Operations whose API calls were retried this often, or waited this long in total, warn about the throttling
+const (
+	throttlingWarnRetries = 3
+	throttlingWarnWait    = time.Minute
+)
+
+// retryStats adds up the retries of every API call made for a single operation. Calls may run concurrently,
+// like the propagation checks, so it synchronizes itself.
+type retryStats struct {
+	mu      sync.Mutex
+	retries int
+	waited  time.Duration
+	lastErr error
+}
+
+type retryStatsKey struct{}
+
+// withRetryStats returns a context retry reports its backoff to, see throttlingDiagnostics
+func withRetryStats(ctx context.Context) (context.Context, *retryStats) {
+	stats := &retryStats{}
+	return context.WithValue(ctx, retryStatsKey{}, stats), stats
+}
+
+// retryStatsFrom returns the stats of the operation ctx belongs to, or nil outside of one
+func retryStatsFrom(ctx context.Context) *retryStats {
+	stats, _ := ctx.Value(retryStatsKey{}).(*retryStats)
+	return stats
+}
+
+func (s *retryStats) add(wait time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+	s.waited += wait
+	s.lastErr = err
+}
+
+// throttlingDiagnostics warns when the API calls of an operation needed many retries or spent long backing off,
+// so slow applies are recognized as rate limiting instead of the provider being slow
+func throttlingDiagnostics(stats *retryStats) diag.Diagnostics {
+	var diags diag.Diagnostics
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	if stats.retries < throttlingWarnRetries && stats.waited < throttlingWarnWait {
+		return diags
+	}
+	diags.AddWarning(
+		"Porkbun API calls were throttled",
+		fmt.Sprintf("The API calls of this operation were retried %d times and waited %s in total, last because of: %s\n\n"+
+			"Porkbun limits how many calls an API key can make. Running Terraform with a lower -parallelism, "+
+			"or fewer workspaces sharing the key at once, is usually faster than waiting for the retries.",
+			stats.retries, stats.waited, stats.lastErr),
+	)
+	return diags
+}
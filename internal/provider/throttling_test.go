@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ThrottlingDiagnostics(t *testing.T) {
+	r := require.New(t)
+	limited := &porkbunapi.Error{StatusCode: 503, Message: "Rate limit exceeded"}
+
+	ctx, stats := withRetryStats(context.Background())
+	calls := 0
+	_, err := retry(ctx, retryPolicy{attempts: 3}, func(ctx context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", limited
+		}
+		return "ok", nil
+	})
+	r.NoError(err)
+	r.Empty(throttlingDiagnostics(stats))
+
+	// Retries add up over the calls of an operation
+	_, err = retry(ctx, retryPolicy{attempts: 2}, func(ctx context.Context) (string, error) {
+		return "", limited
+	})
+	r.Error(err)
+	diags := throttlingDiagnostics(stats)
+	r.Len(diags.Warnings(), 1)
+	r.Contains(diags.Warnings()[0].Detail(), "retried 3 times")
+	r.Contains(diags.Warnings()[0].Detail(), "Rate limit exceeded")
+
+	// A single long wait is enough
+	_, stats = withRetryStats(context.Background())
+	stats.add(90*time.Second, limited)
+	r.Len(throttlingDiagnostics(stats).Warnings(), 1)
+
+	// Calls outside of an operation aren't counted
+	r.Nil(retryStatsFrom(context.Background()))
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans are created through the global tracer provider, which discards them until SetupTracing replaces it
+const tracerName = "github.com/cullenmcdermott/terraform-provider-porkbun/internal/provider"
+
+// SetupTracing installs a tracer provider exporting spans over OTLP/HTTP. The exporter is configured
+// with the standard OTEL_EXPORTER_OTLP_* environment variables. The returned function flushes the
+// spans still buffered and has to be called before the process exits.
+func SetupTracing(ctx context.Context, version string) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "terraform-provider-porkbun"),
+			attribute.String("service.version", version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// startRetrySpan starts the span covering every attempt of a single API call made through retry
+func startRetrySpan(ctx context.Context) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "Porkbun API call")
+}
+
+func endRetrySpan(span trace.Span, attempts int, err error) {
+	span.SetAttributes(attribute.Int("porkbun.attempts", attempts))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		var apiErr *porkbunapi.Error
+		if errors.As(err, &apiErr) {
+			span.SetAttributes(attribute.Int("http.response.status_code", apiErr.StatusCode))
+		}
+	}
+	span.End()
+}
+
+// tracingTransport emits a client span for every request sent to the API. No trace context is
+// propagated in the request headers, Porkbun wouldn't do anything with it.
+type tracingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracer().Start(req.Context(), "Porkbun API request",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("porkbun.endpoint", req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func Test_TracingSpansPerAttempt(t *testing.T) {
+	r := require.New(t)
+	spans := recordSpans(t)
+
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"SUCCESS","yourIp":"127.0.0.1"}`))
+	}))
+	defer ts.Close()
+
+	client := porkbunapi.New("pk1", "sk1")
+	client.BaseURL, _ = url.Parse(ts.URL)
+	client.HTTPClient = newHTTPClient(defaultMaxResponseBytes)
+	_, err := retry(context.Background(), retryPolicy{attempts: 2}, func(ctx context.Context) (string, error) {
+		return client.Ping(ctx)
+	})
+	r.NoError(err)
+
+	ended := spans.Ended()
+	r.Len(ended, 3)
+
+	call := ended[2]
+	r.Equal("Porkbun API call", call.Name())
+	r.Equal(int64(2), spanAttribute(call, "porkbun.attempts").AsInt64())
+	r.Equal(codes.Unset, call.Status().Code)
+
+	for i, status := range []int64{503, 200} {
+		request := ended[i]
+		r.Equal("Porkbun API request", request.Name())
+		r.Equal(call.SpanContext().SpanID(), request.Parent().SpanID())
+		r.Equal("/ping", spanAttribute(request, "porkbun.endpoint").AsString())
+		r.Equal(status, spanAttribute(request, "http.response.status_code").AsInt64())
+	}
+	r.Equal(codes.Error, ended[0].Status().Code)
+}
+
+func Test_TracingRecordsFailedCall(t *testing.T) {
+	r := require.New(t)
+	spans := recordSpans(t)
+
+	_, err := retry(context.Background(), retryPolicy{attempts: 3}, func(ctx context.Context) (string, error) {
+		return "", &porkbunapi.Error{StatusCode: 400, Status: "ERROR", Message: "Invalid domain."}
+	})
+	r.Error(err)
+
+	ended := spans.Ended()
+	r.Len(ended, 1)
+	r.Equal(int64(1), spanAttribute(ended[0], "porkbun.attempts").AsInt64())
+	r.Equal(int64(400), spanAttribute(ended[0], "http.response.status_code").AsInt64())
+	r.Equal(codes.Error, ended[0].Status().Code)
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Every API request is logged under this subsystem, enable it with TF_LOG_PROVIDER_PORKBUN_API
+const apiLogSubsystem = "api"
+
+// Zone listings for busy domains can get large, 10MiB leaves plenty of headroom
+const defaultMaxResponseBytes = 10 << 20
+
+// errResponseTooLarge is returned while reading a response body larger than the configured maximum. Asking
+// again returns the same body, so it is never retried.
+var errResponseTooLarge = errors.New("response body exceeds the maximum")
+
+// newHTTPClient builds the client handed to the Porkbun API client. Responses are capped at maxResponseBytes
+// after decompression and every request is traced and logged.
+func newHTTPClient(maxResponseBytes int64) *http.Client {
+	// Compression needs no setting: with DisableCompression unset the transport asks for gzip and decodes the
+	// body before it reaches the limit
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &limitedTransport{
+			next:     &tracingTransport{next: &loggingTransport{next: transport, usage: newAPIUsage()}},
+			maxBytes: maxResponseBytes,
+		},
+	}
+}
+
+// loggingTransportOf finds the logging transport of a client built by newHTTPClient. Fixtures and the mock
+// replace what is behind it, and it counts the API usage of the client.
+func loggingTransportOf(client *http.Client) (*loggingTransport, error) {
+	limited, ok := client.Transport.(*limitedTransport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected transport %T", client.Transport)
+	}
+	traced, ok := limited.next.(*tracingTransport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected transport %T", limited.next)
+	}
+	logged, ok := traced.next.(*loggingTransport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected transport %T", traced.next)
+	}
+	return logged, nil
+}
+
+type limitedTransport struct {
+	next     http.RoundTripper
+	maxBytes int64
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = &limitedBody{
+		ReadCloser: resp.Body,
+		remaining:  t.maxBytes,
+		maxBytes:   t.maxBytes,
+	}
+	return resp, nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	maxBytes  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Read one more byte so a body of exactly maxBytes isn't reported as too large
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w of %d bytes", errResponseTooLarge, b.maxBytes)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// loggingTransport logs the endpoint, status and duration of each request along with the totals counted in
+// usage so far. Bodies are never logged as they carry the API keys. Fields set on the request context, like
+// the domain, are included.
+type loggingTransport struct {
+	next  http.RoundTripper
+	usage *apiUsage
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), apiLogSubsystem, tflog.WithRootFields(), tflog.WithLevelFromEnv("TF_LOG_PROVIDER_PORKBUN", apiLogSubsystem))
+	ctx = tflog.SubsystemSetField(ctx, apiLogSubsystem, "endpoint", req.URL.Path)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		t.usage.record(0, err)
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, "Porkbun API request failed", t.usage.logFields(map[string]any{
+			"duration_ms": duration.Milliseconds(),
+			"error":       err.Error(),
+		}))
+		return nil, err
+	}
+
+	t.usage.record(resp.StatusCode, nil)
+	tflog.SubsystemDebug(ctx, apiLogSubsystem, "Porkbun API request", t.usage.logFields(map[string]any{
+		"duration_ms": duration.Milliseconds(),
+		"status_code": resp.StatusCode,
+	}))
+	return resp, nil
+}
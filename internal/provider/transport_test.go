@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HTTPClientLimitsResponseSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		maxBytes  int64
+		expectErr bool
+	}{
+		{
+			name:     "underLimit",
+			body:     strings.Repeat("a", 10),
+			maxBytes: 20,
+		},
+		{
+			name:     "exactlyLimit",
+			body:     strings.Repeat("a", 20),
+			maxBytes: 20,
+		},
+		{
+			name:      "overLimit",
+			body:      strings.Repeat("a", 21),
+			maxBytes:  20,
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				_, _ = io.WriteString(w, test.body)
+			}))
+			defer ts.Close()
+
+			resp, err := newHTTPClient(test.maxBytes).Get(ts.URL)
+			r.NoError(err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if test.expectErr {
+				r.ErrorIs(err, errResponseTooLarge)
+				r.False(retryableError(err))
+				return
+			}
+			r.NoError(err)
+			r.Equal(test.body, string(body))
+		})
+	}
+}
+
+func Test_HTTPClientRequestsCompression(t *testing.T) {
+	r := require.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.Equal("gzip", req.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = io.WriteString(gz, `{"status":"SUCCESS"}`)
+		r.NoError(gz.Close())
+	}))
+	defer ts.Close()
+
+	resp, err := newHTTPClient(defaultMaxResponseBytes).Get(ts.URL)
+	r.NoError(err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	r.NoError(err)
+	r.Equal(`{"status":"SUCCESS"}`, string(body))
+}
+
+func Test_HTTPClientLogsRequests(t *testing.T) {
+	r := require.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = tflog.SetField(ctx, "domain", "foobar.dev")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+"/dns/retrieve/foobar.dev", strings.NewReader(`{"apikey":"pk1_secret"}`))
+	r.NoError(err)
+	resp, err := newHTTPClient(defaultMaxResponseBytes).Do(req)
+	r.NoError(err)
+	resp.Body.Close()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	r.NoError(err)
+	r.Len(entries, 1)
+	r.Equal("Porkbun API request", entries[0]["@message"])
+	r.Equal("/dns/retrieve/foobar.dev", entries[0]["endpoint"])
+	r.Equal(float64(503), entries[0]["status_code"])
+	r.Equal("foobar.dev", entries[0]["domain"])
+	r.Contains(entries[0], "duration_ms")
+	r.NotContains(output.String(), "pk1_secret")
+}
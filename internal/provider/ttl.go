@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The range of TTLs Porkbun accepts, in seconds
+const (
+	minTTL = 600
+	maxTTL = 86400
+)
+
+// TTLs zones commonly use, anything else is more likely a typo than a choice
+var commonTTLs = []int{600, 900, 1800, 3600, 7200, 14400, 28800, 43200, 86400}
+
+// parseTTL reads a TTL given in seconds like "3600" or as a duration like "1h" or "90m"
+func parseTTL(value string) (int, error) {
+	duration, err := parseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration%time.Second != 0 {
+		return 0, fmt.Errorf("%q isn't a whole number of seconds", strings.TrimSpace(value))
+	}
+	return int(duration / time.Second), nil
+}
+
+// validateTTL checks value against the range Porkbun accepts and returns a warning for values that
+// aren't common
+func validateTTL(value string) (string, error) {
+	seconds, err := parseTTL(value)
+	if err != nil {
+		return "", err
+	}
+	if seconds < minTTL || seconds > maxTTL {
+		return "", fmt.Errorf("%d seconds is outside of the %d to %d seconds Porkbun accepts", seconds, minTTL, maxTTL)
+	}
+
+	for _, common := range commonTTLs {
+		if seconds == common {
+			return "", nil
+		}
+	}
+	lower, upper := commonTTLs[0], commonTTLs[len(commonTTLs)-1]
+	for _, common := range commonTTLs {
+		if common < seconds {
+			lower = common
+		} else {
+			upper = common
+			break
+		}
+	}
+	return fmt.Sprintf("A TTL of %d seconds is unusual, the closest common values are %d and %d.", seconds, lower, upper), nil
+}
+
+// ttlSeconds is the TTL sent to Porkbun, which only takes seconds
+func ttlSeconds(value types.String) string {
+	seconds, err := parseTTL(value.ValueString())
+	if value.IsNull() || err != nil {
+		return value.ValueString()
+	}
+	return strconv.Itoa(seconds)
+}
+
+// parseDefaultTTLs checks the default_ttls of the provider, returning them in seconds keyed by the upper case record type
+func parseDefaultTTLs(configured map[string]string) (map[string]string, error) {
+	defaults := make(map[string]string, len(configured))
+	for recordType, value := range configured {
+		if _, err := validateTTL(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", recordType, err)
+		}
+		seconds, _ := parseTTL(value)
+		defaults[strings.ToUpper(recordType)] = strconv.Itoa(seconds)
+	}
+	return defaults, nil
+}
+
+// recordTTL is the TTL sent to Porkbun for a record of recordType, the provider's default for the type when
+// the record doesn't set one. Without either Porkbun applies its own default.
+func recordTTL(value types.String, recordType string, defaults map[string]string) string {
+	if value.IsNull() {
+		return defaults[strings.ToUpper(recordType)]
+	}
+	return ttlSeconds(value)
+}
+
+// refreshTTL updates the configured TTL from the API like refreshString, keeping durations like "1h" as
+// configured while Porkbun reports the same number of seconds
+func refreshTTL(current types.String, live string) types.String {
+	if !current.IsNull() {
+		if seconds, err := parseTTL(current.ValueString()); err == nil && strconv.Itoa(seconds) == live {
+			return current
+		}
+	}
+	return refreshString(current, live)
+}
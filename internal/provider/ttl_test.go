@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseTTL(t *testing.T) {
+	tests := map[string]int{
+		"600":    600,
+		" 3600 ": 3600,
+		"1h":     3600,
+		"90m":    5400,
+		"1h30m":  5400,
+		"86400s": 86400,
+	}
+	for value, expected := range tests {
+		seconds, err := parseTTL(value)
+		require.NoError(t, err, value)
+		require.Equal(t, expected, seconds, value)
+	}
+
+	for _, value := range []string{"", "one hour", "1d", "1.5s"} {
+		_, err := parseTTL(value)
+		require.Error(t, err, value)
+	}
+}
+
+func Test_ValidateTTL(t *testing.T) {
+	r := require.New(t)
+
+	warning, err := validateTTL("1h")
+	r.NoError(err)
+	r.Empty(warning)
+
+	warning, err = validateTTL("3000")
+	r.NoError(err)
+	r.Equal("A TTL of 3000 seconds is unusual, the closest common values are 1800 and 3600.", warning)
+
+	_, err = validateTTL("5m")
+	r.ErrorContains(err, "300 seconds is outside of the 600 to 86400 seconds")
+	_, err = validateTTL("604800")
+	r.ErrorContains(err, "outside of")
+	_, err = validateTTL("soon")
+	r.ErrorContains(err, "neither a number of seconds nor a duration")
+}
+
+func Test_RefreshTTL(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("3600", ttlSeconds(types.StringValue("1h")))
+	r.Equal("600", ttlSeconds(types.StringValue("600")))
+	r.Equal("", ttlSeconds(types.StringNull()))
+
+	r.Equal(types.StringValue("1h"), refreshTTL(types.StringValue("1h"), "3600"))
+	r.Equal(types.StringValue("7200"), refreshTTL(types.StringValue("1h"), "7200"))
+	r.Equal(types.StringValue("600"), refreshTTL(types.StringValue("600"), "600"))
+	// The default TTL Porkbun fills in isn't drift
+	r.True(refreshTTL(types.StringNull(), "600").IsNull())
+}
+
+func Test_DefaultTTLs(t *testing.T) {
+	r := require.New(t)
+
+	defaults, err := parseDefaultTTLs(map[string]string{"txt": "600", "A": "1h", "MX": "86400"})
+	r.NoError(err)
+	r.Equal(map[string]string{"TXT": "600", "A": "3600", "MX": "86400"}, defaults)
+
+	_, err = parseDefaultTTLs(map[string]string{"TXT": "300"})
+	r.ErrorContains(err, "TXT: 300 seconds is outside of")
+
+	// A configured ttl wins over the default of the type
+	r.Equal("3600", recordTTL(types.StringNull(), "a", defaults))
+	r.Equal("7200", recordTTL(types.StringValue("2h"), "A", defaults))
+	r.Equal("", recordTTL(types.StringNull(), "CNAME", defaults))
+	r.Equal("", recordTTL(types.StringNull(), "A", nil))
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+)
+
+// zoneImport is what porkbun_zone_import_plan suggests for a single record
+type zoneImport struct {
+	ID       string
+	Address  string
+	ImportID string
+	HCL      string
+}
+
+// zoneImports suggests an import block and resource for each of records, which are the records of domain as
+// the API returns them. Resource names are derived from the record name and type, with a number appended
+// where several records would get the same one.
+func zoneImports(domain string, records []porkbunapi.Record, notesMarker string) []zoneImport {
+	used := map[string]int{}
+	imports := make([]zoneImport, 0, len(records))
+	for _, record := range records {
+		name := relativeRecordName(record.Name, domain)
+		label := resourceLabel(name, record.Type)
+		used[label]++
+		if used[label] > 1 {
+			label = fmt.Sprintf("%s_%d", label, used[label])
+		}
+
+		address := "porkbun_dns_record." + label
+		importID := domain + "/" + record.ID
+
+		attributes := [][2]string{
+			{"domain", domain},
+			{"name", name},
+			{"type", record.Type},
+			{"content", record.Content},
+		}
+		if record.TTL != "" {
+			attributes = append(attributes, [2]string{"ttl", record.TTL})
+		}
+		// The API reports a priority of 0 on every record, it only means something for these
+		if (record.Type == "MX" || record.Type == "SRV") && record.Prio != "" {
+			attributes = append(attributes, [2]string{"prio", record.Prio})
+		}
+		if notes := unstampNotes(record.Notes, notesMarker); notes != "" {
+			attributes = append(attributes, [2]string{"notes", notes})
+		}
+
+		var hcl strings.Builder
+		fmt.Fprintf(&hcl, "import {\n  to = %s\n  id = %s\n}\n\n", address, hclString(importID))
+		fmt.Fprintf(&hcl, "resource \"porkbun_dns_record\" %q {\n", label)
+		for _, attribute := range attributes {
+			fmt.Fprintf(&hcl, "  %-7s = %s\n", attribute[0], hclString(attribute[1]))
+		}
+		hcl.WriteString("}\n")
+
+		imports = append(imports, zoneImport{
+			ID:       record.ID,
+			Address:  address,
+			ImportID: importID,
+			HCL:      hcl.String(),
+		})
+	}
+	return imports
+}
+
+// resourceLabel makes a Terraform resource name like www_cname from a relative record name and type
+func resourceLabel(name string, recordType string) string {
+	if name == "" {
+		name = "apex"
+	}
+
+	var label strings.Builder
+	for _, r := range strings.ToLower(name + "_" + recordType) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			label.WriteRune(r)
+		case r == '*':
+			label.WriteString("wildcard")
+		default:
+			label.WriteRune('_')
+		}
+	}
+
+	// Names have to start with a letter or underscore, labels like _dmarc are fine as they are
+	result := label.String()
+	if result[0] >= '0' && result[0] <= '9' || result[0] == '-' {
+		result = "r" + result
+	}
+	return result
+}
+
+// hclString quotes value as an HCL string, escaping the template sequences HCL would otherwise interpolate
+func hclString(value string) string {
+	quoted := strconv.Quote(value)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ZoneImports(t *testing.T) {
+	r := require.New(t)
+
+	imports := zoneImports("foobar.dev", []porkbunapi.Record{
+		{ID: "1", Name: "foobar.dev", Type: "A", Content: "0.0.0.1", TTL: "600", Prio: "0"},
+		{ID: "2", Name: "foobar.dev", Type: "A", Content: "0.0.0.2", TTL: "600", Prio: "0"},
+		{ID: "3", Name: "foobar.dev", Type: "MX", Content: "mx.foobar.dev", TTL: "3600", Prio: "10", Notes: "mail managed-by:terraform"},
+		{ID: "4", Name: "_dmarc.foobar.dev", Type: "TXT", Content: "v=DMARC1; p=none; rua=mailto:${user}@foobar.dev"},
+		{ID: "5", Name: "*.foobar.dev", Type: "CNAME", Content: "foobar.dev"},
+		{ID: "6", Name: "1.foobar.dev", Type: "A", Content: "0.0.0.1"},
+	}, "managed-by:terraform")
+	r.Len(imports, 6)
+
+	r.Equal(zoneImport{
+		ID:       "1",
+		Address:  "porkbun_dns_record.apex_a",
+		ImportID: "foobar.dev/1",
+		HCL: `import {
+  to = porkbun_dns_record.apex_a
+  id = "foobar.dev/1"
+}
+
+resource "porkbun_dns_record" "apex_a" {
+  domain  = "foobar.dev"
+  name    = ""
+  type    = "A"
+  content = "0.0.0.1"
+  ttl     = "600"
+}
+`,
+	}, imports[0])
+	r.Equal("porkbun_dns_record.apex_a_2", imports[1].Address)
+
+	r.Equal("porkbun_dns_record.apex_mx", imports[2].Address)
+	r.Contains(imports[2].HCL, `  prio    = "10"`)
+	r.Contains(imports[2].HCL, `  notes   = "mail"`)
+
+	r.Equal("porkbun_dns_record._dmarc_txt", imports[3].Address)
+	r.Contains(imports[3].HCL, `"v=DMARC1; p=none; rua=mailto:$${user}@foobar.dev"`)
+	r.Equal("porkbun_dns_record.wildcard_cname", imports[4].Address)
+	r.Equal("porkbun_dns_record.r1_a", imports[5].Address)
+}
@@ -0,0 +1,352 @@
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// zoneRecord is a single resource record from a zone file, shaped like the porkbun_dns_record
+// attributes: Name is relative to the origin and Prio is split out for MX and SRV records.
+type zoneRecord struct {
+	Name    string
+	Type    string
+	TTL     string
+	Content string
+	Prio    string
+}
+
+type zoneToken struct {
+	text   string
+	quoted bool
+}
+
+type zoneLine struct {
+	number int
+	// ownerOmitted is set when the line starts with whitespace, meaning the previous owner applies
+	ownerOmitted bool
+	tokens       []zoneToken
+}
+
+// Record types whose rdata contains domain names that may be written relative to $ORIGIN
+var zoneNameTargets = map[string]bool{
+	"CNAME": true,
+	"ALIAS": true,
+	"NS":    true,
+	"PTR":   true,
+	"MX":    true,
+	"SRV":   true,
+}
+
+// parseZoneFile parses a BIND style zone file. origin is used until a $ORIGIN directive says otherwise.
+// SOA records are skipped as Porkbun manages them itself and $INCLUDE isn't supported.
+func parseZoneFile(zone string, origin string) ([]zoneRecord, error) {
+	lines, err := tokenizeZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	origin = strings.TrimSuffix(strings.ToLower(origin), ".")
+	var defaultTTL, lastOwner string
+	records := []zoneRecord{}
+
+	for _, line := range lines {
+		tokens := line.tokens
+
+		if !line.ownerOmitted && strings.HasPrefix(tokens[0].text, "$") && !tokens[0].quoted {
+			switch strings.ToUpper(tokens[0].text) {
+			case "$ORIGIN":
+				if len(tokens) < 2 {
+					return nil, fmt.Errorf("line %d: $ORIGIN needs a domain name", line.number)
+				}
+				origin, err = absoluteZoneName(tokens[1].text, origin)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line.number, err)
+				}
+			case "$TTL":
+				if len(tokens) < 2 {
+					return nil, fmt.Errorf("line %d: $TTL needs a value", line.number)
+				}
+				ttl, ok := parseZoneTTL(tokens[1].text)
+				if !ok {
+					return nil, fmt.Errorf("line %d: invalid $TTL %q", line.number, tokens[1].text)
+				}
+				defaultTTL = ttl
+			default:
+				return nil, fmt.Errorf("line %d: unsupported directive %s", line.number, tokens[0].text)
+			}
+			continue
+		}
+
+		owner := lastOwner
+		if !line.ownerOmitted {
+			owner, err = absoluteZoneName(tokens[0].text, origin)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.number, err)
+			}
+			tokens = tokens[1:]
+		}
+		if owner == "" {
+			return nil, fmt.Errorf("line %d: record has no owner name", line.number)
+		}
+		lastOwner = owner
+
+		// TTL and class are both optional and may come in either order
+		ttl := defaultTTL
+		for i := 0; i < 2 && len(tokens) > 0; i++ {
+			if t, ok := parseZoneTTL(tokens[0].text); ok {
+				ttl = t
+				tokens = tokens[1:]
+			} else if isZoneClass(tokens[0].text) {
+				tokens = tokens[1:]
+			}
+		}
+
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("line %d: missing record type", line.number)
+		}
+		recordType := strings.ToUpper(tokens[0].text)
+		rdata := tokens[1:]
+		if len(rdata) == 0 {
+			return nil, fmt.Errorf("line %d: %s record has no data", line.number, recordType)
+		}
+
+		if recordType == "SOA" {
+			continue
+		}
+
+		name, err := relativeZoneName(owner, origin)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+
+		record := zoneRecord{
+			Name: name,
+			Type: recordType,
+			TTL:  ttl,
+		}
+
+		if zoneNameTargets[recordType] {
+			last := len(rdata) - 1
+			target, err := absoluteZoneName(rdata[last].text, origin)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.number, err)
+			}
+			rdata[last] = zoneToken{text: target}
+		}
+
+		switch recordType {
+		case "MX", "SRV":
+			if len(rdata) < 2 {
+				return nil, fmt.Errorf("line %d: %s record needs a priority and a target", line.number, recordType)
+			}
+			record.Prio = rdata[0].text
+			record.Content = joinZoneTokens(rdata[1:])
+		case "TXT", "SPF":
+			// Character strings are concatenated, which is how Porkbun stores TXT content
+			var content strings.Builder
+			for _, token := range rdata {
+				content.WriteString(token.text)
+			}
+			record.Content = content.String()
+		default:
+			record.Content = joinZoneTokens(rdata)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func tokenizeZone(zone string) ([]zoneLine, error) {
+	var (
+		lines   []zoneLine
+		current zoneLine
+		token   strings.Builder
+		inToken bool
+		quoted  bool
+		parens  int
+		lineNo  = 1
+	)
+
+	current.number = lineNo
+	atLineStart := true
+
+	flushToken := func() {
+		if inToken {
+			current.tokens = append(current.tokens, zoneToken{text: token.String(), quoted: quoted})
+			token.Reset()
+			inToken = false
+			quoted = false
+		}
+	}
+	flushLine := func() {
+		flushToken()
+		if len(current.tokens) > 0 {
+			lines = append(lines, current)
+		}
+		current = zoneLine{number: lineNo}
+		atLineStart = true
+	}
+
+	runes := []rune(zone)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		if quoted {
+			switch c {
+			case '\\':
+				if i+1 < len(runes) {
+					i++
+					token.WriteRune(runes[i])
+				}
+			case '"':
+				flushToken()
+			case '\n':
+				return nil, fmt.Errorf("line %d: unterminated quoted string", lineNo)
+			default:
+				token.WriteRune(c)
+			}
+			continue
+		}
+
+		switch c {
+		case ';':
+			for i+1 < len(runes) && runes[i+1] != '\n' {
+				i++
+			}
+		case '"':
+			flushToken()
+			inToken = true
+			quoted = true
+		case '(':
+			flushToken()
+			parens++
+		case ')':
+			flushToken()
+			if parens == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNo)
+			}
+			parens--
+		case '\n':
+			lineNo++
+			if parens > 0 {
+				flushToken()
+			} else {
+				flushLine()
+			}
+			continue
+		case ' ', '\t', '\r':
+			if atLineStart && len(current.tokens) == 0 && !inToken {
+				current.ownerOmitted = true
+			}
+			flushToken()
+		default:
+			if c == '\\' && i+1 < len(runes) {
+				i++
+				c = runes[i]
+			}
+			token.WriteRune(c)
+			inToken = true
+		}
+		atLineStart = false
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("line %d: unterminated quoted string", lineNo)
+	}
+	if parens > 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", lineNo)
+	}
+	flushLine()
+
+	return lines, nil
+}
+
+// absoluteZoneName expands name against origin and returns it lowercased without the trailing dot
+func absoluteZoneName(name string, origin string) (string, error) {
+	if name == "@" {
+		if origin == "" {
+			return "", fmt.Errorf("@ used without an origin")
+		}
+		return origin, nil
+	}
+	if strings.HasSuffix(name, ".") {
+		return strings.ToLower(strings.TrimSuffix(name, ".")), nil
+	}
+	if origin == "" {
+		return "", fmt.Errorf("relative name %q used without an origin", name)
+	}
+	return strings.ToLower(name) + "." + origin, nil
+}
+
+func relativeZoneName(name string, origin string) (string, error) {
+	if name == origin {
+		return "", nil
+	}
+	if strings.HasSuffix(name, "."+origin) {
+		return strings.TrimSuffix(name, "."+origin), nil
+	}
+	return "", fmt.Errorf("name %q is outside of the zone %q", name, origin)
+}
+
+func isZoneClass(token string) bool {
+	switch strings.ToUpper(token) {
+	case "IN", "CH", "HS", "CS":
+		return true
+	}
+	return false
+}
+
+// parseZoneTTL accepts plain seconds as well as BIND's unit suffixes like 1h30m
+func parseZoneTTL(token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	var total, current uint64
+	sawDigit := false
+	for _, c := range strings.ToLower(token) {
+		if c >= '0' && c <= '9' {
+			current = current*10 + uint64(c-'0')
+			sawDigit = true
+			continue
+		}
+
+		if !sawDigit {
+			return "", false
+		}
+		switch c {
+		case 's':
+			total += current
+		case 'm':
+			total += current * 60
+		case 'h':
+			total += current * 60 * 60
+		case 'd':
+			total += current * 60 * 60 * 24
+		case 'w':
+			total += current * 60 * 60 * 24 * 7
+		default:
+			return "", false
+		}
+		current = 0
+		sawDigit = false
+	}
+	total += current
+
+	return strconv.FormatUint(total, 10), true
+}
+
+func joinZoneTokens(tokens []zoneToken) string {
+	parts := make([]string, len(tokens))
+	for i, token := range tokens {
+		if token.quoted {
+			parts[i] = strconv.Quote(token.text)
+		} else {
+			parts[i] = token.text
+		}
+	}
+	return strings.Join(parts, " ")
+}
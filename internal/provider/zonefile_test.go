@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseZoneFile(t *testing.T) {
+	r := require.New(t)
+
+	zone := `
+$ORIGIN foobar.dev.
+$TTL 1h
+@       IN SOA ns1.foobar.dev. hostmaster.foobar.dev. (
+            2024010101 ; serial
+            7200 3600 1209600 3600 )
+        IN  NS   curitiba.ns.porkbun.com.
+@  600  IN  A    0.0.0.1
+www     CNAME    @
+        TXT      "v=spf1 include:_spf.example.com" " ~all"
+mail 300 IN MX 10 mx1
+_sip._tcp  SRV 10 60 5060 sip.example.com.
+@          CAA 0 issue "letsencrypt.org"
+*.dev.foobar.dev.  A 10.0.0.1 ; wildcard
+`
+	records, err := parseZoneFile(zone, "ignored.example")
+	r.NoError(err)
+	r.Equal([]zoneRecord{
+		{Name: "", Type: "NS", TTL: "3600", Content: "curitiba.ns.porkbun.com"},
+		{Name: "", Type: "A", TTL: "600", Content: "0.0.0.1"},
+		{Name: "www", Type: "CNAME", TTL: "3600", Content: "foobar.dev"},
+		{Name: "www", Type: "TXT", TTL: "3600", Content: "v=spf1 include:_spf.example.com ~all"},
+		{Name: "mail", Type: "MX", TTL: "300", Content: "mx1.foobar.dev", Prio: "10"},
+		{Name: "_sip._tcp", Type: "SRV", TTL: "3600", Content: "60 5060 sip.example.com", Prio: "10"},
+		{Name: "", Type: "CAA", TTL: "3600", Content: `0 issue "letsencrypt.org"`},
+		{Name: "*.dev", Type: "A", TTL: "3600", Content: "10.0.0.1"},
+	}, records)
+}
+
+func Test_ParseZoneFileUsesGivenOrigin(t *testing.T) {
+	r := require.New(t)
+
+	records, err := parseZoneFile("test A 0.0.0.1\n", "FooBar.dev.")
+	r.NoError(err)
+	r.Equal([]zoneRecord{{Name: "test", Type: "A", Content: "0.0.0.1"}}, records)
+}
+
+func Test_ParseZoneFileErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		zone string
+	}{
+		{name: "noOrigin", zone: "test A 0.0.0.1"},
+		{name: "outsideZone", zone: "test.example.com. A 0.0.0.1"},
+		{name: "unterminatedQuote", zone: `test TXT "abc`},
+		{name: "unbalancedParens", zone: "test TXT ( abc"},
+		{name: "include", zone: "$INCLUDE other.zone"},
+		{name: "missingType", zone: "test 600 IN"},
+		{name: "mxWithoutTarget", zone: "test MX 10"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			origin := "foobar.dev"
+			if test.name == "noOrigin" {
+				origin = ""
+			}
+			_, err := parseZoneFile(test.zone, origin)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_ParseZoneTTL(t *testing.T) {
+	r := require.New(t)
+
+	for token, expected := range map[string]string{
+		"600":   "600",
+		"1h":    "3600",
+		"1h30m": "5400",
+		"1W":    "604800",
+		"2d":    "172800",
+	} {
+		ttl, ok := parseZoneTTL(token)
+		r.True(ok, token)
+		r.Equal(expected, ttl, token)
+	}
+
+	for _, token := range []string{"IN", "A", "h", "10x"} {
+		_, ok := parseZoneTTL(token)
+		r.False(ok, token)
+	}
+}
@@ -0,0 +1,71 @@
+// Package reconcile computes the changes that turn the records a resource has at Porkbun into the ones it
+// should have, apart from the API calls making them so the decisions can be tested on their own.
+//
+// Records are identified by a key chosen by the resource, like the address a record serves, and their
+// Porkbun ID.
+package reconcile
+
+import "sort"
+
+// Record is a record that exists at Porkbun
+type Record struct {
+	Key string
+	ID  string
+}
+
+// Plan lists the changes in the order they should be made: records are created and edited first, and
+// only deleted once all of those succeeded, so a name keeps answering throughout.
+type Plan struct {
+	// Create holds the keys without a record, in the order they were desired
+	Create []string
+	// Edit holds the records that are kept but written again
+	Edit []Record
+	// Keep holds the records that are kept unchanged
+	Keep []Record
+	// Delete holds the records whose key is no longer desired
+	Delete []Record
+}
+
+// Diff plans the changes turning live, record IDs keyed by key, into a record for each of desired. With
+// edit set the records that are kept are written again, for changes the key doesn't cover like the TTL.
+// Desired keys listed more than once get a single record. Edit, Keep and Delete are sorted by key.
+func Diff(live map[string]string, desired []string, edit bool) Plan {
+	var plan Plan
+	wanted := make(map[string]bool, len(desired))
+	for _, key := range desired {
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+
+		id, ok := live[key]
+		switch {
+		case !ok:
+			plan.Create = append(plan.Create, key)
+		case edit:
+			plan.Edit = append(plan.Edit, Record{Key: key, ID: id})
+		default:
+			plan.Keep = append(plan.Keep, Record{Key: key, ID: id})
+		}
+	}
+
+	for key, id := range live {
+		if !wanted[key] {
+			plan.Delete = append(plan.Delete, Record{Key: key, ID: id})
+		}
+	}
+
+	sortRecords(plan.Edit)
+	sortRecords(plan.Keep)
+	sortRecords(plan.Delete)
+	return plan
+}
+
+// Empty reports whether the plan leaves every record as it is
+func (p Plan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Edit) == 0 && len(p.Delete) == 0
+}
+
+func sortRecords(records []Record) {
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+}
@@ -0,0 +1,97 @@
+package reconcile
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Diff(t *testing.T) {
+	tests := []struct {
+		name    string
+		live    map[string]string
+		desired []string
+		edit    bool
+		plan    Plan
+	}{
+		{
+			name:    "create everything",
+			desired: []string{"192.0.2.2", "192.0.2.1"},
+			plan:    Plan{Create: []string{"192.0.2.2", "192.0.2.1"}},
+		},
+		{
+			name:    "unchanged",
+			live:    map[string]string{"192.0.2.1": "1", "192.0.2.2": "2"},
+			desired: []string{"192.0.2.2", "192.0.2.1"},
+			plan:    Plan{Keep: []Record{{Key: "192.0.2.1", ID: "1"}, {Key: "192.0.2.2", ID: "2"}}},
+		},
+		{
+			name:    "edit kept records",
+			live:    map[string]string{"192.0.2.1": "1"},
+			desired: []string{"192.0.2.1", "192.0.2.3"},
+			edit:    true,
+			plan: Plan{
+				Create: []string{"192.0.2.3"},
+				Edit:   []Record{{Key: "192.0.2.1", ID: "1"}},
+			},
+		},
+		{
+			name:    "replace",
+			live:    map[string]string{"192.0.2.1": "1", "192.0.2.2": "2"},
+			desired: []string{"192.0.2.3", "192.0.2.2"},
+			plan: Plan{
+				Create: []string{"192.0.2.3"},
+				Keep:   []Record{{Key: "192.0.2.2", ID: "2"}},
+				Delete: []Record{{Key: "192.0.2.1", ID: "1"}},
+			},
+		},
+		{
+			name: "delete everything",
+			live: map[string]string{"2001:db8::1": "3", "192.0.2.1": "1"},
+			plan: Plan{Delete: []Record{{Key: "192.0.2.1", ID: "1"}, {Key: "2001:db8::1", ID: "3"}}},
+		},
+		{
+			name:    "duplicate desired keys",
+			live:    map[string]string{"192.0.2.1": "1"},
+			desired: []string{"192.0.2.2", "192.0.2.1", "192.0.2.2", "192.0.2.1"},
+			plan: Plan{
+				Create: []string{"192.0.2.2"},
+				Keep:   []Record{{Key: "192.0.2.1", ID: "1"}},
+			},
+		},
+		{
+			name: "nothing",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan := Diff(test.live, test.desired, test.edit)
+			require.Equal(t, test.plan, plan)
+			require.Equal(t, len(test.plan.Create)+len(test.plan.Edit)+len(test.plan.Delete) == 0, plan.Empty())
+		})
+	}
+}
+
+// Benchmark_Diff plans a zone where a tenth of the records is replaced, as when a block of addresses moves
+func Benchmark_Diff(b *testing.B) {
+	for _, size := range []int{1000, 10000} {
+		live := make(map[string]string, size)
+		desired := make([]string, 0, size)
+		for i := 0; i < size; i++ {
+			live[fmt.Sprintf("A host%d", i)] = strconv.Itoa(100000 + i)
+			if i%10 == 0 {
+				desired = append(desired, fmt.Sprintf("A moved%d", i))
+			} else {
+				desired = append(desired, fmt.Sprintf("A host%d", i))
+			}
+		}
+		b.Run(strconv.Itoa(size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Diff(live, desired, false)
+			}
+		})
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"flag"
 	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"strconv"
 
 	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -30,14 +34,48 @@ var (
 
 func main() {
 	var debug bool
+	var pprofAddr string
+	var tracing bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	// Terraform launches the provider itself so the environment variable is usually the only way to set this
+	flag.StringVar(&pprofAddr, "pprof-addr", os.Getenv("PORKBUN_PPROF_ADDR"), "address to serve net/http/pprof on, e.g. localhost:6060, disabled when empty")
+	tracingEnv, _ := strconv.ParseBool(os.Getenv("PORKBUN_OTEL_TRACING"))
+	flag.BoolVar(&tracing, "otel-tracing", tracingEnv, "export a span per Porkbun API call over OTLP, configured with the OTEL_EXPORTER_OTLP_* variables")
 	flag.Parse()
 
+	if pprofAddr != "" {
+		// Serve a mux of its own rather than http.DefaultServeMux, so nothing else a dependency registers
+		// there ends up reachable on this address
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		go func() {
+			log.Println(http.ListenAndServe(pprofAddr, mux))
+		}()
+	}
+
+	if tracing {
+		shutdown, err := provider.SetupTracing(context.Background(), version)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				log.Println(err)
+			}
+		}()
+	}
+
 	opts := providerserver.ServeOpts{
 		// TODO: Update this string with the published name of your provider.
 		Address: "registry.terraform.io/cullenmcdermott/porkbun",
 		Debug:   debug,
+		// Protocol 6 is required for nested attributes, provider functions and write-only arguments
+		ProtocolVersion: 6,
 	}
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
@@ -0,0 +1,199 @@
+// Package porkbunapi is a client for the Porkbun JSON API.
+//
+// It talks to v3 by default, see Version and Client.SetEndpoint for selecting another version or host. It covers the DNS, DNSSEC, nameserver, glue, URL forwarding, domain listing, pricing and SSL endpoints.
+// Every call takes a context, failures are reported as *Error and a RetryPolicy can be set to retry
+// rate limited calls inside the client.
+package porkbunapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is where V3 is served on DefaultHost
+const DefaultBaseURL = "https://api.porkbun.com/api/json/v3/"
+
+// RetryPolicy is consulted after attempt number attempt (starting at 1) failed with err. It returns how long
+// to wait before trying again, or false to give up and return err.
+type RetryPolicy func(attempt int, err error) (time.Duration, bool)
+
+type Client struct {
+	BaseURL *url.URL
+	// Version maps endpoints to their path under BaseURL, set it together with BaseURL through SetEndpoint
+	Version    Version
+	HTTPClient *http.Client
+	// UserAgent is sent with every request when set
+	UserAgent string
+	// Retry is nil by default, meaning every call is attempted once
+	Retry RetryPolicy
+	// UnknownFields turns on strict decoding, it is called after every successful call whose response has
+	// fields the client drops, so changes to the API are noticed. It is nil by default.
+	UnknownFields UnknownFieldsFunc
+
+	apiKey    string
+	secretKey string
+}
+
+type userAgentKey struct{}
+
+// WithUserAgent adds product to the User-Agent of the calls made with the returned context, after
+// Client.UserAgent. It names whoever a shared client is working for, like the module that manages a record.
+func WithUserAgent(ctx context.Context, product string) context.Context {
+	if existing := userAgentFromContext(ctx); existing != "" {
+		product = existing + " " + product
+	}
+	return context.WithValue(ctx, userAgentKey{}, product)
+}
+
+func userAgentFromContext(ctx context.Context) string {
+	product, _ := ctx.Value(userAgentKey{}).(string)
+	return product
+}
+
+func New(apiKey string, secretKey string) *Client {
+	baseURL, _ := url.Parse(DefaultBaseURL)
+	return &Client{
+		BaseURL:    baseURL,
+		Version:    V3,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		apiKey:     apiKey,
+		secretKey:  secretKey,
+	}
+}
+
+// Ping checks the credentials and returns the IP address the request came from
+func (c *Client) Ping(ctx context.Context) (string, error) {
+	var resp struct {
+		YourIP string `json:"yourIp"`
+	}
+	if err := c.do(ctx, nil, &resp, "ping"); err != nil {
+		return "", err
+	}
+	return resp.YourIP, nil
+}
+
+// do posts payload with the credentials added to the endpoint made of elem and decodes a successful response into out
+func (c *Client) do(ctx context.Context, payload map[string]any, out any, elem ...string) error {
+	endpoint := c.BaseURL.JoinPath(c.Version.path(elem)...)
+
+	body := map[string]any{
+		"apikey":       c.apiKey,
+		"secretapikey": c.secretKey,
+	}
+	for k, v := range payload {
+		body[k] = v
+	}
+
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		respBody, err := c.post(ctx, endpoint, reqBody)
+		if err == nil {
+			if out == nil {
+				return nil
+			}
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("failed to unmarshal response: %w", err)
+			}
+			if c.UnknownFields != nil {
+				if fields := unknownFields(respBody, out); len(fields) > 0 {
+					c.UnknownFields(ctx, strings.Join(elem, "/"), fields)
+				}
+			}
+			return nil
+		}
+
+		if c.Retry == nil {
+			return err
+		}
+		wait, ok := c.Retry(attempt, err)
+		if !ok {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) post(ctx context.Context, endpoint *url.URL, reqBody []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if userAgent := strings.TrimSpace(c.UserAgent + " " + userAgentFromContext(ctx)); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var status struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	jsonErr := json.Unmarshal(respBody, &status)
+
+	if resp.StatusCode != http.StatusOK {
+		// Rate limiting and maintenance responses aren't API JSON, keep the raw body for those
+		if jsonErr != nil || status.Status == "" {
+			return nil, newError(resp, endpoint, "", string(respBody))
+		}
+		return nil, newError(resp, endpoint, status.Status, status.Message)
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", jsonErr)
+	}
+	if status.Status != "SUCCESS" {
+		return nil, newError(resp, endpoint, status.Status, status.Message)
+	}
+
+	return respBody, nil
+}
+
+// flexString decodes values the API sends as either a JSON string or a number, like record IDs
+type flexString string
+
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*s = ""
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*s = flexString(str)
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+	*s = flexString(num.String())
+	return nil
+}
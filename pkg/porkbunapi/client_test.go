@@ -0,0 +1,171 @@
+package porkbunapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, baseUrl string) *Client {
+	client := New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	var err error
+	client.BaseURL, err = url.Parse(baseUrl)
+	require.NoError(t, err)
+	return client
+}
+
+func newFakeServer(t *testing.T, opts ...porkbuntest.Option) (*porkbuntest.Server, *Client) {
+	server := porkbuntest.NewServer(opts...)
+	t.Cleanup(server.Close)
+	return server, newTestClient(t, server.URL)
+}
+
+func Test_Ping(t *testing.T) {
+	r := require.New(t)
+	_, client := newFakeServer(t)
+
+	ip, err := client.Ping(context.Background())
+	r.NoError(err)
+	r.Equal("127.0.0.1", ip)
+}
+
+func Test_SendsCredentialsAndUserAgent(t *testing.T) {
+	r := require.New(t)
+
+	var body map[string]any
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userAgent = req.UserAgent()
+		data, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(data, &body)
+		_, _ = io.WriteString(w, `{"status":"SUCCESS","yourIp":"127.0.0.1"}`)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	client.UserAgent = "terraform-provider-porkbun/test"
+	_, err := client.Ping(context.Background())
+	r.NoError(err)
+
+	r.Equal(porkbuntest.APIKey, body["apikey"])
+	r.Equal(porkbuntest.SecretKey, body["secretapikey"])
+	r.Equal("terraform-provider-porkbun/test", userAgent)
+
+	ctx := WithUserAgent(context.Background(), "dns-module/1.0.0")
+	_, err = client.Ping(WithUserAgent(ctx, "ci"))
+	r.NoError(err)
+	r.Equal("terraform-provider-porkbun/test dns-module/1.0.0 ci", userAgent)
+}
+
+func Test_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   Error
+	}{
+		{
+			name:       "errorWithSuccessCode",
+			statusCode: http.StatusOK,
+			body:       `{"status":"ERROR","message":"Invalid domain."}`,
+			expected:   Error{StatusCode: 200, Status: "ERROR", Message: "Invalid domain."},
+		},
+		{
+			name:       "errorWithFailureCode",
+			statusCode: http.StatusBadRequest,
+			body:       `{"status":"ERROR","message":"Invalid record ID."}`,
+			expected:   Error{StatusCode: 400, Status: "ERROR", Message: "Invalid record ID."},
+		},
+		{
+			name:       "notJSON",
+			statusCode: http.StatusServiceUnavailable,
+			body:       "Rate limit exceeded",
+			expected:   Error{StatusCode: 503, Message: "Rate limit exceeded"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := require.New(t)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = io.WriteString(w, test.body)
+			}))
+			defer server.Close()
+
+			_, err := newTestClient(t, server.URL).Ping(context.Background())
+			var apiErr *Error
+			r.True(errors.As(err, &apiErr))
+			test.expected.Endpoint = server.URL + "/ping"
+			r.Equal(test.expected, *apiErr)
+			r.Equal(test.statusCode == http.StatusServiceUnavailable, IsRateLimited(err))
+		})
+	}
+}
+
+func Test_RetryPolicy(t *testing.T) {
+	r := require.New(t)
+	server, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"), porkbuntest.WithRateLimit(1, 50*time.Millisecond))
+
+	var attempts []int
+	client.Retry = func(attempt int, err error) (time.Duration, bool) {
+		attempts = append(attempts, attempt)
+		return 100 * time.Millisecond, IsRateLimited(err) && attempt < 3
+	}
+
+	_, err := client.RetrieveRecords(context.Background(), "foobar.dev")
+	r.NoError(err)
+	_, err = client.RetrieveRecords(context.Background(), "foobar.dev")
+	r.NoError(err)
+	r.Equal([]int{1}, attempts)
+	r.Equal(3, server.Calls("dns/retrieve"))
+}
+
+func Test_RetryPolicyGivesUp(t *testing.T) {
+	r := require.New(t)
+	server, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	var attempts []int
+	client.Retry = func(attempt int, err error) (time.Duration, bool) {
+		attempts = append(attempts, attempt)
+		return 0, IsRateLimited(err)
+	}
+
+	// Errors the policy rejects are returned straight away
+	_, err := client.RetrieveRecords(context.Background(), "unknown.dev")
+	r.Error(err)
+	r.Equal([]int{1}, attempts)
+	r.Equal(1, server.Calls("dns/retrieve"))
+}
+
+func Test_RetryStopsWithContext(t *testing.T) {
+	r := require.New(t)
+	_, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"), porkbuntest.WithRateLimit(1, time.Hour))
+	client.Retry = func(attempt int, err error) (time.Duration, bool) {
+		return time.Hour, true
+	}
+
+	_, err := client.RetrieveRecords(context.Background(), "foobar.dev")
+	r.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.RetrieveRecords(ctx, "foobar.dev")
+	r.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func Test_FlexString(t *testing.T) {
+	r := require.New(t)
+
+	var values []flexString
+	r.NoError(json.Unmarshal([]byte(`["106926659", 106926659, null, "yes"]`), &values))
+	r.Equal([]flexString{"106926659", "106926659", "", "yes"}, values)
+}
@@ -0,0 +1,130 @@
+package porkbunapi
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Record is a DNS record. The API returns Name fully qualified but takes the subdomain alone when writing.
+type Record struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Content string `json:"content,omitempty"`
+	TTL     string `json:"ttl,omitempty"`
+	Prio    string `json:"prio,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID      flexString `json:"id"`
+		Name    string     `json:"name"`
+		Type    string     `json:"type"`
+		Content string     `json:"content"`
+		TTL     flexString `json:"ttl"`
+		Prio    flexString `json:"prio"`
+		Notes   string     `json:"notes"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = Record{
+		ID:      string(raw.ID),
+		Name:    raw.Name,
+		Type:    raw.Type,
+		Content: raw.Content,
+		TTL:     string(raw.TTL),
+		Prio:    string(raw.Prio),
+		Notes:   raw.Notes,
+	}
+	return nil
+}
+
+func (r Record) payload() map[string]any {
+	payload := map[string]any{
+		"name":    r.Name,
+		"type":    r.Type,
+		"content": r.Content,
+	}
+	if r.TTL != "" {
+		payload["ttl"] = r.TTL
+	}
+	if r.Prio != "" {
+		payload["prio"] = r.Prio
+	}
+	if r.Notes != "" {
+		payload["notes"] = r.Notes
+	}
+	return payload
+}
+
+type recordsResponse struct {
+	Records []Record `json:"records"`
+}
+
+// CreateRecord creates record on domain and returns the ID it was given
+func (c *Client) CreateRecord(ctx context.Context, domain string, record Record) (string, error) {
+	var resp struct {
+		ID flexString `json:"id"`
+	}
+	if err := c.do(ctx, record.payload(), &resp, "dns", "create", domain); err != nil {
+		return "", err
+	}
+	return string(resp.ID), nil
+}
+
+// EditRecord replaces the record with the given ID
+func (c *Client) EditRecord(ctx context.Context, domain string, id string, record Record) error {
+	return c.do(ctx, record.payload(), nil, "dns", "edit", domain, id)
+}
+
+// EditRecordsByNameType updates content, TTL, priority and notes of every record matching subdomain and type
+func (c *Client) EditRecordsByNameType(ctx context.Context, domain string, recordType string, subdomain string, record Record) error {
+	payload := record.payload()
+	delete(payload, "name")
+	delete(payload, "type")
+	return c.do(ctx, payload, nil, "dns", "editByNameType", domain, recordType, subdomain)
+}
+
+func (c *Client) DeleteRecord(ctx context.Context, domain string, id string) error {
+	return c.do(ctx, nil, nil, "dns", "delete", domain, id)
+}
+
+// DeleteRecordsByNameType deletes every record matching subdomain and type, an empty subdomain is the apex
+func (c *Client) DeleteRecordsByNameType(ctx context.Context, domain string, recordType string, subdomain string) error {
+	return c.do(ctx, nil, nil, "dns", "deleteByNameType", domain, recordType, subdomain)
+}
+
+// RetrieveRecords returns every record of domain
+func (c *Client) RetrieveRecords(ctx context.Context, domain string) ([]Record, error) {
+	var resp recordsResponse
+	if err := c.do(ctx, nil, &resp, "dns", "retrieve", domain); err != nil {
+		return nil, err
+	}
+	return resp.Records, nil
+}
+
+// RetrieveRecord returns a single record, the second return value is false if there is no record with that ID
+func (c *Client) RetrieveRecord(ctx context.Context, domain string, id string) (Record, bool, error) {
+	var resp recordsResponse
+	if err := c.do(ctx, nil, &resp, "dns", "retrieve", domain, id); err != nil {
+		return Record{}, false, err
+	}
+	for _, record := range resp.Records {
+		if record.ID == id {
+			return record, true, nil
+		}
+	}
+	return Record{}, false, nil
+}
+
+// RetrieveRecordsByNameType returns the records matching subdomain and type, an empty subdomain is the apex
+func (c *Client) RetrieveRecordsByNameType(ctx context.Context, domain string, recordType string, subdomain string) ([]Record, error) {
+	var resp recordsResponse
+	if err := c.do(ctx, nil, &resp, "dns", "retrieveByNameType", domain, recordType, subdomain); err != nil {
+		return nil, err
+	}
+	return resp.Records, nil
+}
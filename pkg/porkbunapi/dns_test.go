@@ -0,0 +1,59 @@
+package porkbunapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordLifecycle(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	server, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	id, err := client.CreateRecord(ctx, "foobar.dev", Record{Name: "www", Type: "A", Content: "0.0.0.1", Notes: "web"})
+	r.NoError(err)
+	r.NotEmpty(id)
+
+	record, ok, err := client.RetrieveRecord(ctx, "foobar.dev", id)
+	r.NoError(err)
+	r.True(ok)
+	r.Equal(Record{ID: id, Name: "www.foobar.dev", Type: "A", Content: "0.0.0.1", TTL: "600", Prio: "0", Notes: "web"}, record)
+
+	r.NoError(client.EditRecord(ctx, "foobar.dev", id, Record{Name: "www", Type: "A", Content: "0.0.0.2", TTL: "3600"}))
+	r.Equal("0.0.0.2", server.Records("foobar.dev")[0].Content)
+	r.Equal("3600", server.Records("foobar.dev")[0].TTL)
+
+	r.NoError(client.DeleteRecord(ctx, "foobar.dev", id))
+	_, ok, err = client.RetrieveRecord(ctx, "foobar.dev", id)
+	r.NoError(err)
+	r.False(ok)
+
+	_, err = client.RetrieveRecords(ctx, "unknown.dev")
+	r.Error(err)
+}
+
+func Test_RecordsByNameType(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	server, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev",
+		porkbuntest.Record{Name: "", Type: "TXT", Content: "one"},
+		porkbuntest.Record{Name: "", Type: "TXT", Content: "two"},
+		porkbuntest.Record{Name: "www", Type: "TXT", Content: "three"},
+	))
+
+	records, err := client.RetrieveRecordsByNameType(ctx, "foobar.dev", "TXT", "")
+	r.NoError(err)
+	r.Len(records, 2)
+
+	r.NoError(client.EditRecordsByNameType(ctx, "foobar.dev", "TXT", "www", Record{Content: "edited"}))
+	records, err = client.RetrieveRecordsByNameType(ctx, "foobar.dev", "TXT", "www")
+	r.NoError(err)
+	r.Len(records, 1)
+	r.Equal("edited", records[0].Content)
+
+	r.NoError(client.DeleteRecordsByNameType(ctx, "foobar.dev", "TXT", ""))
+	r.Len(server.Records("foobar.dev"), 1)
+}
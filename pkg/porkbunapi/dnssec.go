@@ -0,0 +1,104 @@
+package porkbunapi
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+)
+
+// DNSSECRecord is a DS record published at the registry. The KeyData fields are only needed by registries
+// that take the DNSKEY instead of a digest.
+type DNSSECRecord struct {
+	KeyTag          string
+	Alg             string
+	DigestType      string
+	Digest          string
+	MaxSigLife      string
+	KeyDataFlags    string
+	KeyDataProtocol string
+	KeyDataAlgo     string
+	KeyDataPubKey   string
+}
+
+type dnssecRecordJSON struct {
+	KeyTag          flexString `json:"keyTag"`
+	Alg             flexString `json:"alg"`
+	DigestType      flexString `json:"digestType"`
+	Digest          string     `json:"digest"`
+	MaxSigLife      flexString `json:"maxSigLife"`
+	KeyDataFlags    flexString `json:"keyDataFlags"`
+	KeyDataProtocol flexString `json:"keyDataProtocol"`
+	KeyDataAlgo     flexString `json:"keyDataAlgo"`
+	KeyDataPubKey   string     `json:"keyDataPubKey"`
+}
+
+func (r *DNSSECRecord) UnmarshalJSON(data []byte) error {
+	var raw dnssecRecordJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = DNSSECRecord{
+		KeyTag:          string(raw.KeyTag),
+		Alg:             string(raw.Alg),
+		DigestType:      string(raw.DigestType),
+		Digest:          raw.Digest,
+		MaxSigLife:      string(raw.MaxSigLife),
+		KeyDataFlags:    string(raw.KeyDataFlags),
+		KeyDataProtocol: string(raw.KeyDataProtocol),
+		KeyDataAlgo:     string(raw.KeyDataAlgo),
+		KeyDataPubKey:   raw.KeyDataPubKey,
+	}
+	return nil
+}
+
+func (c *Client) CreateDNSSECRecord(ctx context.Context, domain string, record DNSSECRecord) error {
+	payload := map[string]any{
+		"keyTag":     record.KeyTag,
+		"alg":        record.Alg,
+		"digestType": record.DigestType,
+		"digest":     record.Digest,
+	}
+	optional := map[string]string{
+		"maxSigLife":      record.MaxSigLife,
+		"keyDataFlags":    record.KeyDataFlags,
+		"keyDataProtocol": record.KeyDataProtocol,
+		"keyDataAlgo":     record.KeyDataAlgo,
+		"keyDataPubKey":   record.KeyDataPubKey,
+	}
+	for k, v := range optional {
+		if v != "" {
+			payload[k] = v
+		}
+	}
+	return c.do(ctx, payload, nil, "dns", "createDnssecRecord", domain)
+}
+
+// GetDNSSECRecords returns the DS records of domain ordered by key tag
+func (c *Client) GetDNSSECRecords(ctx context.Context, domain string) ([]DNSSECRecord, error) {
+	// Records are keyed by key tag, and the API sends an empty list instead of an empty object
+	var resp struct {
+		Records json.RawMessage `json:"records"`
+	}
+	if err := c.do(ctx, nil, &resp, "dns", "getDnssecRecords", domain); err != nil {
+		return nil, err
+	}
+
+	byTag := map[string]DNSSECRecord{}
+	if len(resp.Records) > 0 && resp.Records[0] == '{' {
+		if err := json.Unmarshal(resp.Records, &byTag); err != nil {
+			return nil, err
+		}
+	}
+
+	records := make([]DNSSECRecord, 0, len(byTag))
+	for _, record := range byTag {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].KeyTag < records[j].KeyTag })
+	return records, nil
+}
+
+func (c *Client) DeleteDNSSECRecord(ctx context.Context, domain string, keyTag string) error {
+	return c.do(ctx, nil, nil, "dns", "deleteDnssecRecord", domain, keyTag)
+}
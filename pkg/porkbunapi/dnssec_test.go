@@ -0,0 +1,32 @@
+package porkbunapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DNSSECRecords(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	_, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	records, err := client.GetDNSSECRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Empty(records)
+
+	r.NoError(client.CreateDNSSECRecord(ctx, "foobar.dev", DNSSECRecord{KeyTag: "64087", Alg: "13", DigestType: "2", Digest: "abcd"}))
+	r.NoError(client.CreateDNSSECRecord(ctx, "foobar.dev", DNSSECRecord{KeyTag: "12345", Alg: "13", DigestType: "2", Digest: "ef01"}))
+
+	records, err = client.GetDNSSECRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Equal([]DNSSECRecord{
+		{KeyTag: "12345", Alg: "13", DigestType: "2", Digest: "ef01"},
+		{KeyTag: "64087", Alg: "13", DigestType: "2", Digest: "abcd"},
+	}, records)
+
+	r.NoError(client.DeleteDNSSECRecord(ctx, "foobar.dev", "64087"))
+	r.Error(client.DeleteDNSSECRecord(ctx, "foobar.dev", "64087"))
+}
@@ -0,0 +1,209 @@
+package porkbunapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// listAll returns at most this many domains per call
+const domainPageSize = 1000
+
+type Domain struct {
+	Domain       string
+	Status       string
+	TLD          string
+	CreateDate   string
+	ExpireDate   string
+	SecurityLock bool
+	WhoisPrivacy bool
+	AutoRenew    bool
+	NotLocal     bool
+	Labels       []Label
+}
+
+type Label struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Color string `json:"color"`
+}
+
+func (d *Domain) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Domain       string     `json:"domain"`
+		Status       string     `json:"status"`
+		TLD          string     `json:"tld"`
+		CreateDate   string     `json:"createDate"`
+		ExpireDate   string     `json:"expireDate"`
+		SecurityLock flexString `json:"securityLock"`
+		WhoisPrivacy flexString `json:"whoisPrivacy"`
+		AutoRenew    flexString `json:"autoRenew"`
+		NotLocal     flexString `json:"notLocal"`
+		Labels       []struct {
+			ID    flexString `json:"id"`
+			Title string     `json:"title"`
+			Color string     `json:"color"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*d = Domain{
+		Domain:       raw.Domain,
+		Status:       raw.Status,
+		TLD:          raw.TLD,
+		CreateDate:   raw.CreateDate,
+		ExpireDate:   raw.ExpireDate,
+		SecurityLock: raw.SecurityLock == "1",
+		WhoisPrivacy: raw.WhoisPrivacy == "1",
+		AutoRenew:    raw.AutoRenew == "1",
+		NotLocal:     raw.NotLocal == "1",
+	}
+	for _, label := range raw.Labels {
+		d.Labels = append(d.Labels, Label{ID: string(label.ID), Title: label.Title, Color: label.Color})
+	}
+	return nil
+}
+
+// ListDomains returns every domain in the account, following the API's pagination
+func (c *Client) ListDomains(ctx context.Context) ([]Domain, error) {
+	var domains []Domain
+	for start := 0; ; start += domainPageSize {
+		var resp struct {
+			Domains []Domain `json:"domains"`
+		}
+		payload := map[string]any{
+			"start":         fmt.Sprint(start),
+			"includeLabels": "yes",
+		}
+		if err := c.do(ctx, payload, &resp, "domain", "listAll"); err != nil {
+			return nil, err
+		}
+
+		domains = append(domains, resp.Domains...)
+		if len(resp.Domains) < domainPageSize {
+			return domains, nil
+		}
+	}
+}
+
+// GetNameservers returns the authoritative nameservers set at the registry
+func (c *Client) GetNameservers(ctx context.Context, domain string) ([]string, error) {
+	var resp struct {
+		NS []string `json:"ns"`
+	}
+	if err := c.do(ctx, nil, &resp, "domain", "getNs", domain); err != nil {
+		return nil, err
+	}
+	return resp.NS, nil
+}
+
+func (c *Client) UpdateNameservers(ctx context.Context, domain string, nameservers []string) error {
+	return c.do(ctx, map[string]any{"ns": nameservers}, nil, "domain", "updateNs", domain)
+}
+
+// URLForward is a redirect served by Porkbun. IncludePath and Wildcard are "yes" or "no" as in the API.
+type URLForward struct {
+	ID          string `json:"id,omitempty"`
+	Subdomain   string `json:"subdomain"`
+	Location    string `json:"location"`
+	Type        string `json:"type"`
+	IncludePath string `json:"includePath"`
+	Wildcard    string `json:"wildcard"`
+}
+
+func (f *URLForward) UnmarshalJSON(data []byte) error {
+	type plain URLForward
+	var raw struct {
+		plain
+		ID flexString `json:"id"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*f = URLForward(raw.plain)
+	f.ID = string(raw.ID)
+	return nil
+}
+
+// AddURLForward creates a forward, the API doesn't return its ID so callers have to look it up with GetURLForwards
+func (c *Client) AddURLForward(ctx context.Context, domain string, forward URLForward) error {
+	payload := map[string]any{
+		"subdomain":   forward.Subdomain,
+		"location":    forward.Location,
+		"type":        forward.Type,
+		"includePath": forward.IncludePath,
+		"wildcard":    forward.Wildcard,
+	}
+	return c.do(ctx, payload, nil, "domain", "addUrlForward", domain)
+}
+
+func (c *Client) GetURLForwards(ctx context.Context, domain string) ([]URLForward, error) {
+	var resp struct {
+		Forwards []URLForward `json:"forwards"`
+	}
+	if err := c.do(ctx, nil, &resp, "domain", "getUrlForwarding", domain); err != nil {
+		return nil, err
+	}
+	return resp.Forwards, nil
+}
+
+func (c *Client) DeleteURLForward(ctx context.Context, domain string, id string) error {
+	return c.do(ctx, nil, nil, "domain", "deleteUrlForward", domain, id)
+}
+
+// GlueRecord holds the addresses registered for a nameserver host under the domain
+type GlueRecord struct {
+	// Host is fully qualified, e.g. ns1.example.com
+	Host string
+	IPv4 []string
+	IPv6 []string
+}
+
+// CreateGlueRecord registers ips for the host subdomain of domain, e.g. ns1
+func (c *Client) CreateGlueRecord(ctx context.Context, domain string, subdomain string, ips []string) error {
+	return c.do(ctx, map[string]any{"ips": ips}, nil, "domain", "createGlue", domain, subdomain)
+}
+
+// UpdateGlueRecord replaces every address of the host subdomain of domain
+func (c *Client) UpdateGlueRecord(ctx context.Context, domain string, subdomain string, ips []string) error {
+	return c.do(ctx, map[string]any{"ips": ips}, nil, "domain", "updateGlue", domain, subdomain)
+}
+
+func (c *Client) DeleteGlueRecord(ctx context.Context, domain string, subdomain string) error {
+	return c.do(ctx, nil, nil, "domain", "deleteGlue", domain, subdomain)
+}
+
+func (c *Client) GetGlueRecords(ctx context.Context, domain string) ([]GlueRecord, error) {
+	// Hosts come back as [host, {"v4": [...], "v6": [...]}] pairs
+	var resp struct {
+		Hosts [][]json.RawMessage `json:"hosts"`
+	}
+	if err := c.do(ctx, nil, &resp, "domain", "getGlue", domain); err != nil {
+		return nil, err
+	}
+
+	records := make([]GlueRecord, 0, len(resp.Hosts))
+	for _, host := range resp.Hosts {
+		if len(host) != 2 {
+			return nil, fmt.Errorf("failed to unmarshal response: unexpected glue host entry of length %d", len(host))
+		}
+
+		var record GlueRecord
+		var ips struct {
+			V4 []string `json:"v4"`
+			V6 []string `json:"v6"`
+		}
+		if err := json.Unmarshal(host[0], &record.Host); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if err := json.Unmarshal(host[1], &ips); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		record.IPv4 = ips.V4
+		record.IPv6 = ips.V6
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,74 @@
+package porkbunapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ListDomains(t *testing.T) {
+	r := require.New(t)
+	_, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"), porkbuntest.WithDomain("example.com"))
+
+	domains, err := client.ListDomains(context.Background())
+	r.NoError(err)
+	r.Len(domains, 2)
+	r.Equal("example.com", domains[0].Domain)
+	r.Equal("com", domains[0].TLD)
+	r.True(domains[0].SecurityLock)
+	r.False(domains[0].NotLocal)
+}
+
+func Test_Nameservers(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	server, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	ns, err := client.GetNameservers(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Len(ns, 4)
+
+	r.NoError(client.UpdateNameservers(ctx, "foobar.dev", []string{"ns1.example.com", "ns2.example.com"}))
+	r.Equal([]string{"ns1.example.com", "ns2.example.com"}, server.Nameservers("foobar.dev"))
+}
+
+func Test_URLForwards(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	_, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	forward := URLForward{Subdomain: "blog", Location: "https://example.com", Type: "temporary", IncludePath: "no", Wildcard: "yes"}
+	r.NoError(client.AddURLForward(ctx, "foobar.dev", forward))
+
+	forwards, err := client.GetURLForwards(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Len(forwards, 1)
+	r.NotEmpty(forwards[0].ID)
+	forward.ID = forwards[0].ID
+	r.Equal(forward, forwards[0])
+
+	r.NoError(client.DeleteURLForward(ctx, "foobar.dev", forward.ID))
+	forwards, err = client.GetURLForwards(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Empty(forwards)
+}
+
+func Test_GlueRecords(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	_, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	r.NoError(client.CreateGlueRecord(ctx, "foobar.dev", "ns1", []string{"192.0.2.1"}))
+	r.NoError(client.UpdateGlueRecord(ctx, "foobar.dev", "ns1", []string{"192.0.2.2", "2001:db8::2"}))
+
+	glue, err := client.GetGlueRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Equal([]GlueRecord{{Host: "ns1.foobar.dev", IPv4: []string{"192.0.2.2"}, IPv6: []string{"2001:db8::2"}}}, glue)
+
+	r.NoError(client.DeleteGlueRecord(ctx, "foobar.dev", "ns1"))
+	glue, err = client.GetGlueRecords(ctx, "foobar.dev")
+	r.NoError(err)
+	r.Empty(glue)
+}
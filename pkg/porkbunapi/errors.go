@@ -0,0 +1,89 @@
+package porkbunapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Kinds of failures an *Error can be matched against with errors.Is, so callers don't need to know the
+// status codes and messages Porkbun uses for them
+var (
+	// ErrRateLimited means too many requests were made, the call can be repeated after a while
+	ErrRateLimited = errors.New("rate limited")
+	// ErrAuth means the keys were rejected or API access isn't enabled for the account or domain
+	ErrAuth = errors.New("not authorized")
+	// ErrNotFound means the domain or record the call refers to doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrValidation means the request was rejected, repeating it unchanged won't help
+	ErrValidation = errors.New("invalid request")
+)
+
+// Error is returned whenever the API doesn't answer with a status of SUCCESS
+type Error struct {
+	// StatusCode is the HTTP status code, the API also reports some failures with a 200
+	StatusCode int
+	// Status is the status field of the response, empty when the body wasn't API JSON
+	Status  string
+	Message string
+	// Endpoint is the URL that was called, it never contains the keys
+	Endpoint string
+	// RequestID identifies the response for Porkbun support, taken from the X-Request-Id or Cloudflare's
+	// CF-Ray header. Empty when the response had neither.
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("porkbun API returned HTTP %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("porkbun API error (HTTP %d): %s", e.StatusCode, e.Message)
+}
+
+// requestIDHeaders are the response headers that can identify a request, in order of preference
+var requestIDHeaders = []string{"X-Request-Id", "CF-Ray"}
+
+func newError(resp *http.Response, endpoint *url.URL, status string, message string) *Error {
+	e := &Error{StatusCode: resp.StatusCode, Status: status, Message: message, Endpoint: endpoint.String()}
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+	return e
+}
+
+// Is matches the error against ErrRateLimited, ErrAuth, ErrNotFound and ErrValidation
+func (e *Error) Is(target error) bool {
+	kind := e.kind()
+	return kind != nil && kind == target
+}
+
+// kind classifies the error. Porkbun reports most failures as a 400, or a 200 with a status of ERROR,
+// so the message has to be looked at as well.
+func (e *Error) kind() error {
+	message := strings.ToLower(e.Message)
+	switch {
+	case e.StatusCode == http.StatusServiceUnavailable || e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden,
+		strings.Contains(message, "invalid api key"),
+		strings.Contains(message, "not opted in to api access"):
+		return ErrAuth
+	case e.StatusCode == http.StatusNotFound,
+		strings.HasPrefix(message, "invalid domain"),
+		strings.HasPrefix(message, "invalid record id"):
+		return ErrNotFound
+	case e.StatusCode == http.StatusBadRequest || e.Status == "ERROR":
+		return ErrValidation
+	}
+	return nil
+}
+
+// IsRateLimited reports whether err is the 503 Porkbun answers with when too many requests were made
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
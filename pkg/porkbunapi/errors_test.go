@@ -0,0 +1,83 @@
+package porkbunapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		err      *Error
+		expected error
+	}{
+		{&Error{StatusCode: 503, Message: "Rate limit exceeded"}, ErrRateLimited},
+		{&Error{StatusCode: 429, Message: "Too Many Requests"}, ErrRateLimited},
+		{&Error{StatusCode: 400, Status: "ERROR", Message: "Invalid API key. (002)"}, ErrAuth},
+		{&Error{StatusCode: 200, Status: "ERROR", Message: "Domain is not opted in to API access."}, ErrAuth},
+		{&Error{StatusCode: 403, Status: "ERROR", Message: "Forbidden"}, ErrAuth},
+		{&Error{StatusCode: 400, Status: "ERROR", Message: "Invalid domain."}, ErrNotFound},
+		{&Error{StatusCode: 400, Status: "ERROR", Message: "Invalid record ID."}, ErrNotFound},
+		{&Error{StatusCode: 400, Status: "ERROR", Message: "Type and content are required."}, ErrValidation},
+		{&Error{StatusCode: 200, Status: "ERROR", Message: "Edit error: We were unable to edit the DNS record."}, ErrValidation},
+		{&Error{StatusCode: 500, Message: "Internal Server Error"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.err.Message, func(t *testing.T) {
+			r := require.New(t)
+
+			// Wrapping keeps the kind
+			err := fmt.Errorf("calling API: %w", test.err)
+			for _, kind := range []error{ErrRateLimited, ErrAuth, ErrNotFound, ErrValidation} {
+				r.Equal(kind == test.expected, errors.Is(err, kind), "kind %s", kind)
+			}
+		})
+	}
+}
+
+func Test_FakeServerErrorKinds(t *testing.T) {
+	r := require.New(t)
+	server, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+	ctx := context.Background()
+
+	_, err := client.RetrieveRecords(ctx, "unknown.dev")
+	r.ErrorIs(err, ErrNotFound)
+
+	_, err = client.CreateRecord(ctx, "foobar.dev", Record{Name: "www"})
+	r.ErrorIs(err, ErrValidation)
+
+	client = New("pk1_wrong", "sk1_wrong")
+	client.BaseURL = newTestClient(t, server.URL).BaseURL
+	_, err = client.Ping(ctx)
+	r.ErrorIs(err, ErrAuth)
+}
+
+func Test_ErrorRequestContext(t *testing.T) {
+	r := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("CF-Ray", "8c1f2b3a4d5e6f70-AMS")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"status":"ERROR","message":"Invalid domain."}`)
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := newTestClient(t, server.URL).RetrieveRecords(context.Background(), "foobar.dev")
+	var apiErr *Error
+	r.ErrorAs(err, &apiErr)
+	r.Equal(server.URL+"/dns/retrieve/foobar.dev", apiErr.Endpoint)
+	r.Equal("8c1f2b3a4d5e6f70-AMS", apiErr.RequestID)
+	r.Equal("Invalid domain.", apiErr.Message)
+
+	// The fake server sends no request ID
+	_, client := newFakeServer(t)
+	_, err = client.RetrieveRecords(context.Background(), "unknown.dev")
+	r.ErrorAs(err, &apiErr)
+	r.Empty(apiErr.RequestID)
+	r.Contains(apiErr.Endpoint, "/dns/retrieve/unknown.dev")
+}
@@ -0,0 +1,50 @@
+package porkbunapi_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/pkg/porkbunapi"
+)
+
+// Point a dynamic DNS name at the address the request came from
+func Example() {
+	ctx := context.Background()
+	client := porkbunapi.New(os.Getenv("PORKBUN_API_KEY"), os.Getenv("PORKBUN_SECRET_KEY"))
+
+	ip, err := client.Ping(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	records, err := client.RetrieveRecordsByNameType(ctx, "example.com", "A", "home")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(records) > 0 && records[0].Content == ip {
+		return
+	}
+
+	if err := client.EditRecordsByNameType(ctx, "example.com", "A", "home", porkbunapi.Record{Content: ip, TTL: "600"}); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("home.example.com now points at", ip)
+}
+
+// Retry rate limited calls a few times with a growing wait
+func ExampleRetryPolicy() {
+	client := porkbunapi.New(os.Getenv("PORKBUN_API_KEY"), os.Getenv("PORKBUN_SECRET_KEY"))
+	client.Retry = func(attempt int, err error) (time.Duration, bool) {
+		if attempt >= 5 || !porkbunapi.IsRateLimited(err) {
+			return 0, false
+		}
+		return time.Duration(attempt) * 10 * time.Second, true
+	}
+
+	if _, err := client.Ping(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
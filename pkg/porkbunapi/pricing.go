@@ -0,0 +1,70 @@
+package porkbunapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// Pricing is the default price of a TLD in USD, as decimal strings like "9.73"
+type Pricing struct {
+	Registration string `json:"registration"`
+	Renewal      string `json:"renewal"`
+	Transfer     string `json:"transfer"`
+	// Coupons are the promotions running for the TLD, keyed by what they apply to like "registration"
+	Coupons Coupons `json:"coupons,omitempty"`
+	// SpecialType is set for TLDs with their own pricing rules, like "handshake"
+	SpecialType string `json:"specialType,omitempty"`
+}
+
+// Coupon is a promotion code Porkbun advertises for a TLD
+type Coupon struct {
+	Code       string `json:"code"`
+	MaxPerUser int    `json:"max_per_user"`
+	// FirstYearOnly is "yes" when only the first year is discounted
+	FirstYearOnly string `json:"first_year_only"`
+	// Type is "amount" for a discount in USD or "percent"
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+}
+
+// Coupons are keyed by what they apply to. The API sends an empty list rather than an object when a TLD
+// has none.
+type Coupons map[string]Coupon
+
+func (c *Coupons) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("[]")) {
+		*c = nil
+		return nil
+	}
+	return json.Unmarshal(data, (*map[string]Coupon)(c))
+}
+
+// OnSale reports whether registering the TLD costs less than usual, either because a coupon is running
+// or the first year is priced below the renewal
+func (p Pricing) OnSale() bool {
+	if _, ok := p.Coupons["registration"]; ok {
+		return true
+	}
+	registration, err := strconv.ParseFloat(p.Registration, 64)
+	if err != nil {
+		return false
+	}
+	renewal, err := strconv.ParseFloat(p.Renewal, 64)
+	if err != nil {
+		return false
+	}
+	return registration < renewal
+}
+
+// GetPricing returns the default pricing of every supported TLD keyed by the TLD without a leading dot
+func (c *Client) GetPricing(ctx context.Context) (map[string]Pricing, error) {
+	var resp struct {
+		Pricing map[string]Pricing `json:"pricing"`
+	}
+	if err := c.do(ctx, nil, &resp, "pricing", "get"); err != nil {
+		return nil, err
+	}
+	return resp.Pricing, nil
+}
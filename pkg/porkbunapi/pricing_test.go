@@ -0,0 +1,35 @@
+package porkbunapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetPricing(t *testing.T) {
+	r := require.New(t)
+	_, client := newFakeServer(t)
+
+	pricing, err := client.GetPricing(context.Background())
+	r.NoError(err)
+	r.Equal(Pricing{Registration: "10.81", Renewal: "10.81", Transfer: "10.81"}, pricing["dev"])
+
+	// An empty coupon list comes back as no coupons
+	r.Nil(pricing["com"].Coupons)
+	r.False(pricing["com"].OnSale())
+
+	r.Equal(Coupons{"registration": {Code: "AWESOMENESS", MaxPerUser: 1, FirstYearOnly: "yes", Type: "amount", Amount: 1}}, pricing["xyz"].Coupons)
+	r.True(pricing["xyz"].OnSale())
+}
+
+func Test_PricingOnSale(t *testing.T) {
+	r := require.New(t)
+
+	r.True(Pricing{Registration: "1.99", Renewal: "10.81"}.OnSale())
+	r.False(Pricing{Registration: "10.81", Renewal: "10.81"}.OnSale())
+	r.False(Pricing{Registration: "", Renewal: "10.81"}.OnSale())
+	r.True(Pricing{Registration: "10.81", Renewal: "10.81", Coupons: Coupons{"registration": {Code: "SALE"}}}.OnSale())
+	// Transfer coupons don't make registering cheaper
+	r.False(Pricing{Registration: "10.81", Renewal: "10.81", Coupons: Coupons{"transfer": {Code: "MOVE"}}}.OnSale())
+}
@@ -0,0 +1,20 @@
+package porkbunapi
+
+import (
+	"context"
+)
+
+// SSLBundle is the free certificate Porkbun issues for a domain, all fields are PEM encoded
+type SSLBundle struct {
+	CertificateChain string `json:"certificatechain"`
+	PrivateKey       string `json:"privatekey"`
+	PublicKey        string `json:"publickey"`
+}
+
+func (c *Client) RetrieveSSLBundle(ctx context.Context, domain string) (SSLBundle, error) {
+	var bundle SSLBundle
+	if err := c.do(ctx, nil, &bundle, "ssl", "retrieve", domain); err != nil {
+		return SSLBundle{}, err
+	}
+	return bundle, nil
+}
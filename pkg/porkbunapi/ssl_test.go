@@ -0,0 +1,23 @@
+package porkbunapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RetrieveSSLBundle(t *testing.T) {
+	r := require.New(t)
+	_, client := newFakeServer(t, porkbuntest.WithDomain("foobar.dev"))
+
+	bundle, err := client.RetrieveSSLBundle(context.Background(), "foobar.dev")
+	r.NoError(err)
+	r.Equal(2, strings.Count(bundle.CertificateChain, "BEGIN CERTIFICATE"))
+	r.Contains(bundle.PrivateKey, "PRIVATE KEY")
+
+	_, err = client.RetrieveSSLBundle(context.Background(), "unknown.dev")
+	r.Error(err)
+}
@@ -0,0 +1,115 @@
+package porkbunapi
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownFieldsFunc is given the endpoint and the paths of the fields of a successful response the client
+// doesn't decode, like "records[].newAttribute"
+type UnknownFieldsFunc func(ctx context.Context, endpoint string, fields []string)
+
+// Every response carries these, they are decoded by the client before the endpoint's own fields
+var envelopeFields = []string{"status", "message"}
+
+// unknownFields returns the paths of the fields of data that decoding it into out drops. Fields are matched
+// like encoding/json does, case-insensitively by their tag or name.
+func unknownFields(data []byte, out any) []string {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	if obj, ok := value.(map[string]any); ok {
+		for _, field := range envelopeFields {
+			delete(obj, field)
+		}
+	}
+
+	found := map[string]bool{}
+	collectUnknownFields(value, reflect.TypeOf(out), "", found)
+
+	fields := make([]string, 0, len(found))
+	for field := range found {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// collectUnknownFields adds the paths of the fields of value that type t has no field for to found. Values
+// of other types, like flexString, decode themselves and are taken as they are.
+func collectUnknownFields(value any, t reflect.Type, path string, found map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, v := range obj {
+			ft, ok := fields[strings.ToLower(key)]
+			if !ok {
+				found[joinFieldPath(path, key)] = true
+				continue
+			}
+			collectUnknownFields(v, ft, joinFieldPath(path, key), found)
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := value.([]any)
+		// json.RawMessage takes any JSON as it is
+		if !ok || t.Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for _, v := range arr {
+			collectUnknownFields(v, t.Elem(), path+"[]", found)
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		for _, v := range obj {
+			collectUnknownFields(v, t.Elem(), joinFieldPath(path, "*"), found)
+		}
+	}
+}
+
+// jsonFields maps the lower case JSON names of the fields of struct type t to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, ft := range jsonFields(field.Type) {
+				fields[name] = ft
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
+
+func joinFieldPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,62 @@
+package porkbunapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UnknownFields(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, `{"status":"SUCCESS","cloudflare":"enabled","records":[
+			{"id":"1","name":"www.foobar.dev","type":"A","content":"192.0.2.1","ttl":600,"prio":"0","notes":"","proxied":false},
+			{"id":"2","name":"foobar.dev","type":"MX","content":"mx.foobar.dev","ttl":"600","prio":10,"Notes":null,"proxied":true,"tags":["mail"]}
+		]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := newTestClient(t, server.URL)
+	var endpoints []string
+	var unknown [][]string
+	client.UnknownFields = func(ctx context.Context, endpoint string, fields []string) {
+		endpoints = append(endpoints, endpoint)
+		unknown = append(unknown, fields)
+	}
+
+	records, err := client.RetrieveRecords(context.Background(), "foobar.dev")
+	r.NoError(err)
+	r.Len(records, 2)
+	r.Equal([]string{"dns/retrieve/foobar.dev"}, endpoints)
+	r.Equal([][]string{{"cloudflare", "records[].proxied", "records[].tags"}}, unknown)
+}
+
+func Test_UnknownFieldsOfKnownResponses(t *testing.T) {
+	r := require.New(t)
+
+	var pricing struct {
+		Pricing map[string]Pricing `json:"pricing"`
+	}
+	r.Equal([]string{"pricing.*.premium"}, unknownFields([]byte(`{"status":"SUCCESS","pricing":{
+		"dev":{"registration":"10.81","renewal":"12.87","transfer":"10.81","coupons":[],"premium":false}
+	}}`), &pricing))
+
+	var glue struct {
+		Hosts [][]json.RawMessage `json:"hosts"`
+	}
+	r.Empty(unknownFields([]byte(`{"status":"SUCCESS","hosts":[["ns1.foobar.dev",{"v4":["192.0.2.1"],"v6":[]}]]}`), &glue))
+
+	var domains struct {
+		Domains []Domain `json:"domains"`
+	}
+	r.Empty(unknownFields([]byte(`{"status":"SUCCESS","domains":[
+		{"domain":"foobar.dev","status":"ACTIVE","tld":"dev","createDate":"2020-01-01","expireDate":"2030-01-01","securityLock":"1",
+		 "whoisPrivacy":1,"autoRenew":0,"notLocal":0,"labels":[{"id":"1","title":"prod","color":"#ff0000"}]}
+	]}`), &domains))
+}
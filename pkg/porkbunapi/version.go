@@ -0,0 +1,67 @@
+package porkbunapi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Hosts the API is served from. The IPv4 host has no AAAA record, so requests sent to it always come
+// from the IPv4 address of the caller, which is what Ping reports then.
+const (
+	DefaultHost = "api.porkbun.com"
+	IPv4Host    = "api-ipv4.porkbun.com"
+)
+
+// Version is a version of the JSON API. It decides the path the API is served under and where each
+// endpoint lives in it, so the methods of Client keep working when endpoints move between versions.
+type Version struct {
+	Name string
+
+	// endpoints maps the v3 path of an endpoint, like "dns/retrieveByNameType", to its path in this
+	// version. Endpoints that didn't move are left out.
+	endpoints map[string][]string
+}
+
+// V3 is the current version of the API and the one New uses
+var V3 = Version{Name: "v3"}
+
+var versions = map[string]Version{
+	V3.Name: V3,
+}
+
+// LookupVersion returns the version called name
+func LookupVersion(name string) (Version, error) {
+	version, ok := versions[strings.ToLower(name)]
+	if !ok {
+		var names []string
+		for name := range versions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return Version{}, fmt.Errorf("unsupported API version %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return version, nil
+}
+
+// BaseURL is where the version is served on host
+func (v Version) BaseURL(host string) *url.URL {
+	return &url.URL{Scheme: "https", Host: host, Path: "/api/json/" + v.Name + "/"}
+}
+
+// path translates the elements of a v3 endpoint path, the longest moved prefix wins
+func (v Version) path(elem []string) []string {
+	for n := len(elem); n > 0; n-- {
+		if moved, ok := v.endpoints[strings.Join(elem[:n], "/")]; ok {
+			return append(append([]string{}, moved...), elem[n:]...)
+		}
+	}
+	return elem
+}
+
+// SetEndpoint points the client at version of the API served on host
+func (c *Client) SetEndpoint(host string, version Version) {
+	c.BaseURL = version.BaseURL(host)
+	c.Version = version
+}
@@ -0,0 +1,61 @@
+package porkbunapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cullenmcdermott/terraform-provider-porkbun/internal/porkbuntest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LookupVersion(t *testing.T) {
+	r := require.New(t)
+
+	version, err := LookupVersion("V3")
+	r.NoError(err)
+	r.Equal(V3, version)
+	r.Equal(DefaultBaseURL, version.BaseURL(DefaultHost).String())
+	r.Equal("https://api-ipv4.porkbun.com/api/json/v3/", version.BaseURL(IPv4Host).String())
+
+	_, err = LookupVersion("v2")
+	r.ErrorContains(err, `unsupported API version "v2", expected one of v3`)
+}
+
+func Test_VersionPath(t *testing.T) {
+	r := require.New(t)
+
+	version := Version{Name: "v4", endpoints: map[string][]string{
+		"dns":                    {"zones"},
+		"dns/retrieveByNameType": {"records", "lookup"},
+	}}
+
+	r.Equal([]string{"records", "lookup", "foobar.dev", "A"}, version.path([]string{"dns", "retrieveByNameType", "foobar.dev", "A"}))
+	r.Equal([]string{"zones", "create", "foobar.dev"}, version.path([]string{"dns", "create", "foobar.dev"}))
+	r.Equal([]string{"ping"}, version.path([]string{"ping"}))
+	r.Equal([]string{"ping"}, V3.path([]string{"ping"}))
+}
+
+func Test_ClientUsesVersion(t *testing.T) {
+	r := require.New(t)
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path)
+		w.Write([]byte(`{"status": "SUCCESS", "yourIp": "192.0.2.1"}`))
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	r.NoError(err)
+
+	client := New(porkbuntest.APIKey, porkbuntest.SecretKey)
+	client.SetEndpoint(serverURL.Host, Version{Name: "v4", endpoints: map[string][]string{"ping": {"auth", "check"}}})
+	// The test server doesn't speak TLS
+	client.BaseURL.Scheme = "http"
+
+	_, err = client.Ping(context.Background())
+	r.NoError(err)
+	r.Equal([]string{"/api/json/v4/auth/check"}, paths)
+}